@@ -2,7 +2,6 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"net/http"
 
@@ -10,28 +9,34 @@ import (
 )
 
 func (rest *Rest) addEvent(w http.ResponseWriter, r *http.Request) {
-	data, err := ioutil.ReadAll(r.Body)
+	newEvent, err := rest.createEvent(r)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid Data Format"))
+		rest.sendError(w, r, err)
 		return
 	}
+
+	rest.sendData(w, newEvent)
+}
+
+// createEvent stores the event sent in the request body, it is shared by all api versions.
+func (rest *Rest) createEvent(r *http.Request) (structs.Event, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.Event{}, structs.NewValidationError("Invalid Data Format")
+	}
 	var e structs.EventCreation
 	if err = json.Unmarshal(data, &e); err != nil {
-		rest.sendError(w, http.StatusBadRequest, err)
-		return
+		return structs.Event{}, structs.WrapError(structs.KindValidation, err)
 	}
 	user, _, _ := r.BasicAuth()
-	loc, err := rest.service.Users.GetUserLocation(user)
-	event, err := structs.CreateEvent(loc, e)
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, err)
-		return
+		return structs.Event{}, err
 	}
-	newEvent, err := rest.service.Events.AddEvent(loc, event)
+	event, err := structs.CreateEvent(loc, e)
 	if err != nil {
-		rest.sendError(w, http.StatusInternalServerError, err)
-		return
+		return structs.Event{}, err
 	}
-
-	rest.sendData(w, newEvent)
+	event.Owner = user
+	return rest.service.Events.AddEvent(r.Context(), loc, event)
 }
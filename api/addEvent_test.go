@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/service"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestCreateEventNeedsTheLocationOfTheUser(t *testing.T) {
+	eventsRepo, _ := db.NewMapRepository()
+	usersRepo, _ := db.NewUsersInMemoryRepository()
+	rest := &Rest{service: service.NewService(&service.Config{EventsRepo: eventsRepo, UsersRepo: usersRepo})}
+
+	r := httptest.NewRequest("POST", "/events", strings.NewReader(
+		`{"name":"Lunch","start":"2030-01-01T12:00:00Z","end":"2030-01-01T13:00:00Z"}`))
+	r.SetBasicAuth("ghost", "pw")
+	if _, err := rest.createEvent(r); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("expected the failed location lookup of kind %v, got %v", structs.KindNotFound, err)
+	}
+	if stored := eventsRepo.GetLastUsedId(); stored != 0 {
+		t.Errorf("expected no event stored without the location of the user, got %d", stored)
+	}
+}
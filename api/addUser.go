@@ -2,39 +2,35 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"net/http"
 
 	"github.com/dkucheru/Calendar/structs"
-	"github.com/go-playground/validator/v10"
 )
 
 func (rest *Rest) addUser(w http.ResponseWriter, r *http.Request) {
+	user, err := rest.registerUser(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, user.Username+" "+user.Location.String())
+}
 
+func (rest *Rest) registerUser(r *http.Request) (structs.HashedInfo, error) {
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid Data Format"))
-		return
+		return structs.HashedInfo{}, structs.NewValidationError("Invalid Data Format")
 	}
 
 	var newUser structs.CreateUser
 	err = json.Unmarshal(data, &newUser)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid Data Format"))
-		return
+		return structs.HashedInfo{}, structs.NewValidationError("Invalid Data Format")
 	}
-	validate := validator.New()
-	err = validate.Struct(newUser)
-	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, errors.New("validator : Invalid Data Format"))
-		return
+	if err = structs.Validate(newUser, "validator : Invalid Data Format"); err != nil {
+		return structs.HashedInfo{}, err
 	}
 
-	user, err := rest.service.Users.AddUser(newUser)
-	if err != nil {
-		rest.sendError(w, http.StatusInternalServerError, err)
-		return
-	}
-	rest.sendData(w, user.Username+" "+user.Location.String())
+	return rest.service.Users.AddUser(r.Context(), newUser)
 }
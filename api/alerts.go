@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/gorilla/mux"
+)
+
+// alertActions are the reactions to an alert, they are the last part of its path.
+const alertActions = "{action:snooze|ack|dismiss}"
+
+func (rest *Rest) allAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := rest.findAlerts(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, alerts)
+}
+
+func (rest *Rest) alertAction(w http.ResponseWriter, r *http.Request) {
+	alert, err := rest.reactToAlert(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, alert)
+}
+
+func (rest *Rest) allAlertsV2(w http.ResponseWriter, r *http.Request) {
+	alerts, err := rest.findAlerts(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: alerts})
+}
+
+func (rest *Rest) alertActionV2(w http.ResponseWriter, r *http.Request) {
+	alert, err := rest.reactToAlert(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: alert})
+}
+
+// findAlerts lists the alerts of the requesting user, ?state= picks the ones in one state.
+func (rest *Rest) findAlerts(r *http.Request) ([]structs.AlertInstance, error) {
+	state, err := structs.ParseAlertState(r.URL.Query().Get("state"))
+	if err != nil {
+		return nil, err
+	}
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return nil, err
+	}
+	return rest.service.Alerts.GetAlerts(r.Context(), user, state, loc)
+}
+
+// reactToAlert snoozes, acknowledges or dismisses an alert of the requesting user,
+// snoozing takes the duration in ?for=, e.g. 10m.
+func (rest *Rest) reactToAlert(r *http.Request) (structs.AlertInstance, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return structs.AlertInstance{}, structs.NewValidationError("Invalid Data Format")
+	}
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.AlertInstance{}, err
+	}
+
+	switch mux.Vars(r)["action"] {
+	case "snooze":
+		d, err := time.ParseDuration(r.URL.Query().Get("for"))
+		if err != nil {
+			return structs.AlertInstance{}, structs.NewValidationError("error parsing snooze duration",
+				structs.FieldError{Field: "for", Message: "must be a duration like 10m or 1h"})
+		}
+		return rest.service.Alerts.Snooze(r.Context(), user, id, d, loc)
+	case "ack":
+		return rest.service.Alerts.Acknowledge(r.Context(), user, id, loc)
+	default:
+		return rest.service.Alerts.Dismiss(r.Context(), user, id, loc)
+	}
+}
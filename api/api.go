@@ -3,53 +3,135 @@ package api
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/metrics"
 	"github.com/dkucheru/Calendar/service"
+	"github.com/dkucheru/Calendar/structs"
 	"github.com/gorilla/mux"
 )
 
+type Config struct {
+	Address         string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	ShutdownTimeout time.Duration
+	RequestTimeout  time.Duration
+	Version         string
+	Metrics         *metrics.Registry
+}
+
+// StatusClientClosedRequest is reported when the client went away before
+// the response was ready. It is not a standard code, but is widely used for this.
+const StatusClientClosedRequest = 499
+
 type Rest struct {
-	address  string
-	mux      *mux.Router
-	listener net.Listener
-	service  *service.Service
-	server   *http.Server
+	conf        Config
+	mux         *mux.Router
+	listener    net.Listener
+	service     *service.Service
+	server      *http.Server
+	diagnostics diagnostics
+	metrics     *httpMetrics
 }
 
-func New(address string, service *service.Service) *Rest {
+func New(conf *Config, service *service.Service) *Rest {
 	rest := &Rest{
-		address: address,
-		service: service,
+		conf:        *conf,
+		service:     service,
+		diagnostics: diagnostics{startedAt: time.Now()},
 	}
+	registry := conf.Metrics
+	if registry == nil {
+		registry = metrics.NewRegistry()
+	}
+	rest.metrics = newHTTPMetrics(registry)
 
 	api := mux.NewRouter()
-	api.HandleFunc("/users", rest.addUser).Methods("POST")
-	api.Handle("/users/{username}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.changeTimezone))).Methods("PUT")
+	api.HandleFunc("/healthz", rest.healthz).Methods("GET")
+	api.HandleFunc("/readyz", rest.readyz).Methods("GET")
+	api.Handle("/metrics", registry.Handler()).Methods("GET")
+	api.Handle("/debug/info", rest.BasicAuthMiddleware(http.HandlerFunc(rest.debugInfo))).Methods("GET")
+
+	// v2 is matched before the unprefixed v1 routes
+	v2 := api.PathPrefix("/v2").Subrouter()
+	v2.Use(apiVersionMiddleware(2))
+	rest.routesV2(v2)
+
+	// v1 is served both with and without the prefix, existing clients use the root
+	rest.routesV1(api.PathPrefix("/v1").Subrouter())
+	rest.routesV1(api)
+
+	rest.mux = api
 
-	api.Handle("/events", rest.BasicAuthMiddleware(http.HandlerFunc(rest.addEvent))).Methods("POST")
+	return rest
+}
+
+func (rest *Rest) routesV1(api *mux.Router) {
+	api.HandleFunc("/users", rest.idempotent(rest.addUser)).Methods("POST")
+	api.Handle("/users/{username}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.changeTimezone))).Methods("PUT")
+	api.Handle("/users/{username}/availability", rest.BasicAuthMiddleware(http.HandlerFunc(rest.getAvailability))).Methods("GET")
+	api.Handle("/users/{username}/availability", rest.BasicAuthMiddleware(http.HandlerFunc(rest.putAvailability))).Methods("PUT")
+	api.Handle("/scheduling/suggest", rest.BasicAuthMiddleware(http.HandlerFunc(rest.suggestSlots))).Methods("POST")
+	api.Handle("/users/{username}/booking-pages", rest.BasicAuthMiddleware(http.HandlerFunc(rest.allBookingPages))).Methods("GET")
+	api.Handle("/users/{username}/booking-pages/{slug}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.putBookingPage))).Methods("PUT")
+	api.Handle("/users/{username}/booking-pages/{slug}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.deleteBookingPage))).Methods("DELETE")
+
+	// booking pages are public, guests book without an account
+	api.HandleFunc("/book/{slug}", rest.openSlots).Methods("GET")
+	api.HandleFunc("/book/{slug}", rest.idempotent(rest.book)).Methods("POST")
+	api.HandleFunc("/book/{slug}/bookings/{token}", rest.cancelBooking).Methods("DELETE")
+
+	api.Handle("/resources", rest.BasicAuthMiddleware(rest.idempotent(rest.addResource))).Methods("POST")
+	api.Handle("/resources", rest.BasicAuthMiddleware(http.HandlerFunc(rest.allResources))).Methods("GET")
+	api.Handle("/resources/{id}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.getResource))).Methods("GET")
+
+	api.Handle("/events", rest.BasicAuthMiddleware(rest.idempotent(rest.addEvent))).Methods("POST")
 	api.Handle("/events", rest.BasicAuthMiddleware(http.HandlerFunc(rest.allEvents))).Methods("GET")
+	api.Handle("/events/quick", rest.BasicAuthMiddleware(rest.idempotent(rest.quickAddEvent))).Methods("POST")
 	api.Handle("/events/{id}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.deleteEvent))).Methods("DELETE")
 	api.Handle("/events/{id}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.updateEvent))).Methods("PUT")
+	api.Handle("/events/{id}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.patchEvent))).Methods("PATCH")
 
-	rest.mux = api
+	api.Handle("/views/"+calendarViews, rest.BasicAuthMiddleware(http.HandlerFunc(rest.calendarView))).Methods("GET")
+	api.Handle("/reports/time", rest.BasicAuthMiddleware(http.HandlerFunc(rest.timeReport))).Methods("GET")
+	api.Handle("/holidays", rest.BasicAuthMiddleware(http.HandlerFunc(rest.allHolidays))).Methods("GET")
 
-	return rest
+	api.Handle("/tasks", rest.BasicAuthMiddleware(rest.idempotent(rest.addTask))).Methods("POST")
+	api.Handle("/tasks", rest.BasicAuthMiddleware(http.HandlerFunc(rest.allTasks))).Methods("GET")
+	api.Handle("/tasks/{id}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.getTask))).Methods("GET")
+	api.Handle("/tasks/{id}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.updateTask))).Methods("PUT")
+	api.Handle("/tasks/{id}", rest.BasicAuthMiddleware(http.HandlerFunc(rest.deleteTask))).Methods("DELETE")
+
+	api.Handle("/alerts", rest.BasicAuthMiddleware(http.HandlerFunc(rest.allAlerts))).Methods("GET")
+	api.Handle("/alerts/{id}/"+alertActions, rest.BasicAuthMiddleware(http.HandlerFunc(rest.alertAction))).Methods("POST")
 }
 
 func (rest *Rest) BasicAuthMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, pass, ok := r.BasicAuth()
-		err := rest.service.Users.CheckPassword(user, pass)
-		if !ok || err != nil {
-			w.Header().Set("WWW-Authenticate", `Basic realm="Please enter your username and password for this site"`)
-			w.WriteHeader(401)
-			w.Write([]byte("Unauthorised.\n"))
-			log.Println(err.Error())
+		err := rest.service.Users.CheckPassword(r.Context(), user, pass)
+		if !ok {
+			err = fmt.Errorf("%w : credentials are missing", structs.ErrUnauthorized)
+		}
+		if err != nil {
+			if structs.KindOf(err) == structs.KindUnauthorized {
+				reason := "invalid_credentials"
+				if !ok {
+					reason = "missing_credentials"
+				}
+				rest.metrics.authFailures.Inc(reason)
+				logger.FromContext(r.Context()).WithFields(logger.Fields{
+					"user":   user,
+					"reason": reason,
+				}).Warnf("authentication failed : %v", err)
+			}
+			rest.sendError(w, r, err)
 			return
 		}
 		handler(w, r)
@@ -57,7 +139,7 @@ func (rest *Rest) BasicAuthMiddleware(handler http.HandlerFunc) http.HandlerFunc
 }
 
 func (rest *Rest) Listen() (err error) {
-	rest.listener, err = net.Listen("tcp", rest.address)
+	rest.listener, err = net.Listen("tcp", rest.conf.Address)
 	if err != nil {
 		return err
 	}
@@ -65,7 +147,9 @@ func (rest *Rest) Listen() (err error) {
 	r := http.NewServeMux()
 	r.Handle("/", rest.mux)
 	rest.server = &http.Server{
-		Handler: r,
+		Handler:      r,
+		ReadTimeout:  rest.conf.ReadTimeout,
+		WriteTimeout: rest.conf.WriteTimeout,
 	}
 
 	rest.setupMiddleware()
@@ -73,20 +157,31 @@ func (rest *Rest) Listen() (err error) {
 }
 
 func (rest *Rest) Stop() {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), rest.conf.ShutdownTimeout)
 	defer cancel()
 	if err := rest.server.Shutdown(ctx); err != nil {
-		log.Printf("Could not shut down server correctly: %v\n", err)
+		logger.Errorf("could not shut down server correctly : %v", err)
 		os.Exit(1)
 	}
 }
 
 func (rest *Rest) setupMiddleware() {
-	rest.mux.Use(func(handler http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			log.Println("new request", r.RequestURI)
+	rest.mux.Use(rest.accessLogMiddleware)
+	rest.mux.Use(rest.metricsMiddleware)
+	rest.mux.Use(rest.timeoutMiddleware)
+}
+
+// timeoutMiddleware gives every request a deadline, which is passed
+// down to the service and repository calls through the request context.
+func (rest *Rest) timeoutMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rest.conf.RequestTimeout <= 0 {
 			handler.ServeHTTP(w, r)
-		})
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), rest.conf.RequestTimeout)
+		defer cancel()
+		handler.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
@@ -95,24 +190,6 @@ type Response struct {
 	Data   interface{}
 }
 
-func (rest *Rest) sendError(w http.ResponseWriter, statusCode int, err error) {
-	w.WriteHeader(statusCode)
-
-	bytes, err := json.Marshal(Response{
-		Status: statusCode,
-		Data:   err.Error(),
-	})
-
-	if err != nil {
-		log.Println(err)
-	}
-
-	_, err = w.Write(bytes)
-	if err != nil {
-		log.Println(err)
-	}
-}
-
 func (rest *Rest) sendData(w http.ResponseWriter, data interface{}) {
 	bytes, err := json.Marshal(Response{
 		Status: 1,
@@ -120,11 +197,11 @@ func (rest *Rest) sendData(w http.ResponseWriter, data interface{}) {
 	})
 
 	if err != nil {
-		log.Println(err)
+		logger.Errorf("writing response : %v", err)
 	}
 
 	_, err = w.Write(bytes)
 	if err != nil {
-		log.Println(err)
+		logger.Errorf("writing response : %v", err)
 	}
 }
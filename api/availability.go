@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func (rest *Rest) getAvailability(w http.ResponseWriter, r *http.Request) {
+	a, err := rest.readAvailability(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, a)
+}
+
+func (rest *Rest) putAvailability(w http.ResponseWriter, r *http.Request) {
+	a, err := rest.replaceAvailability(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, a)
+}
+
+func (rest *Rest) getAvailabilityV2(w http.ResponseWriter, r *http.Request) {
+	a, err := rest.readAvailability(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: a})
+}
+
+func (rest *Rest) putAvailabilityV2(w http.ResponseWriter, r *http.Request) {
+	a, err := rest.replaceAvailability(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: a})
+}
+
+func (rest *Rest) readAvailability(r *http.Request) (structs.Availability, error) {
+	username, err := ownUser(r)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	return rest.service.Users.GetAvailability(r.Context(), username)
+}
+
+// replaceAvailability stores the profile sent in the request body as a whole.
+func (rest *Rest) replaceAvailability(r *http.Request) (structs.Availability, error) {
+	username, err := ownUser(r)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.Availability{}, structs.NewValidationError("Invalid Data Format")
+	}
+	var a structs.Availability
+	if err = json.Unmarshal(data, &a); err != nil {
+		return structs.Availability{}, structs.WrapError(structs.KindValidation, err)
+	}
+	return rest.service.Users.UpdateAvailability(r.Context(), username, a)
+}
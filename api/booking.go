@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/gorilla/mux"
+)
+
+func (rest *Rest) putBookingPage(w http.ResponseWriter, r *http.Request) {
+	page, err := rest.saveBookingPage(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, page)
+}
+
+func (rest *Rest) allBookingPages(w http.ResponseWriter, r *http.Request) {
+	pages, err := rest.findBookingPages(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, pages)
+}
+
+func (rest *Rest) deleteBookingPage(w http.ResponseWriter, r *http.Request) {
+	if err := rest.removeBookingPage(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, "Deleted Booking Page")
+}
+
+func (rest *Rest) openSlots(w http.ResponseWriter, r *http.Request) {
+	slots, err := rest.findOpenSlots(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, slots)
+}
+
+func (rest *Rest) book(w http.ResponseWriter, r *http.Request) {
+	booking, err := rest.createBooking(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, booking)
+}
+
+func (rest *Rest) cancelBooking(w http.ResponseWriter, r *http.Request) {
+	booking, err := rest.removeBooking(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, booking)
+}
+
+func (rest *Rest) putBookingPageV2(w http.ResponseWriter, r *http.Request) {
+	page, err := rest.saveBookingPage(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: page})
+}
+
+func (rest *Rest) allBookingPagesV2(w http.ResponseWriter, r *http.Request) {
+	pages, err := rest.findBookingPages(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: pages})
+}
+
+func (rest *Rest) deleteBookingPageV2(w http.ResponseWriter, r *http.Request) {
+	if err := rest.removeBookingPage(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rest *Rest) openSlotsV2(w http.ResponseWriter, r *http.Request) {
+	slots, err := rest.findOpenSlots(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: slots})
+}
+
+func (rest *Rest) bookV2(w http.ResponseWriter, r *http.Request) {
+	booking, err := rest.createBooking(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/v2/book/"+booking.Slug+"/bookings/"+booking.CancelToken)
+	rest.sendEnvelope(w, http.StatusCreated, Envelope{Data: booking})
+}
+
+func (rest *Rest) cancelBookingV2(w http.ResponseWriter, r *http.Request) {
+	if _, err := rest.removeBooking(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rest *Rest) saveBookingPage(r *http.Request) (structs.BookingPage, error) {
+	username, err := ownUser(r)
+	if err != nil {
+		return structs.BookingPage{}, err
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.BookingPage{}, structs.NewValidationError("Invalid Data Format")
+	}
+	var page structs.BookingPage
+	if err = json.Unmarshal(data, &page); err != nil {
+		return structs.BookingPage{}, structs.WrapError(structs.KindValidation, err)
+	}
+	return rest.service.Booking.SavePage(r.Context(), username, mux.Vars(r)["slug"], page)
+}
+
+func (rest *Rest) findBookingPages(r *http.Request) ([]structs.BookingPage, error) {
+	username, err := ownUser(r)
+	if err != nil {
+		return nil, err
+	}
+	return rest.service.Booking.GetPages(r.Context(), username)
+}
+
+func (rest *Rest) removeBookingPage(r *http.Request) error {
+	username, err := ownUser(r)
+	if err != nil {
+		return err
+	}
+	return rest.service.Booking.DeletePage(r.Context(), username, mux.Vars(r)["slug"])
+}
+
+// findOpenSlots lists the free slots of a page for visitors, who do not need an account.
+// Dates in from and to are read in timezone, or in UTC without one. Slots are
+// shown in timezone, or in the one of the page owner.
+func (rest *Rest) findOpenSlots(r *http.Request) (structs.OpenSlots, error) {
+	query := r.URL.Query()
+	var visitor *time.Location
+	dates := time.UTC
+	if name := query.Get("timezone"); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return structs.OpenSlots{}, structs.NewValidationError("Invalid timezone parameter",
+				structs.FieldError{Field: "timezone", Message: "must be a known IANA time zone name"})
+		}
+		visitor, dates = loc, loc
+	}
+	window, err := structs.ParseBookingWindow(query.Get("from"), query.Get("to"), dates, time.Now().UTC())
+	if err != nil {
+		return structs.OpenSlots{}, err
+	}
+	return rest.service.Booking.OpenSlots(r.Context(), mux.Vars(r)["slug"], window, visitor)
+}
+
+func (rest *Rest) createBooking(r *http.Request) (structs.Booking, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.Booking{}, structs.NewValidationError("Invalid Data Format")
+	}
+	var request structs.BookingRequest
+	if err = json.Unmarshal(data, &request); err != nil {
+		return structs.Booking{}, structs.WrapError(structs.KindValidation, err)
+	}
+	return rest.service.Booking.Book(r.Context(), mux.Vars(r)["slug"], request)
+}
+
+func (rest *Rest) removeBooking(r *http.Request) (structs.Booking, error) {
+	vars := mux.Vars(r)
+	return rest.service.Booking.Cancel(r.Context(), vars["slug"], vars["token"])
+}
@@ -1,41 +1,79 @@
 package api
 
 import (
-	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dkucheru/Calendar/structs"
 	"github.com/gorilla/mux"
 )
 
+// changeTimezone answers with the new location, unless events are rebased
+// or a dry run is asked for, then the rebased events are listed too.
 func (rest *Rest) changeTimezone(w http.ResponseWriter, r *http.Request) {
-	mux := mux.Vars(r)
-	username := mux["username"]
-
 	query := r.URL.Query()
-	location := query.Get("location")
-
-	if username == "" {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid username"))
+	opts, err := loadRebaseOptions(query)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rebasing := query.Get("rebase") != "" || opts.DryRun
+	// only the owner may move events around
+	if _, err := ownUser(r); rebasing && err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	relocation, err := rest.moveUser(r, query.Get("location"), opts)
+	if err != nil {
+		rest.sendError(w, r, err)
 		return
 	}
 
-	loc, err := time.LoadLocation(location)
-	if err != nil || location == "" {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid location parameter"))
+	if !rebasing {
+		rest.sendData(w, relocation.User.Location.String())
 		return
 	}
+	rest.sendData(w, relocation)
+}
 
-	newLocation, err := rest.service.Users.UpdateLocation(username, *loc)
-	if err != nil {
-		if errors.Is(err, structs.ErrNoMatch) {
-			rest.sendError(w, http.StatusNotFound, err)
-			return
+// loadRebaseOptions reads ?rebase=none|future|all|selected&events=1,2&dry_run=true.
+func loadRebaseOptions(query url.Values) (structs.RebaseOptions, error) {
+	opts := structs.RebaseOptions{Scope: structs.RebaseScope(query.Get("rebase"))}
+	if ids := query.Get("events"); ids != "" {
+		for _, id := range strings.Split(ids, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(id))
+			if err != nil {
+				return structs.RebaseOptions{}, structs.NewValidationError("error parsing event ids",
+					structs.FieldError{Field: "events", Message: "must be a comma separated list of ids"})
+			}
+			opts.EventIDs = append(opts.EventIDs, n)
 		}
-		rest.sendError(w, http.StatusInternalServerError, err)
-		return
+	}
+	if dryRun := query.Get("dry_run"); dryRun != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return structs.RebaseOptions{}, structs.NewValidationError("error parsing dry_run",
+				structs.FieldError{Field: "dry_run", Message: "must be true or false"})
+		}
+	}
+	return opts, nil
+}
+
+// moveUser changes the location of the user named in the route.
+func (rest *Rest) moveUser(r *http.Request, location string, opts structs.RebaseOptions) (structs.Relocation, error) {
+	username := mux.Vars(r)["username"]
+	if username == "" {
+		return structs.Relocation{}, structs.NewValidationError("Invalid username")
+	}
+
+	loc, err := time.LoadLocation(location)
+	if err != nil || location == "" {
+		return structs.Relocation{}, structs.NewValidationError("Invalid location parameter",
+			structs.FieldError{Field: "location", Message: "must be a known IANA time zone name"})
 	}
 
-	rest.sendData(w, newLocation.Location.String())
+	return rest.service.Users.UpdateLocation(r.Context(), username, *loc, opts)
 }
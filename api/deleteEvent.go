@@ -1,36 +1,23 @@
 package api
 
 import (
-	"errors"
 	"net/http"
-	"strconv"
-
-	"github.com/dkucheru/Calendar/structs"
-	"github.com/gorilla/mux"
 )
 
 func (rest *Rest) deleteEvent(w http.ResponseWriter, r *http.Request) {
-	var id int
-	var err error
+	if err := rest.removeEvent(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
 
-	mux := mux.Vars(r)
-	receivedId := mux["id"]
+	rest.sendData(w, "Deleted Event")
+}
 
-	id, err = strconv.Atoi(receivedId)
+func (rest *Rest) removeEvent(r *http.Request) error {
+	id, err := eventID(r)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid Data Format"))
-		return
+		return err
 	}
 	user, _, _ := r.BasicAuth()
-	err = rest.service.Events.DeleteEvent(id, user)
-	if err != nil {
-		if errors.Is(err, structs.ErrNoMatch) {
-			rest.sendError(w, http.StatusNotFound, err)
-			return
-		}
-		rest.sendError(w, http.StatusInternalServerError, err)
-		return
-	}
-
-	rest.sendData(w, "Deleted Event")
+	return rest.service.Events.DeleteEvent(r.Context(), id, user)
 }
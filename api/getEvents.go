@@ -2,10 +2,10 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dkucheru/Calendar/structs"
@@ -28,40 +28,44 @@ const (
 )
 
 func (rest *Rest) allEvents(w http.ResponseWriter, r *http.Request) {
-	user, _, _ := r.BasicAuth()
-	loc, err := rest.service.Users.GetUserLocation(user)
+	events, err := rest.findEvents(r, r.URL.Query())
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, err)
+		rest.sendError(w, r, err)
 		return
 	}
-
-	query := r.URL.Query()
-	params, err := LoadParameters(query)
+	eventsJSON, err := json.Marshal(events)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, err)
+		rest.sendError(w, r, err)
 		return
 	}
-
-	events, err := rest.service.Events.GetEventsOfTheDay(params, loc)
+	_, err = w.Write(eventsJSON)
 	if err != nil {
-		rest.sendError(w, http.StatusInternalServerError, err)
+		rest.sendError(w, r, err)
 		return
 	}
-	eventsJSON, err := json.Marshal(events)
+}
+
+// findEvents returns the events of the requesting user matching query in their location.
+func (rest *Rest) findEvents(r *http.Request, query url.Values) ([]structs.Event, error) {
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
 	if err != nil {
-		rest.sendError(w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
-	_, err = w.Write(eventsJSON)
+
+	params, err := LoadParameters(query)
 	if err != nil {
-		rest.sendError(w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
+	params.Owner = user
+
+	return rest.service.Events.GetEventsOfTheDay(r.Context(), params, loc)
 }
 
 func LoadParameters(query url.Values) (structs.EventParams, error) {
 	params := structs.EventParams{}
 	params.Name = query.Get("name")
+	params.Holidays = query.Get("holidays")
 	if sort := query.Get("sorting"); sort != "" {
 		params.Sorting = true
 	}
@@ -86,12 +90,14 @@ func LoadParameters(query url.Values) (structs.EventParams, error) {
 		end:   true,
 	}
 
-	for paramName, url := range receivedParams {
-		if url != "" {
+	// checked in a fixed order, so the same query always reports the same field
+	for _, paramName := range []string{day, week, month, year, start, end} {
+		if url := receivedParams[paramName]; url != "" {
 			if isNum[paramName] {
 				newInt, err := strconv.Atoi(url)
 				if err != nil {
-					return structs.EventParams{}, errors.New("error parsing date part value")
+					return structs.EventParams{}, structs.NewValidationError("error parsing date part value",
+						structs.FieldError{Field: strings.ToLower(paramName), Message: "must be a number"})
 				}
 				switch paramName {
 				case day:
@@ -107,7 +113,8 @@ func LoadParameters(query url.Values) (structs.EventParams, error) {
 				var dest *time.Time
 				err := json.Unmarshal([]byte(url), dest)
 				if err != nil {
-					return structs.EventParams{}, err
+					return structs.EventParams{}, structs.NewValidationError(err.Error(),
+						structs.FieldError{Field: strings.ToLower(paramName), Message: "must be a RFC 3339 time"})
 				}
 				switch paramName {
 				case start:
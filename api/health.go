@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/logger"
+)
+
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency of the service is usable.
+type Check func(ctx context.Context) error
+
+// Worker is a background worker whose liveness /readyz reports.
+type Worker interface {
+	// LastTick returns when the worker last woke up, the zero time before it started.
+	LastTick() time.Time
+	// Interval returns how often the worker wakes up.
+	Interval() time.Duration
+}
+
+// InfoSource returns a piece of runtime state shown by /debug/info.
+type InfoSource func(ctx context.Context) (interface{}, error)
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+type namedInfo struct {
+	name   string
+	source InfoSource
+}
+
+type diagnostics struct {
+	mu        sync.RWMutex
+	startedAt time.Time
+	readiness []namedCheck
+	info      []namedInfo
+}
+
+type checkResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AddReadinessCheck registers a check that has to pass for /readyz to report the service as ready.
+func (rest *Rest) AddReadinessCheck(name string, check Check) {
+	rest.diagnostics.mu.Lock()
+	defer rest.diagnostics.mu.Unlock()
+	rest.diagnostics.readiness = append(rest.diagnostics.readiness, namedCheck{name: name, check: check})
+}
+
+// AddWorkerCheck registers a readiness check failing when worker has not woken up
+// within two of its intervals, or has not started yet.
+func (rest *Rest) AddWorkerCheck(name string, worker Worker) {
+	rest.AddReadinessCheck(name, func(ctx context.Context) error {
+		return workerCheck(worker, time.Now())
+	})
+}
+
+func workerCheck(worker Worker, now time.Time) error {
+	last := worker.LastTick()
+	if last.IsZero() {
+		return fmt.Errorf("worker has not started")
+	}
+	if idle := now.Sub(last); idle > 2*worker.Interval() {
+		return fmt.Errorf("worker has not run for %v", idle.Round(time.Second))
+	}
+	return nil
+}
+
+// AddDebugInfo registers a source of data included in the /debug/info response under name.
+func (rest *Rest) AddDebugInfo(name string, source InfoSource) {
+	rest.diagnostics.mu.Lock()
+	defer rest.diagnostics.mu.Unlock()
+	rest.diagnostics.info = append(rest.diagnostics.info, namedInfo{name: name, source: source})
+}
+
+// healthz only tells that the process is up and serving requests.
+func (rest *Rest) healthz(w http.ResponseWriter, r *http.Request) {
+	rest.sendData(w, "ok")
+}
+
+func (rest *Rest) readyz(w http.ResponseWriter, r *http.Request) {
+	rest.diagnostics.mu.RLock()
+	checks := append([]namedCheck(nil), rest.diagnostics.readiness...)
+	rest.diagnostics.mu.RUnlock()
+
+	ready := true
+	results := make([]checkResult, 0, len(checks))
+	for _, c := range checks {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := c.check(ctx)
+		cancel()
+		result := checkResult{Name: c.name, Status: "ok"}
+		if err != nil {
+			ready = false
+			result.Status = "failing"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	if !ready {
+		rest.sendStatus(w, http.StatusServiceUnavailable, results)
+		return
+	}
+	rest.sendData(w, results)
+}
+
+func (rest *Rest) debugInfo(w http.ResponseWriter, r *http.Request) {
+	rest.diagnostics.mu.RLock()
+	sources := append([]namedInfo(nil), rest.diagnostics.info...)
+	rest.diagnostics.mu.RUnlock()
+
+	info := map[string]interface{}{
+		"version":    rest.conf.Version,
+		"go_version": runtime.Version(),
+		"started_at": rest.diagnostics.startedAt,
+		"uptime":     time.Since(rest.diagnostics.startedAt).Round(time.Second).String(),
+		"goroutines": runtime.NumGoroutine(),
+	}
+	for _, s := range sources {
+		value, err := s.source(r.Context())
+		if err != nil {
+			value = map[string]string{"error": err.Error()}
+		}
+		info[s.name] = value
+	}
+	rest.sendData(w, info)
+}
+
+// sendStatus writes data in the usual response format with a non-success status code.
+func (rest *Rest) sendStatus(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.WriteHeader(statusCode)
+	bytes, err := json.Marshal(Response{
+		Status: statusCode,
+		Data:   data,
+	})
+	if err != nil {
+		logger.Errorf("writing response : %v", err)
+	}
+	_, err = w.Write(bytes)
+	if err != nil {
+		logger.Errorf("writing response : %v", err)
+	}
+}
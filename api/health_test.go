@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+type stubWorker struct {
+	last     time.Time
+	interval time.Duration
+}
+
+func (w stubWorker) LastTick() time.Time     { return w.last }
+func (w stubWorker) Interval() time.Duration { return w.interval }
+
+func TestWorkerCheck(t *testing.T) {
+	now := time.Date(2021, 12, 13, 10, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		worker      stubWorker
+		errExpected bool
+	}{
+		"Not started":         {stubWorker{interval: time.Minute}, true},
+		"Woke up recently":    {stubWorker{now.Add(-90 * time.Second), time.Minute}, false},
+		"Missed two wake ups": {stubWorker{now.Add(-3 * time.Minute), time.Minute}, true},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if err := workerCheck(test.worker, now); (err != nil) != test.errExpected {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
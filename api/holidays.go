@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func (rest *Rest) allHolidays(w http.ResponseWriter, r *http.Request) {
+	holidays, err := rest.findHolidays(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, holidays)
+}
+
+func (rest *Rest) allHolidaysV2(w http.ResponseWriter, r *http.Request) {
+	holidays, err := rest.findHolidays(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: holidays})
+}
+
+// findHolidays lists the holidays of the country of the query in its year, the current one by default.
+func (rest *Rest) findHolidays(r *http.Request) ([]structs.Holiday, error) {
+	query := r.URL.Query()
+	country := query.Get("country")
+	if country == "" {
+		return nil, structs.NewValidationError("Missing country parameter",
+			structs.FieldError{Field: "country", Message: "must be one of " + strings.Join(rest.service.Holidays.Countries(), ", ")})
+	}
+	year := time.Now().UTC().Year()
+	if value := query.Get("year"); value != "" {
+		var err error
+		if year, err = strconv.Atoi(value); err != nil {
+			return nil, structs.NewValidationError("error parsing year",
+				structs.FieldError{Field: "year", Message: "must be a number"})
+		}
+	}
+	return rest.service.Holidays.GetHolidays(r.Context(), country, year)
+}
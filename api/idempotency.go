@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+)
+
+// responseCapture passes the response through and keeps a copy of it.
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(statusCode int) {
+	if c.status == 0 {
+		c.status = statusCode
+	}
+	c.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (c *responseCapture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+// idempotent makes retries of handler with the same Idempotency-Key header replay the
+// first response. Keys are scoped to the user and the route, reusing one with a
+// different body is rejected. Requests without the header are handled as usual.
+// Guests of public routes send no user, their keys are scoped by the path alone,
+// which holds the slug of the booking page.
+func (rest *Rest) idempotent(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			handler(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength || !isPrintable(key) {
+			rest.sendError(w, r, structs.NewValidationError("invalid idempotency key", structs.FieldError{
+				Field:   idempotencyKeyHeader,
+				Message: "must be printable ASCII of at most 255 characters",
+			}))
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			rest.sendError(w, r, structs.NewValidationError("Invalid Data Format"))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		user, _, _ := r.BasicAuth()
+		scope := sha256.Sum256([]byte(user + "\x00" + r.Method + "\x00" + r.URL.Path + "\x00" + key))
+		requestHash := sha256.Sum256(body)
+		stored, finish, err := rest.service.Idempotency.Begin(r.Context(),
+			hex.EncodeToString(scope[:]), hex.EncodeToString(requestHash[:]))
+		if err != nil {
+			rest.sendError(w, r, err)
+			return
+		}
+		if stored != nil {
+			for name, values := range stored.Header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(idempotencyReplayedHeader, "true")
+			w.WriteHeader(stored.Status)
+			w.Write(stored.Body)
+			return
+		}
+
+		// headers set before the handler, like the request id, belong to this request only
+		before := w.Header().Clone()
+		capture := &responseCapture{ResponseWriter: w}
+		defer func() {
+			finish(capture.status, handlerHeaders(before, capture.Header()), capture.body.Bytes())
+		}()
+		handler(capture, r)
+	}
+}
+
+// handlerHeaders returns the headers of after that are not in before as they are.
+func handlerHeaders(before http.Header, after http.Header) map[string][]string {
+	added := make(map[string][]string)
+	for name, values := range after {
+		if !reflect.DeepEqual(before[name], values) {
+			added[name] = values
+		}
+	}
+	return added
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/metrics"
+	"github.com/gorilla/mux"
+)
+
+// statusRecorder remembers what the handler wrote, so that middleware
+// can report it after the handler returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if s.status == 0 {
+		s.status = statusCode
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+func (s *statusRecorder) statusCode() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+type httpMetrics struct {
+	requests     *metrics.CounterVec
+	duration     *metrics.HistogramVec
+	authFailures *metrics.CounterVec
+}
+
+func newHTTPMetrics(registry *metrics.Registry) *httpMetrics {
+	return &httpMetrics{
+		requests: registry.NewCounterVec("http_requests_total",
+			"Number of handled http requests.", "method", "route", "status"),
+		duration: registry.NewHistogramVec("http_request_duration_seconds",
+			"Time spent handling http requests.", metrics.DefaultBuckets, "method", "route"),
+		authFailures: registry.NewCounterVec("auth_failures_total",
+			"Number of rejected basic auth attempts.", "reason"),
+	}
+}
+
+// routeTemplate names the matched route by its template, e.g. /events/{id},
+// so that metrics are not split per event id.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
+
+func (rest *Rest) metricsMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		handler.ServeHTTP(recorder, r)
+
+		route := routeTemplate(r)
+		rest.metrics.requests.Inc(r.Method, route, strconv.Itoa(recorder.statusCode()))
+		rest.metrics.duration.Observe(time.Since(started).Seconds(), r.Method, route)
+	})
+}
+
+const requestIDHeader = "X-Request-ID"
+
+// requestID returns the id sent by the client when it looks sane,
+// otherwise a new random one.
+func requestID(r *http.Request) string {
+	id := r.Header.Get(requestIDHeader)
+	if id != "" && len(id) <= 128 && isPrintable(id) {
+		return id
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+func isPrintable(s string) bool {
+	for _, c := range s {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID returns the correlation id of the request handled with ctx.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type requestIDKey struct{}
+
+// accessLogMiddleware attaches a correlation id to the request context and the
+// response, and writes one access log line per request once it is handled.
+func (rest *Rest) accessLogMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		id := requestID(r)
+		w.Header().Set(requestIDHeader, id)
+
+		entry := logger.WithField("request_id", id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		ctx = logger.NewContext(ctx, entry)
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		handler.ServeHTTP(recorder, r.WithContext(ctx))
+
+		user, _, _ := r.BasicAuth()
+		status := recorder.statusCode()
+		level := logger.InfoLevel
+		switch {
+		case status >= 500:
+			level = logger.ErrorLevel
+		case status >= 400:
+			level = logger.WarnLevel
+		}
+		entry.WithFields(logger.Fields{
+			"method":     r.Method,
+			"route":      routeTemplate(r),
+			"path":       r.URL.Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(started).Microseconds()) / 1000,
+			"user":       user,
+			"bytes":      recorder.bytes,
+			"remote":     r.RemoteAddr,
+		}).Log(level, "request handled")
+	})
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+func (rest *Rest) patchEvent(w http.ResponseWriter, r *http.Request) {
+	patchedEvent, err := rest.applyEventPatch(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+
+	rest.sendData(w, patchedEvent)
+}
+
+func (rest *Rest) patchEventV2(w http.ResponseWriter, r *http.Request) {
+	patchedEvent, err := rest.applyEventPatch(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: patchedEvent})
+}
+
+// applyEventPatch changes only the fields named in the request body. A JSON Merge Patch
+// (RFC 7386) is expected, unless the body is declared as a JSON Patch (RFC 6902).
+// Times are read in the location of the user, like in the other event requests.
+func (rest *Rest) applyEventPatch(r *http.Request) (structs.Event, error) {
+	id, err := eventID(r)
+	if err != nil {
+		return structs.Event{}, err
+	}
+
+	apply := mergePatch
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			mediaType = contentType
+		}
+		switch mediaType {
+		case mergePatchContentType, "application/json":
+		case jsonPatchContentType:
+			apply = jsonPatch
+		default:
+			return structs.Event{}, structs.NewError(structs.KindUnsupported,
+				"patches have to be sent as "+mergePatchContentType+" or "+jsonPatchContentType)
+		}
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.Event{}, structs.NewValidationError("Invalid Data Format")
+	}
+
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.Event{}, err
+	}
+
+	return rest.service.Events.PatchEvent(r.Context(), id, user, loc, func(e structs.EventCreation) (structs.EventCreation, error) {
+		return patchEventCreation(e, data, apply)
+	})
+}
+
+// patchEventCreation applies the patch to the JSON form of e. Fields set to
+// null or removed by the patch, such as alert, end up with their zero value.
+func patchEventCreation(e structs.EventCreation, patch []byte,
+	apply func(document map[string]interface{}, patch []byte) (map[string]interface{}, error)) (structs.EventCreation, error) {
+	encoded, err := json.Marshal(e)
+	if err != nil {
+		return structs.EventCreation{}, err
+	}
+	var document map[string]interface{}
+	if err = json.Unmarshal(encoded, &document); err != nil {
+		return structs.EventCreation{}, err
+	}
+	known := make(map[string]bool, len(document))
+	for name := range document {
+		known[name] = true
+	}
+	if e.Alert.IsZero() {
+		delete(document, "alert")
+	}
+
+	patched, err := apply(document, patch)
+	if err != nil {
+		return structs.EventCreation{}, err
+	}
+
+	// EventCreation decodes itself, so unknown members are looked for here
+	for name := range patched {
+		if !known[name] {
+			return structs.EventCreation{}, structs.NewValidationError("json: unknown field \""+name+"\"",
+				structs.FieldError{Field: name, Message: "is not a field of an event"})
+		}
+	}
+
+	encoded, err = json.Marshal(patched)
+	if err != nil {
+		return structs.EventCreation{}, err
+	}
+	var result structs.EventCreation
+	if err = json.Unmarshal(encoded, &result); err != nil {
+		return structs.EventCreation{}, structs.WrapError(structs.KindValidation, err)
+	}
+	return result, nil
+}
+
+// mergePatch applies an RFC 7386 merge patch, null removes a member.
+func mergePatch(document map[string]interface{}, patch []byte) (map[string]interface{}, error) {
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, structs.WrapError(structs.KindValidation, err)
+	}
+	if _, ok := p.(map[string]interface{}); !ok {
+		return nil, structs.NewValidationError("merge patch has to be a JSON object")
+	}
+	return mergeValue(document, p).(map[string]interface{}), nil
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergeValue(targetObject[name], value)
+	}
+	return targetObject
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// jsonPatch applies an RFC 6902 patch. Events are flat, so only paths
+// naming a top level member are accepted and move and copy are not supported.
+func jsonPatch(document map[string]interface{}, patch []byte) (map[string]interface{}, error) {
+	var operations []patchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, structs.WrapError(structs.KindValidation, err)
+	}
+	for _, operation := range operations {
+		field := structs.FieldError{Field: operation.Path}
+		name := strings.TrimPrefix(operation.Path, "/")
+		if !strings.HasPrefix(operation.Path, "/") || name == "" || strings.Contains(name, "/") {
+			field.Message = "only paths to top level members are supported"
+			return nil, structs.NewValidationError("invalid patch operation", field)
+		}
+		name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+
+		var value interface{}
+		if operation.Op == "add" || operation.Op == "replace" || operation.Op == "test" {
+			if len(operation.Value) == 0 {
+				field.Message = "operation " + operation.Op + " needs a value"
+				return nil, structs.NewValidationError("invalid patch operation", field)
+			}
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				return nil, structs.WrapError(structs.KindValidation, err)
+			}
+		}
+
+		current, exists := document[name]
+		switch operation.Op {
+		case "add":
+			document[name] = value
+		case "replace", "remove":
+			if !exists {
+				field.Message = "member does not exist"
+				return nil, structs.NewValidationError("invalid patch operation", field)
+			}
+			if operation.Op == "remove" {
+				delete(document, name)
+			} else {
+				document[name] = value
+			}
+		case "test":
+			if !exists || !reflect.DeepEqual(current, value) {
+				field.Message = "value does not match"
+				return nil, &structs.Error{Kind: structs.KindTestFailed, Message: "patch test failed", Fields: []structs.FieldError{field}}
+			}
+		default:
+			field.Message = "operation " + operation.Op + " is not supported"
+			return nil, structs.NewValidationError("invalid patch operation", field)
+		}
+	}
+	return document, nil
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestPatchEventCreation(t *testing.T) {
+	stored := structs.EventCreation{
+		Name:        "Meeting",
+		Start:       time.Date(2021, 12, 10, 13, 45, 0, 0, time.UTC),
+		End:         time.Date(2021, 12, 10, 14, 0, 0, 0, time.UTC),
+		Description: "weekly",
+		Alert:       time.Date(2021, 12, 10, 13, 30, 0, 0, time.UTC),
+	}
+	later := time.Date(2021, 12, 10, 15, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		jsonPatch   bool
+		patch       string
+		check       func(e structs.EventCreation) bool
+		errExpected bool
+	}{
+		"Merge description": {
+			false, `{"description":"changed"}`,
+			func(e structs.EventCreation) bool { return e.Description == "changed" && e.Name == "Meeting" }, false,
+		},
+		"Merge clears alert": {
+			false, `{"alert":null}`,
+			func(e structs.EventCreation) bool { return e.Alert.IsZero() && e.Description == "weekly" }, false,
+		},
+		"Merge end": {
+			false, `{"end":"2021-12-10T15:00:00Z"}`,
+			func(e structs.EventCreation) bool { return e.End.Equal(later) && e.Start.Equal(stored.Start) }, false,
+		},
+		"Merge unknown field": {
+			false, `{"id":3}`, nil, true,
+		},
+		"Merge not an object": {
+			false, `"name"`, nil, true,
+		},
+		"JSON Patch replace and remove": {
+			true, `[{"op":"replace","path":"/name","value":"Renamed"},{"op":"remove","path":"/alert"}]`,
+			func(e structs.EventCreation) bool { return e.Name == "Renamed" && e.Alert.IsZero() }, false,
+		},
+		"JSON Patch passing test": {
+			true, `[{"op":"test","path":"/name","value":"Meeting"},{"op":"add","path":"/end","value":"2021-12-10T15:00:00Z"}]`,
+			func(e structs.EventCreation) bool { return e.End.Equal(later) }, false,
+		},
+		"JSON Patch failing test": {
+			true, `[{"op":"test","path":"/name","value":"Other"}]`, nil, true,
+		},
+		"JSON Patch nested path": {
+			true, `[{"op":"replace","path":"/name/first","value":"x"}]`, nil, true,
+		},
+		"JSON Patch unsupported operation": {
+			true, `[{"op":"move","from":"/name","path":"/description"}]`, nil, true,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			apply := mergePatch
+			if test.jsonPatch {
+				apply = jsonPatch
+			}
+			patched, err := patchEventCreation(stored, []byte(test.patch), apply)
+			if (err != nil) != test.errExpected {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err == nil && !test.check(patched) {
+				t.Errorf("wrong patched event %+v", patched)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body, every failed request is answered with one.
+// The request id is not repeated here, it is sent in the X-Request-ID header.
+type Problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Fields   []structs.FieldError `json:"fields,omitempty"`
+}
+
+type problemKind struct {
+	status int
+	title  string
+}
+
+var problemKinds = map[structs.ErrorKind]problemKind{
+	structs.KindValidation:    {http.StatusBadRequest, "Invalid request"},
+	structs.KindNotFound:      {http.StatusNotFound, "Resource not found"},
+	structs.KindConflict:      {http.StatusConflict, "Conflict"},
+	structs.KindTestFailed:    {http.StatusConflict, "Patch test failed"},
+	structs.KindUnauthorized:  {http.StatusUnauthorized, "Authentication required"},
+	structs.KindForbidden:     {http.StatusForbidden, "Access denied"},
+	structs.KindUnsupported:   {http.StatusUnsupportedMediaType, "Unsupported media type"},
+	structs.KindUnprocessable: {http.StatusUnprocessableEntity, "Unprocessable request"},
+	structs.KindInternal:      {http.StatusInternalServerError, "Internal server error"},
+	structs.KindCanceled:      {StatusClientClosedRequest, "Request canceled"},
+	structs.KindTimeout:       {http.StatusGatewayTimeout, "Request timed out"},
+}
+
+// problemFor is the only place where errors are turned into status codes.
+func problemFor(r *http.Request, err error) Problem {
+	kind := structs.KindOf(err)
+	pk, ok := problemKinds[kind]
+	if !ok {
+		kind, pk = structs.KindInternal, problemKinds[structs.KindInternal]
+	}
+	p := Problem{
+		Type:     "/problems/" + string(kind),
+		Title:    pk.title,
+		Status:   pk.status,
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+		Fields:   structs.FieldsOf(err),
+	}
+	if kind == structs.KindInternal {
+		// storage details are logged, not shown to clients
+		p.Detail = ""
+	}
+	return p
+}
+
+// sendError answers the request with the problem describing err.
+func (rest *Rest) sendError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := problemFor(r, err)
+	entry := logger.FromContext(r.Context()).WithField("status", problem.Status)
+	if problem.Status >= 500 {
+		entry.Errorf("request failed : %v", err)
+	} else {
+		entry.Debugf("request rejected : %v", err)
+	}
+
+	if problem.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Please enter your username and password for this site"`)
+	}
+	if apiVersion(r.Context()) >= 2 {
+		rest.sendEnvelope(w, problem.Status, Envelope{Error: &problem})
+		return
+	}
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+
+	bytes, err := json.Marshal(problem)
+	if err != nil {
+		logger.Errorf("writing response : %v", err)
+	}
+	_, err = w.Write(bytes)
+	if err != nil {
+		logger.Errorf("writing response : %v", err)
+	}
+}
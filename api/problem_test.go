@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestProblemFor(t *testing.T) {
+	testCases := map[string]struct {
+		err          error
+		statusExpect int
+		typeExpect   string
+		detailExpect string
+		fieldsExpect int
+	}{
+		"Missing record": {
+			fmt.Errorf("%w : event with id [1] does not exist ", structs.ErrNoMatch),
+			http.StatusNotFound, "/problems/not-found", "no matching record : event with id [1] does not exist ", 0,
+		},
+		"Duplicate": {
+			fmt.Errorf("%w : user exists", structs.ErrDublicate),
+			http.StatusConflict, "/problems/conflict", "record dublicate : user exists", 0,
+		},
+		"Booked resource": {
+			structs.ErrBooked,
+			http.StatusConflict, "/problems/conflict", "resource already booked", 0,
+		},
+		"Failed patch test": {
+			&structs.Error{Kind: structs.KindTestFailed, Message: "patch test failed", Fields: []structs.FieldError{{Field: "/name"}}},
+			http.StatusConflict, "/problems/patch-test-failed", "patch test failed", 1,
+		},
+		"Mandatory field": {
+			&structs.MandatoryFieldError{FieldName: "name"},
+			http.StatusBadRequest, "/problems/validation",
+			"mandatory field *name* is not filled. Please, add a name to the event", 1,
+		},
+		"Validation with fields": {
+			structs.NewValidationError("bad", structs.FieldError{Field: "a"}, structs.FieldError{Field: "b"}),
+			http.StatusBadRequest, "/problems/validation", "bad", 2,
+		},
+		"Wrong password": {
+			fmt.Errorf("%w : incorrect password", structs.ErrUnauthorized),
+			http.StatusUnauthorized, "/problems/unauthorized", "unauthorized : incorrect password", 0,
+		},
+		"Storage failure hides the detail": {
+			fmt.Errorf("%w : connection refused", structs.ErrPostgres),
+			http.StatusInternalServerError, "/problems/internal", "", 0,
+		},
+		"Unclassified error": {
+			errors.New("boom"),
+			http.StatusInternalServerError, "/problems/internal", "", 0,
+		},
+		"Timeout wins over storage": {
+			fmt.Errorf("%w : %v", structs.ErrTimeout, fmt.Errorf("%w : slow", structs.ErrPostgres)),
+			http.StatusGatewayTimeout, "/problems/timeout", "request timed out : postgres error : slow", 0,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/events/1", nil)
+			p := problemFor(r, test.err)
+			if p.Status != test.statusExpect || p.Type != test.typeExpect {
+				t.Errorf("got %v %v, wanted %v %v", p.Status, p.Type, test.statusExpect, test.typeExpect)
+			}
+			if p.Detail != test.detailExpect {
+				t.Errorf("wrong detail : got %q, wanted %q", p.Detail, test.detailExpect)
+			}
+			if len(p.Fields) != test.fieldsExpect {
+				t.Errorf("got %v fields, wanted %v", len(p.Fields), test.fieldsExpect)
+			}
+			if p.Instance != "/events/1" {
+				t.Errorf("wrong instance %q", p.Instance)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func (rest *Rest) quickAddEvent(w http.ResponseWriter, r *http.Request) {
+	result, err := rest.quickAdd(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, result)
+}
+
+func (rest *Rest) quickAddEventV2(w http.ResponseWriter, r *http.Request) {
+	result, err := rest.quickAdd(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	if result.DryRun {
+		rest.sendEnvelope(w, http.StatusOK, Envelope{Data: result})
+		return
+	}
+	w.Header().Set("Location", "/v2/events/"+strconv.Itoa(result.Event.Id))
+	rest.sendEnvelope(w, http.StatusCreated, Envelope{Data: result})
+}
+
+// quickAdd adds the event described by the text in the request body, or only
+// interprets it for a dry run.
+func (rest *Rest) quickAdd(r *http.Request) (structs.QuickAddResult, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.QuickAddResult{}, structs.NewValidationError("Invalid Data Format")
+	}
+	var quick structs.QuickAdd
+	if err = json.Unmarshal(data, &quick); err != nil {
+		return structs.QuickAddResult{}, structs.WrapError(structs.KindValidation, err)
+	}
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.QuickAddResult{}, err
+	}
+	return rest.service.Events.QuickAdd(r.Context(), user, loc, quick.Text, quick.DryRun)
+}
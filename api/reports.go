@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func (rest *Rest) timeReport(w http.ResponseWriter, r *http.Request) {
+	report, err := rest.findTimeReport(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, report)
+}
+
+func (rest *Rest) timeReportV2(w http.ResponseWriter, r *http.Request) {
+	report, err := rest.findTimeReport(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: report})
+}
+
+// findTimeReport sums up the time of the requesting user between ?from= and ?to=,
+// grouped by ?group_by= and limited to the events with names like ?name=.
+func (rest *Rest) findTimeReport(r *http.Request) (structs.TimeReport, error) {
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.TimeReport{}, err
+	}
+	query := r.URL.Query()
+	return rest.service.Reports.GetTimeReport(r.Context(), user, query.Get("from"), query.Get("to"),
+		query.Get("group_by"), query.Get("name"), loc)
+}
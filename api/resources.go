@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/gorilla/mux"
+)
+
+func (rest *Rest) addResource(w http.ResponseWriter, r *http.Request) {
+	resource, err := rest.createResource(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, resource)
+}
+
+func (rest *Rest) allResources(w http.ResponseWriter, r *http.Request) {
+	resources, err := rest.service.Resources.GetResources(r.Context())
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, resources)
+}
+
+func (rest *Rest) getResource(w http.ResponseWriter, r *http.Request) {
+	resource, err := rest.findResource(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, resource)
+}
+
+func (rest *Rest) addResourceV2(w http.ResponseWriter, r *http.Request) {
+	resource, err := rest.createResource(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/v2/resources/"+strconv.Itoa(resource.Id))
+	rest.sendEnvelope(w, http.StatusCreated, Envelope{Data: resource})
+}
+
+func (rest *Rest) allResourcesV2(w http.ResponseWriter, r *http.Request) {
+	resources, err := rest.service.Resources.GetResources(r.Context())
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: resources})
+}
+
+func (rest *Rest) getResourceV2(w http.ResponseWriter, r *http.Request) {
+	resource, err := rest.findResource(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: resource})
+}
+
+func (rest *Rest) createResource(r *http.Request) (structs.Resource, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.Resource{}, structs.NewValidationError("Invalid Data Format")
+	}
+	var resource structs.Resource
+	if err = json.Unmarshal(data, &resource); err != nil {
+		return structs.Resource{}, structs.WrapError(structs.KindValidation, err)
+	}
+	return rest.service.Resources.AddResource(r.Context(), resource)
+}
+
+func (rest *Rest) findResource(r *http.Request) (structs.Resource, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return structs.Resource{}, structs.NewValidationError("Invalid Data Format")
+	}
+	return rest.service.Resources.GetResource(r.Context(), id)
+}
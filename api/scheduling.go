@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func (rest *Rest) suggestSlots(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := rest.findSlots(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, suggestions)
+}
+
+func (rest *Rest) suggestSlotsV2(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := rest.findSlots(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: suggestions})
+}
+
+// findSlots suggests meeting times for the users named in the request body.
+func (rest *Rest) findSlots(r *http.Request) ([]structs.Suggestion, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, structs.NewValidationError("Invalid Data Format")
+	}
+	var request structs.SuggestionRequest
+	if err = json.Unmarshal(data, &request); err != nil {
+		return nil, structs.WrapError(structs.KindValidation, err)
+	}
+	return rest.service.Scheduling.Suggest(r.Context(), request)
+}
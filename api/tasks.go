@@ -0,0 +1,202 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/gorilla/mux"
+)
+
+func (rest *Rest) addTask(w http.ResponseWriter, r *http.Request) {
+	task, err := rest.createTask(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, task)
+}
+
+func (rest *Rest) allTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := rest.findTasks(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	if r.URL.Query().Get("format") == "ics" {
+		sendTodoCalendar(w, tasks)
+		return
+	}
+	rest.sendData(w, tasks)
+}
+
+func (rest *Rest) getTask(w http.ResponseWriter, r *http.Request) {
+	task, err := rest.findTask(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, task)
+}
+
+func (rest *Rest) updateTask(w http.ResponseWriter, r *http.Request) {
+	task, err := rest.replaceTask(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, task)
+}
+
+func (rest *Rest) deleteTask(w http.ResponseWriter, r *http.Request) {
+	if err := rest.removeTask(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, "Deleted Task")
+}
+
+func (rest *Rest) addTaskV2(w http.ResponseWriter, r *http.Request) {
+	task, err := rest.createTask(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/v2/tasks/"+strconv.Itoa(task.Id))
+	rest.sendEnvelope(w, http.StatusCreated, Envelope{Data: task})
+}
+
+func (rest *Rest) allTasksV2(w http.ResponseWriter, r *http.Request) {
+	tasks, err := rest.findTasks(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	if r.URL.Query().Get("format") == "ics" {
+		sendTodoCalendar(w, tasks)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: tasks})
+}
+
+func (rest *Rest) getTaskV2(w http.ResponseWriter, r *http.Request) {
+	task, err := rest.findTask(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: task})
+}
+
+func (rest *Rest) updateTaskV2(w http.ResponseWriter, r *http.Request) {
+	task, err := rest.replaceTask(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: task})
+}
+
+func (rest *Rest) deleteTaskV2(w http.ResponseWriter, r *http.Request) {
+	if err := rest.removeTask(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sendTodoCalendar writes tasks as the VTODOs of an iCalendar object.
+func sendTodoCalendar(w http.ResponseWriter, tasks []structs.Task) {
+	w.Header().Set("Content-Type", structs.ICalContentType)
+	if _, err := w.Write([]byte(structs.TodoCalendar(tasks, time.Now()))); err != nil {
+		logger.Errorf("writing response : %v", err)
+	}
+}
+
+func (rest *Rest) createTask(r *http.Request) (structs.Task, error) {
+	task, err := readTask(r)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	return rest.service.Tasks.AddTask(r.Context(), user, loc, task)
+}
+
+// findTasks lists the tasks of the requesting user, ?status= picks the ones in one
+// status and ?due=overdue or ?due=today the ones that are late or due today.
+func (rest *Rest) findTasks(r *http.Request) ([]structs.Task, error) {
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return nil, err
+	}
+	query := r.URL.Query()
+	return rest.service.Tasks.GetTasks(r.Context(), user, query.Get("status"), query.Get("due"), loc)
+}
+
+func (rest *Rest) findTask(r *http.Request) (structs.Task, error) {
+	id, err := taskID(r)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	return rest.service.Tasks.GetTask(r.Context(), user, id, loc)
+}
+
+func (rest *Rest) replaceTask(r *http.Request) (structs.Task, error) {
+	id, err := taskID(r)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	task, err := readTask(r)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	return rest.service.Tasks.UpdateTask(r.Context(), user, id, loc, task)
+}
+
+func (rest *Rest) removeTask(r *http.Request) error {
+	id, err := taskID(r)
+	if err != nil {
+		return err
+	}
+	user, _, _ := r.BasicAuth()
+	return rest.service.Tasks.DeleteTask(r.Context(), user, id)
+}
+
+func readTask(r *http.Request) (structs.Task, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return structs.Task{}, structs.NewValidationError("Invalid Data Format")
+	}
+	var task structs.Task
+	if err = json.Unmarshal(data, &task); err != nil {
+		return structs.Task{}, structs.WrapError(structs.KindValidation, err)
+	}
+	return task, nil
+}
+
+func taskID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return 0, structs.NewValidationError("Invalid Data Format")
+	}
+	return id, nil
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -12,46 +11,49 @@ import (
 )
 
 func (rest *Rest) updateEvent(w http.ResponseWriter, r *http.Request) {
-	mux := mux.Vars(r)
-	receivedId := mux["id"]
-	id, err := strconv.Atoi(receivedId)
+	updatedEvent, err := rest.replaceEvent(r)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid Data Format"))
+		rest.sendError(w, r, err)
 		return
 	}
 
+	rest.sendData(w, updatedEvent)
+}
+
+// replaceEvent overwrites the event with the one sent in the request body.
+func (rest *Rest) replaceEvent(r *http.Request) (structs.Event, error) {
+	id, err := eventID(r)
+	if err != nil {
+		return structs.Event{}, err
+	}
+
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, errors.New("Invalid Data Format"))
-		return
+		return structs.Event{}, structs.NewValidationError("Invalid Data Format")
 	}
 
 	user, _, _ := r.BasicAuth()
-	loc, err := rest.service.Users.GetUserLocation(user)
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, err)
-		return
+		return structs.Event{}, err
 	}
 	var e structs.EventCreation
 	if err = json.Unmarshal(data, &e); err != nil {
-		rest.sendError(w, http.StatusBadRequest, err)
-		return
+		return structs.Event{}, structs.WrapError(structs.KindValidation, err)
 	}
 	event, err := structs.CreateEvent(loc, e)
 	if err != nil {
-		rest.sendError(w, http.StatusBadRequest, err)
-		return
+		return structs.Event{}, err
 	}
 
-	updatedEvent, err := rest.service.Events.UpdateEvent(id, event, loc)
+	return rest.service.Events.UpdateEvent(r.Context(), id, user, event, loc)
+}
+
+// eventID reads the id of the event from the route.
+func eventID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		if errors.Is(err, structs.ErrNoMatch) {
-			rest.sendError(w, http.StatusNotFound, err)
-			return
-		}
-		rest.sendError(w, http.StatusInternalServerError, err)
-		return
+		return 0, structs.NewValidationError("Invalid Data Format")
 	}
-
-	rest.sendData(w, updatedEvent)
+	return id, nil
 }
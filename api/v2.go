@@ -0,0 +1,319 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/gorilla/mux"
+)
+
+// Envelope wraps every /v2 response, Data is set on success and Error on failure.
+type Envelope struct {
+	Data  interface{} `json:"data"`
+	Error *Problem    `json:"error,omitempty"`
+	Meta  *Meta       `json:"meta,omitempty"`
+}
+
+type Meta struct {
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+type Pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// Timezone describes a location as it is at the moment of the response.
+type Timezone struct {
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	UTCOffset    string `json:"utc_offset"`
+}
+
+type User struct {
+	Username string   `json:"username"`
+	Timezone Timezone `json:"timezone"`
+}
+
+func newTimezone(loc time.Location, at time.Time) Timezone {
+	local := at.In(&loc)
+	abbreviation, _ := local.Zone()
+	return Timezone{
+		Name:         loc.String(),
+		Abbreviation: abbreviation,
+		UTCOffset:    local.Format("-07:00"),
+	}
+}
+
+func newUser(u structs.HashedInfo) User {
+	return User{Username: u.Username, Timezone: newTimezone(u.Location, time.Now())}
+}
+
+type apiVersionKey struct{}
+
+// apiVersionMiddleware remembers which version of the api serves the request,
+// so that errors raised before the handler, e.g. by authentication, use its format.
+func apiVersionMiddleware(version int) mux.MiddlewareFunc {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), apiVersionKey{}, version)
+			handler.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func apiVersion(ctx context.Context) int {
+	if version, ok := ctx.Value(apiVersionKey{}).(int); ok {
+		return version
+	}
+	return 1
+}
+
+func (rest *Rest) routesV2(api *mux.Router) {
+	api.HandleFunc("/users", rest.idempotent(rest.addUserV2)).Methods("POST")
+	api.Handle("/users/{username}", rest.BasicAuthMiddleware(rest.getUserV2)).Methods("GET")
+	api.Handle("/users/{username}", rest.BasicAuthMiddleware(rest.changeTimezoneV2)).Methods("PUT")
+	api.Handle("/users/{username}/availability", rest.BasicAuthMiddleware(rest.getAvailabilityV2)).Methods("GET")
+	api.Handle("/users/{username}/availability", rest.BasicAuthMiddleware(rest.putAvailabilityV2)).Methods("PUT")
+	api.Handle("/scheduling/suggest", rest.BasicAuthMiddleware(rest.suggestSlotsV2)).Methods("POST")
+	api.Handle("/users/{username}/booking-pages", rest.BasicAuthMiddleware(rest.allBookingPagesV2)).Methods("GET")
+	api.Handle("/users/{username}/booking-pages/{slug}", rest.BasicAuthMiddleware(rest.putBookingPageV2)).Methods("PUT")
+	api.Handle("/users/{username}/booking-pages/{slug}", rest.BasicAuthMiddleware(rest.deleteBookingPageV2)).Methods("DELETE")
+
+	// booking pages are public, guests book without an account
+	api.HandleFunc("/book/{slug}", rest.openSlotsV2).Methods("GET")
+	api.HandleFunc("/book/{slug}", rest.idempotent(rest.bookV2)).Methods("POST")
+	api.HandleFunc("/book/{slug}/bookings/{token}", rest.cancelBookingV2).Methods("DELETE")
+
+	api.Handle("/resources", rest.BasicAuthMiddleware(rest.idempotent(rest.addResourceV2))).Methods("POST")
+	api.Handle("/resources", rest.BasicAuthMiddleware(rest.allResourcesV2)).Methods("GET")
+	api.Handle("/resources/{id}", rest.BasicAuthMiddleware(rest.getResourceV2)).Methods("GET")
+
+	api.Handle("/events", rest.BasicAuthMiddleware(rest.idempotent(rest.addEventV2))).Methods("POST")
+	api.Handle("/events", rest.BasicAuthMiddleware(rest.allEventsV2)).Methods("GET")
+	api.Handle("/events/quick", rest.BasicAuthMiddleware(rest.idempotent(rest.quickAddEventV2))).Methods("POST")
+	api.Handle("/events/{id}", rest.BasicAuthMiddleware(rest.getEventV2)).Methods("GET")
+	api.Handle("/events/{id}", rest.BasicAuthMiddleware(rest.updateEventV2)).Methods("PUT")
+	api.Handle("/events/{id}", rest.BasicAuthMiddleware(rest.patchEventV2)).Methods("PATCH")
+	api.Handle("/events/{id}", rest.BasicAuthMiddleware(rest.deleteEventV2)).Methods("DELETE")
+
+	api.Handle("/views/"+calendarViews, rest.BasicAuthMiddleware(rest.calendarViewV2)).Methods("GET")
+	api.Handle("/reports/time", rest.BasicAuthMiddleware(rest.timeReportV2)).Methods("GET")
+	api.Handle("/holidays", rest.BasicAuthMiddleware(rest.allHolidaysV2)).Methods("GET")
+
+	api.Handle("/tasks", rest.BasicAuthMiddleware(rest.idempotent(rest.addTaskV2))).Methods("POST")
+	api.Handle("/tasks", rest.BasicAuthMiddleware(rest.allTasksV2)).Methods("GET")
+	api.Handle("/tasks/{id}", rest.BasicAuthMiddleware(rest.getTaskV2)).Methods("GET")
+	api.Handle("/tasks/{id}", rest.BasicAuthMiddleware(rest.updateTaskV2)).Methods("PUT")
+	api.Handle("/tasks/{id}", rest.BasicAuthMiddleware(rest.deleteTaskV2)).Methods("DELETE")
+
+	api.Handle("/alerts", rest.BasicAuthMiddleware(rest.allAlertsV2)).Methods("GET")
+	api.Handle("/alerts/{id}/"+alertActions, rest.BasicAuthMiddleware(rest.alertActionV2)).Methods("POST")
+}
+
+func (rest *Rest) addUserV2(w http.ResponseWriter, r *http.Request) {
+	user, err := rest.registerUser(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/v2/users/"+user.Username)
+	rest.sendEnvelope(w, http.StatusCreated, Envelope{Data: newUser(user)})
+}
+
+// ownUser only lets users act on their own account.
+func ownUser(r *http.Request) (string, error) {
+	username := mux.Vars(r)["username"]
+	user, _, _ := r.BasicAuth()
+	if username != user {
+		return "", structs.NewError(structs.KindForbidden, "users can only access their own account")
+	}
+	return username, nil
+}
+
+func (rest *Rest) getUserV2(w http.ResponseWriter, r *http.Request) {
+	username, err := ownUser(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), username)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: newUser(structs.HashedInfo{Username: username, Location: loc})})
+}
+
+type timezoneChange struct {
+	Timezone string              `json:"timezone"`
+	Rebase   structs.RebaseScope `json:"rebase"`
+	Events   []int               `json:"events"`
+	DryRun   bool                `json:"dry_run"`
+}
+
+// UserRelocation is the user after a change of timezone with the events that were rebased.
+type UserRelocation struct {
+	User
+	Rebase structs.RebaseScope    `json:"rebase"`
+	DryRun bool                   `json:"dry_run"`
+	Events []structs.RebasedEvent `json:"events"`
+}
+
+func (rest *Rest) changeTimezoneV2(w http.ResponseWriter, r *http.Request) {
+	if _, err := ownUser(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		rest.sendError(w, r, structs.NewValidationError("Invalid Data Format"))
+		return
+	}
+	var change timezoneChange
+	if err = json.Unmarshal(data, &change); err != nil {
+		rest.sendError(w, r, structs.WrapError(structs.KindValidation, err))
+		return
+	}
+	relocation, err := rest.moveUser(r, change.Timezone, structs.RebaseOptions{
+		Scope:    change.Rebase,
+		EventIDs: change.Events,
+		DryRun:   change.DryRun,
+	})
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: UserRelocation{
+		User:   newUser(relocation.User),
+		Rebase: relocation.Scope,
+		DryRun: relocation.DryRun,
+		Events: relocation.Events,
+	}})
+}
+
+func (rest *Rest) addEventV2(w http.ResponseWriter, r *http.Request) {
+	event, err := rest.createEvent(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/v2/events/"+strconv.Itoa(event.Id))
+	rest.sendEnvelope(w, http.StatusCreated, Envelope{Data: event})
+}
+
+func (rest *Rest) allEventsV2(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, err := loadPagination(query)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	// pages are only stable when the order is
+	query.Set("sorting", "true")
+	events, err := rest.findEvents(r, query)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+
+	page.Total = len(events)
+	from, to := page.Offset, page.Offset+page.Limit
+	if from > len(events) {
+		from = len(events)
+	}
+	if to > len(events) {
+		to = len(events)
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{
+		Data: events[from:to],
+		Meta: &Meta{Pagination: &page},
+	})
+}
+
+func loadPagination(query url.Values) (Pagination, error) {
+	page := Pagination{Limit: defaultPageLimit}
+	var fields []structs.FieldError
+	if values := query["limit"]; len(values) > 0 {
+		limit, err := strconv.Atoi(values[0])
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			fields = append(fields, structs.FieldError{Field: "limit",
+				Message: "must be a number between 1 and " + strconv.Itoa(maxPageLimit)})
+		}
+		page.Limit = limit
+	}
+	if values := query["offset"]; len(values) > 0 {
+		offset, err := strconv.Atoi(values[0])
+		if err != nil || offset < 0 {
+			fields = append(fields, structs.FieldError{Field: "offset", Message: "must be a positive number"})
+		}
+		page.Offset = offset
+	}
+	if len(fields) > 0 {
+		return Pagination{}, structs.NewValidationError("invalid pagination parameters", fields...)
+	}
+	return page, nil
+}
+
+func (rest *Rest) getEventV2(w http.ResponseWriter, r *http.Request) {
+	id, err := eventID(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	event, err := rest.service.Events.GetById(r.Context(), id, user, loc)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: event})
+}
+
+func (rest *Rest) updateEventV2(w http.ResponseWriter, r *http.Request) {
+	event, err := rest.replaceEvent(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: event})
+}
+
+func (rest *Rest) deleteEventV2(w http.ResponseWriter, r *http.Request) {
+	if err := rest.removeEvent(r); err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rest *Rest) sendEnvelope(w http.ResponseWriter, statusCode int, envelope Envelope) {
+	bytes, err := json.Marshal(envelope)
+	if err != nil {
+		logger.Errorf("writing response : %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err = w.Write(bytes)
+	if err != nil {
+		logger.Errorf("writing response : %v", err)
+	}
+}
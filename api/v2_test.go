@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLoadPagination(t *testing.T) {
+	testCases := map[string]struct {
+		query       string
+		limit       int
+		offset      int
+		errExpected bool
+	}{
+		"Defaults":          {"", defaultPageLimit, 0, false},
+		"Limit and offset":  {"limit=10&offset=20", 10, 20, false},
+		"Zero limit":        {"limit=0", 0, 0, true},
+		"Limit too large":   {"limit=501", 0, 0, true},
+		"Negative offset":   {"offset=-1", 0, 0, true},
+		"Offset not number": {"offset=first", 0, 0, true},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			query, _ := url.ParseQuery(test.query)
+			page, err := loadPagination(query)
+			if (err != nil) != test.errExpected {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err == nil && (page.Limit != test.limit || page.Offset != test.offset) {
+				t.Errorf("got limit %v offset %v, wanted %v %v", page.Limit, page.Offset, test.limit, test.offset)
+			}
+		})
+	}
+}
+
+func TestNewTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	winter := newTimezone(*loc, time.Date(2021, 1, 10, 12, 0, 0, 0, time.UTC))
+	summer := newTimezone(*loc, time.Date(2021, 7, 10, 12, 0, 0, 0, time.UTC))
+	if winter != (Timezone{Name: "America/New_York", Abbreviation: "EST", UTCOffset: "-05:00"}) {
+		t.Errorf("wrong winter time zone %+v", winter)
+	}
+	if summer != (Timezone{Name: "America/New_York", Abbreviation: "EDT", UTCOffset: "-04:00"}) {
+		t.Errorf("wrong summer time zone %+v", summer)
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/gorilla/mux"
+)
+
+// calendarViews are the views events can be laid out in, they are the last part of their path.
+const calendarViews = "{view:agenda|day|week|month}"
+
+func (rest *Rest) calendarView(w http.ResponseWriter, r *http.Request) {
+	view, err := rest.findView(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendData(w, view)
+}
+
+func (rest *Rest) calendarViewV2(w http.ResponseWriter, r *http.Request) {
+	view, err := rest.findView(r)
+	if err != nil {
+		rest.sendError(w, r, err)
+		return
+	}
+	rest.sendEnvelope(w, http.StatusOK, Envelope{Data: view})
+}
+
+// findView lays the events of the requesting user out by their local days. ?date= picks
+// the day the view is around, ?week_start= the weekday weeks begin on, ?days= the length
+// of an agenda and ?holidays= the country whose holidays are shown.
+func (rest *Rest) findView(r *http.Request) (structs.CalendarView, error) {
+	user, _, _ := r.BasicAuth()
+	loc, err := rest.service.Users.GetUserLocation(r.Context(), user)
+	if err != nil {
+		return structs.CalendarView{}, err
+	}
+	query := r.URL.Query()
+	return rest.service.Views.GetView(r.Context(), user, mux.Vars(r)["view"], query.Get("date"), query.Get("days"),
+		query.Get("week_start"), query.Get("holidays"), loc)
+}
@@ -1,48 +1,272 @@
 package app
 
 import (
-	"os"
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/dkucheru/Calendar/api"
+	"github.com/dkucheru/Calendar/config"
 	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/metrics"
 	"github.com/dkucheru/Calendar/service"
 )
 
+// Version is set at build time with -ldflags "-X github.com/dkucheru/Calendar/app.Version=..."
+var Version = "dev"
+
+// idempotencyCleanupInterval is the longest time expired idempotency keys are kept.
+const idempotencyCleanupInterval = 10 * time.Minute
+
+// alertDispatchInterval is the longest an alert that is due waits to be delivered.
+const alertDispatchInterval = 15 * time.Second
+
 type App struct {
-	EventsRepo db.EventsRepository
-	UsersRepo  db.UserRepository
-	Service    *service.Service
-	Api        *api.Rest
+	EventsRepo      db.EventsRepository
+	UsersRepo       db.UserRepository
+	IdempotencyRepo db.IdempotencyRepository
+	RelocationRepo  db.RelocationRepository
+	AlertsRepo      db.AlertsRepository
+	ResourcesRepo   db.ResourcesRepository
+	BookingRepo     db.BookingRepository
+	TasksRepo       db.TasksRepository
+	Service         *service.Service
+	Api             *api.Rest
+	Metrics         *metrics.Registry
+	database        *sql.DB
+	conf            *config.Config
+	workers         context.Context
+	stopWorkers     context.CancelFunc
 }
 
-func New(downMigrateFlag bool) (*App, error) {
+func New(conf *config.Config) (*App, error) {
 	var err error
-	app := &App{}
+	app := &App{conf: conf}
+	app.workers, app.stopWorkers = context.WithCancel(context.Background())
 
-	database, err := db.Initialize(os.Getenv("DSN"), downMigrateFlag)
+	level, err := logger.ParseLevel(conf.LogLevel)
 	if err != nil {
 		return nil, err
 	}
-	app.EventsRepo, err = db.NewDatabaseRepository(database)
-	if err != nil {
+	logger.SetLevel(level)
+	if err = logger.SetFormat(conf.LogFormat); err != nil {
 		return nil, err
 	}
 
-	app.UsersRepo, err = db.NewUsersDBRepository(database)
-	if err != nil {
+	if err = app.setupStorage(conf); err != nil {
 		return nil, err
 	}
+	app.Metrics = metrics.NewRegistry()
+	repositoryMetrics := db.NewRepositoryMetrics(app.Metrics)
+	app.EventsRepo = db.NewInstrumentedEventsRepository(app.EventsRepo, repositoryMetrics)
+	app.UsersRepo = db.NewInstrumentedUserRepository(app.UsersRepo, repositoryMetrics)
+	app.IdempotencyRepo = db.NewInstrumentedIdempotencyRepository(app.IdempotencyRepo, repositoryMetrics)
+	app.RelocationRepo = db.NewInstrumentedRelocationRepository(app.RelocationRepo, repositoryMetrics)
+	app.AlertsRepo = db.NewInstrumentedAlertsRepository(app.AlertsRepo, repositoryMetrics)
+	app.ResourcesRepo = db.NewInstrumentedResourcesRepository(app.ResourcesRepo, repositoryMetrics)
+	app.BookingRepo = db.NewInstrumentedBookingRepository(app.BookingRepo, repositoryMetrics)
+	app.TasksRepo = db.NewInstrumentedTasksRepository(app.TasksRepo, repositoryMetrics)
 
-	app.Service = service.NewService(&service.Config{EventsRepo: app.EventsRepo, UsersRepo: app.UsersRepo})
+	app.Service = service.NewService(&service.Config{
+		EventsRepo:      app.EventsRepo,
+		UsersRepo:       app.UsersRepo,
+		IdempotencyRepo: app.IdempotencyRepo,
+		IdempotencyTTL:  conf.IdempotencyTTL,
+		RelocationRepo:  app.RelocationRepo,
+		AlertsRepo:      app.AlertsRepo,
+		ResourcesRepo:   app.ResourcesRepo,
+		BookingRepo:     app.BookingRepo,
+		TasksRepo:       app.TasksRepo,
+		WeekStart:       conf.WeekStart,
+	})
 
-	app.Api = api.New(":8080", app.Service)
+	app.Api = api.New(&api.Config{
+		Address:         conf.Address,
+		ReadTimeout:     conf.ReadTimeout,
+		WriteTimeout:    conf.WriteTimeout,
+		ShutdownTimeout: conf.ShutdownTimeout,
+		RequestTimeout:  conf.RequestTimeout,
+		Version:         Version,
+		Metrics:         app.Metrics,
+	}, app.Service)
+	app.registerDiagnostics(conf)
 	return app, nil
 }
 
+func (a *App) registerDiagnostics(conf *config.Config) {
+	a.Api.AddDebugInfo("storage", func(ctx context.Context) (interface{}, error) {
+		return conf.Storage, nil
+	})
+	a.Api.AddDebugInfo("idempotency", func(ctx context.Context) (interface{}, error) {
+		return a.Service.Idempotency.Stats(), nil
+	})
+	if a.database == nil {
+		return
+	}
+
+	a.Api.AddReadinessCheck("database", a.database.PingContext)
+	a.Api.AddReadinessCheck("migrations", func(ctx context.Context) error {
+		status, err := db.GetMigrationStatus(ctx, a.database)
+		if err != nil {
+			return err
+		}
+		for _, m := range status {
+			if !m.Applied {
+				return fmt.Errorf("migration %v is not applied", m.Id)
+			}
+		}
+		return nil
+	})
+
+	a.Api.AddDebugInfo("database_pool", func(ctx context.Context) (interface{}, error) {
+		return a.database.Stats(), nil
+	})
+	a.Api.AddDebugInfo("migrations", func(ctx context.Context) (interface{}, error) {
+		status, err := db.GetMigrationStatus(ctx, a.database)
+		if err != nil {
+			return nil, err
+		}
+		applied := make([]string, 0, len(status))
+		for _, m := range status {
+			if m.Applied {
+				applied = append(applied, m.Id)
+			}
+		}
+		return applied, nil
+	})
+}
+
+func (a *App) setupStorage(conf *config.Config) (err error) {
+	logger.Infof("using %v storage", conf.Storage)
+	switch conf.Storage {
+	case config.StorageMemory:
+		if a.IdempotencyRepo, err = db.NewIdempotencyInMemoryRepository(); err != nil {
+			return err
+		}
+		if a.EventsRepo, err = db.NewMapRepository(); err != nil {
+			return err
+		}
+		if a.UsersRepo, err = db.NewUsersInMemoryRepository(); err != nil {
+			return err
+		}
+		if a.AlertsRepo, err = db.NewAlertsInMemoryRepository(); err != nil {
+			return err
+		}
+		if a.ResourcesRepo, err = db.NewResourcesInMemoryRepository(); err != nil {
+			return err
+		}
+		if a.BookingRepo, err = db.NewBookingInMemoryRepository(a.EventsRepo); err != nil {
+			return err
+		}
+		if a.TasksRepo, err = db.NewTasksInMemoryRepository(); err != nil {
+			return err
+		}
+		a.RelocationRepo, err = db.NewRelocationInMemoryRepository(a.UsersRepo, a.EventsRepo)
+		return err
+	case config.StorageFile:
+		if a.EventsRepo, err = db.NewEventsFileRepository(filepath.Join(conf.DataDir, "events.json")); err != nil {
+			return err
+		}
+		if a.UsersRepo, err = db.NewUsersFileRepository(filepath.Join(conf.DataDir, "users.json")); err != nil {
+			return err
+		}
+		if a.AlertsRepo, err = db.NewAlertsFileRepository(filepath.Join(conf.DataDir, "alerts.json")); err != nil {
+			return err
+		}
+		if a.ResourcesRepo, err = db.NewResourcesFileRepository(filepath.Join(conf.DataDir, "resources.json")); err != nil {
+			return err
+		}
+		if a.BookingRepo, err = db.NewBookingFileRepository(filepath.Join(conf.DataDir, "bookings.json"), a.EventsRepo); err != nil {
+			return err
+		}
+		if a.TasksRepo, err = db.NewTasksFileRepository(filepath.Join(conf.DataDir, "tasks.json")); err != nil {
+			return err
+		}
+		if a.IdempotencyRepo, err = db.NewIdempotencyFileRepository(filepath.Join(conf.DataDir, "idempotency.json")); err != nil {
+			return err
+		}
+		a.RelocationRepo, err = db.NewRelocationInMemoryRepository(a.UsersRepo, a.EventsRepo)
+		return err
+	}
+
+	a.database, err = db.Connect(conf.DSN)
+	if err != nil {
+		return err
+	}
+	if conf.Migrate == config.MigrateUp {
+		if _, err = db.MigrateUp(a.database, 0); err != nil {
+			return err
+		}
+	}
+	eventsRepo, err := db.NewDatabaseRepository(a.database)
+	if err != nil {
+		return err
+	}
+	eventsRepo.QueryTimeout = conf.QueryTimeout
+	usersRepo, err := db.NewUsersDBRepository(a.database)
+	if err != nil {
+		return err
+	}
+	usersRepo.QueryTimeout = conf.QueryTimeout
+	idempotencyRepo, err := db.NewIdempotencyDBRepository(a.database)
+	if err != nil {
+		return err
+	}
+	idempotencyRepo.QueryTimeout = conf.QueryTimeout
+	relocationRepo, err := db.NewRelocationDBRepository(a.database)
+	if err != nil {
+		return err
+	}
+	relocationRepo.QueryTimeout = conf.QueryTimeout
+	alertsRepo, err := db.NewAlertsDBRepository(a.database)
+	if err != nil {
+		return err
+	}
+	alertsRepo.QueryTimeout = conf.QueryTimeout
+	resourcesRepo, err := db.NewResourcesDBRepository(a.database)
+	if err != nil {
+		return err
+	}
+	resourcesRepo.QueryTimeout = conf.QueryTimeout
+	bookingRepo, err := db.NewBookingDBRepository(a.database)
+	if err != nil {
+		return err
+	}
+	bookingRepo.QueryTimeout = conf.QueryTimeout
+	tasksRepo, err := db.NewTasksDBRepository(a.database)
+	if err != nil {
+		return err
+	}
+	tasksRepo.QueryTimeout = conf.QueryTimeout
+	a.EventsRepo, a.UsersRepo, a.IdempotencyRepo = eventsRepo, usersRepo, idempotencyRepo
+	a.RelocationRepo, a.AlertsRepo, a.ResourcesRepo = relocationRepo, alertsRepo, resourcesRepo
+	a.BookingRepo, a.TasksRepo = bookingRepo, tasksRepo
+	return nil
+}
+
+// Run starts the background workers and serves the api until Stop is called.
 func (a *App) Run() error {
+	interval := idempotencyCleanupInterval
+	if a.conf.IdempotencyTTL < interval {
+		interval = a.conf.IdempotencyTTL
+	}
+	cleanup := service.NewHeartbeat(interval)
+	a.Api.AddWorkerCheck("idempotency_cleanup", cleanup)
+	go a.Service.Idempotency.RunCleanup(a.workers, cleanup)
+	dispatcher := service.NewHeartbeat(alertDispatchInterval)
+	a.Api.AddWorkerCheck("alert_dispatcher", dispatcher)
+	go a.Service.Alerts.RunDispatcher(a.workers, dispatcher)
+
 	return a.Api.Listen()
 }
 
 func (a *App) Stop() {
+	a.stopWorkers()
 	a.Api.Stop()
+	if a.database != nil {
+		a.database.Close()
+	}
 }
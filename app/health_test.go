@@ -0,0 +1,57 @@
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/config"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	conf := config.Default()
+	conf.Storage = config.StorageMemory
+	conf.Address = "localhost:8181"
+	app, err := New(conf)
+	if err != nil {
+		t.Fatalf("error launching app : %v", err)
+	}
+	go app.Run()
+	defer app.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	testCases := map[string]struct {
+		url        string
+		codeExpect int
+		contains   string
+	}{
+		"Liveness": {
+			"/healthz", 200, `"Data":"ok"`,
+		},
+		"Readiness of the workers": {
+			"/readyz", 200, `{"name":"alert_dispatcher","status":"ok"}`,
+		},
+		"Debug info requires authentication": {
+			"/debug/info", 401, `"type":"/problems/unauthorized"`,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			response, err := http.Get("http://" + conf.Address + test.url)
+			if err != nil {
+				t.Fatalf("error sending request : %v", err)
+			}
+			defer response.Body.Close()
+			body, _ := ioutil.ReadAll(response.Body)
+			if response.StatusCode != test.codeExpect {
+				t.Errorf("unexpected response code %v, wanted %v", response.StatusCode, test.codeExpect)
+			}
+			if !strings.Contains(string(body), test.contains) {
+				t.Errorf("response %q does not contain %q", string(body), test.contains)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/dkucheru/Calendar/api"
+	"github.com/dkucheru/Calendar/config"
 	"github.com/dkucheru/Calendar/structs"
 )
 
@@ -40,8 +42,8 @@ func TestAddingUserIntegration(t *testing.T) {
 				"password": "12345678",
 				"location": "Local"
 			 }`),
-			500,
-			`{"Status":500,"Data":"postgres error : error occured when adding new user : pq: duplicate key value violates unique constraint \"users_pkey\""}`,
+			409,
+			`{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"record dublicate : user with username [userExists] already exists ","instance":"/users"}`,
 		},
 		"No location": {
 			[]byte(`{
@@ -49,21 +51,20 @@ func TestAddingUserIntegration(t *testing.T) {
 				"password": "12345678"
 			 }`),
 			400,
-			`{"Status":400,"Data":"validator : Invalid Data Format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"validator : Invalid Data Format","instance":"/users","fields":[{"field":"location","message":"failed on the 'required' rule"}]}`,
 		},
 		"Bad request body": {
 			[]byte(`{
 			 }`),
 			400,
-			`{"Status":400,"Data":"validator : Invalid Data Format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"validator : Invalid Data Format","instance":"/users","fields":[{"field":"username","message":"failed on the 'required' rule"},{"field":"password","message":"failed on the 'required' rule"},{"field":"location","message":"failed on the 'required' rule"}]}`,
 		},
 	}
 	client := http.Client{}
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			downMigrate := false
-			app, err := New(downMigrate)
+			app, err := New(testConfig())
 			if err != nil {
 				t.Errorf("error launching app : " + err.Error())
 				return
@@ -93,9 +94,9 @@ func TestAddingUserIntegration(t *testing.T) {
 				t.Errorf(err.Error())
 			}
 
-			app.UsersRepo.AddUser(structs.CreateUser{Username: "userExists", Password: "12345678", Location: "Local"})
+			app.UsersRepo.AddUser(context.Background(), structs.CreateUser{Username: "userExists", Password: "12345678", Location: "Local"})
 
-			received, getUserErr := app.UsersRepo.GetUser("test")
+			received, getUserErr := app.UsersRepo.GetUser(context.Background(), "test")
 			if getUserErr == nil && received.Username != "test" {
 				t.Errorf("wanted user %s, received user %s", "test", received.Username)
 			}
@@ -138,7 +139,7 @@ func TestAddingEventIntegration(t *testing.T) {
 				"alert": "2019-12-10T14:00:00.000Z"
 			 }`),
 			400,
-			`{"Status":400,"Data":"validator : invalid data format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"validator : invalid data format","instance":"/events","fields":[{"field":"name","message":"failed on the 'required' rule"}]}`,
 		},
 		"No start time event": {
 			[]byte(`{
@@ -148,7 +149,7 @@ func TestAddingEventIntegration(t *testing.T) {
 				"alert": "2019-12-10T14:00:00.000Z"
 			 }`),
 			400,
-			`{"Status":400,"Data":"validator : invalid data format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"validator : invalid data format","instance":"/events","fields":[{"field":"start","message":"failed on the 'required' rule"}]}`,
 		},
 		"No end time event": {
 			[]byte(`{
@@ -158,7 +159,7 @@ func TestAddingEventIntegration(t *testing.T) {
 				"alert": "2019-12-10T14:00:00.000Z"
 			 }`),
 			400,
-			`{"Status":400,"Data":"validator : invalid data format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"validator : invalid data format","instance":"/events","fields":[{"field":"end","message":"failed on the 'required' rule"}]}`,
 		},
 		"No mandatory fields": {
 			[]byte(`{
@@ -166,7 +167,7 @@ func TestAddingEventIntegration(t *testing.T) {
 				"alert": "2019-12-10T14:00:00.000Z"
 			 }`),
 			400,
-			`{"Status":400,"Data":"validator : invalid data format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"validator : invalid data format","instance":"/events","fields":[{"field":"name","message":"failed on the 'required' rule"},{"field":"start","message":"failed on the 'required' rule"},{"field":"end","message":"failed on the 'required' rule"}]}`,
 		},
 		"No alert time": {
 			[]byte(`{
@@ -205,8 +206,7 @@ func TestAddingEventIntegration(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			//preset
 
-			downMigrate := false
-			app, err := New(downMigrate)
+			app, err := New(testConfig())
 			if err != nil {
 				t.Errorf("error launching app : " + err.Error())
 				return
@@ -235,7 +235,7 @@ func TestAddingEventIntegration(t *testing.T) {
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.UsersRepo.AddUser(structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
+			_, err = app.UsersRepo.AddUser(context.Background(), structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
 			if err != nil {
 				t.Errorf(err.Error())
 			}
@@ -325,11 +325,11 @@ func TestChangingUserLocationIntegration(t *testing.T) {
 	}{
 		"Bad location var": {
 			"/users/test?location=amEriGa", 400,
-			`{"Status":400,"Data":"Invalid location parameter"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"Invalid location parameter","instance":"/users/test","fields":[{"field":"location","message":"must be a known IANA time zone name"}]}`,
 		},
 		"User does not exist": {
 			"/users/fakeUser?location=America/New_York", 404,
-			`{"Status":404,"Data":"no matching record : user with username [fakeUser] does not exist "}`,
+			`{"type":"/problems/not-found","title":"Resource not found","status":404,"detail":"no matching record : user with username [fakeUser] does not exist ","instance":"/users/fakeUser"}`,
 		},
 		"Ok user ok location": {
 			"/users/test?location=America/New_York",
@@ -342,8 +342,7 @@ func TestChangingUserLocationIntegration(t *testing.T) {
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
 			//preset
-			downMigrate := false
-			app, err := New(downMigrate)
+			app, err := New(testConfig())
 			if err != nil {
 				t.Errorf("error launching app : " + err.Error())
 			}
@@ -371,7 +370,7 @@ func TestChangingUserLocationIntegration(t *testing.T) {
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.UsersRepo.AddUser(structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
+			_, err = app.UsersRepo.AddUser(context.Background(), structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
 			if err != nil {
 				t.Errorf(err.Error())
 			}
@@ -417,12 +416,12 @@ func TestDeleteEventIntegration(t *testing.T) {
 		response   string
 	}{
 		"Bad event id": {
-			"/events/1on1", 400, `{"Status":400,"Data":"Invalid Data Format"}`,
+			"/events/1on1", 400, `{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"Invalid Data Format","instance":"/events/1on1"}`,
 		},
 		"Event does not exist": {
 			"/events/-1",
 			404,
-			`{"Status":404,"Data":"no matching record : event with id [-1] does not exist "}`,
+			`{"type":"/problems/not-found","title":"Resource not found","status":404,"detail":"no matching record : event with id [-1] does not exist ","instance":"/events/-1"}`,
 		},
 		"Ok delete event": {
 			"/events/1",
@@ -435,8 +434,7 @@ func TestDeleteEventIntegration(t *testing.T) {
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
 			//preset
-			downMigrate := false
-			app, err := New(downMigrate)
+			app, err := New(testConfig())
 			if err != nil {
 				t.Errorf("error launching app : " + err.Error())
 			}
@@ -464,19 +462,19 @@ func TestDeleteEventIntegration(t *testing.T) {
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.UsersRepo.AddUser(structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
+			_, err = app.UsersRepo.AddUser(context.Background(), structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.EventsRepo.Add(structs.Event{Name: "Ok event1", Start: time.Now(), End: time.Now().Add(time.Hour)})
+			_, err = app.EventsRepo.Add(context.Background(), structs.Event{Name: "Ok event1", Start: time.Now(), End: time.Now().Add(time.Hour)})
 			if err != nil {
 				t.Errorf("error occured when adding user; error : " + err.Error())
 			}
-			_, err = app.EventsRepo.Add(structs.Event{Name: "Ok event2", Start: time.Now(), End: time.Now().Add(time.Hour)})
+			_, err = app.EventsRepo.Add(context.Background(), structs.Event{Name: "Ok event2", Start: time.Now(), End: time.Now().Add(time.Hour)})
 			if err != nil {
 				t.Errorf("error occured when adding user; error : " + err.Error())
 			}
-			_, err = app.EventsRepo.Add(structs.Event{Name: "Ok event3", Start: time.Now(), End: time.Now().Add(time.Hour)})
+			_, err = app.EventsRepo.Add(context.Background(), structs.Event{Name: "Ok event3", Start: time.Now(), End: time.Now().Add(time.Hour)})
 			if err != nil {
 				t.Errorf("error occured when adding user; error : " + err.Error())
 			}
@@ -539,7 +537,7 @@ func TestGettingEventsIntegration(t *testing.T) {
 		"Bad date parameters": {
 			"/events?day=today&month=thisMonth&year=current",
 			400,
-			`{"Status":400,"Data":"error parsing date part value"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"error parsing date part value","instance":"/events","fields":[{"field":"day","message":"must be a number"}]}`,
 		},
 	}
 	client := http.Client{}
@@ -547,8 +545,7 @@ func TestGettingEventsIntegration(t *testing.T) {
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
 			//preset
-			downMigrate := false
-			app, err := New(downMigrate)
+			app, err := New(testConfig())
 			if err != nil {
 				t.Errorf("error launching app : " + err.Error())
 			}
@@ -576,19 +573,19 @@ func TestGettingEventsIntegration(t *testing.T) {
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.UsersRepo.AddUser(structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
+			_, err = app.UsersRepo.AddUser(context.Background(), structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.EventsRepo.Add(structs.Event{Name: "Ok event", Start: today, End: today.Add(time.Hour)})
+			_, err = app.EventsRepo.Add(context.Background(), structs.Event{Name: "Ok event", Start: today, End: today.Add(time.Hour)})
 			if err != nil {
 				t.Errorf("error occured when starting the app; error : " + err.Error())
 			}
-			_, err = app.EventsRepo.Add(structs.Event{Name: "was yesterday", Start: yesterday, End: yesterday.Add(time.Hour)})
+			_, err = app.EventsRepo.Add(context.Background(), structs.Event{Name: "was yesterday", Start: yesterday, End: yesterday.Add(time.Hour)})
 			if err != nil {
 				t.Errorf("error occured when starting the app; error : " + err.Error())
 			}
-			_, err = app.EventsRepo.Add(structs.Event{Name: "will be in 2 hours", Start: today.Add(time.Hour * 2), End: today.Add(time.Hour * 3)})
+			_, err = app.EventsRepo.Add(context.Background(), structs.Event{Name: "will be in 2 hours", Start: today.Add(time.Hour * 2), End: today.Add(time.Hour * 3)})
 			if err != nil {
 				t.Errorf("error occured when starting the app; error : " + err.Error())
 			}
@@ -665,7 +662,7 @@ func TestUpdatingEventIntegration(t *testing.T) {
 				"alert": "2019-12-10T14:00:00.000Z"
 			 }`),
 			404,
-			`{"Status":404,"Data":"no matching record : event with id [-1] does not exist "}`,
+			`{"type":"/problems/not-found","title":"Resource not found","status":404,"detail":"no matching record : event with id [-1] does not exist ","instance":"/events/-1"}`,
 		},
 		"Bad id": {
 			"/events/first",
@@ -677,14 +674,14 @@ func TestUpdatingEventIntegration(t *testing.T) {
 				"alert": "2019-12-10T14:00:00.000Z"
 			 }`),
 			400,
-			`{"Status":400,"Data":"Invalid Data Format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"Invalid Data Format","instance":"/events/first"}`,
 		},
 		"Bad input": {
 			"/events/1",
 			[]byte(`{
 				,}`),
 			400,
-			`{"Status":400,"Data":"invalid character ',' looking for beginning of object key string"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"invalid character ',' looking for beginning of object key string","instance":"/events/1"}`,
 		},
 		"No mandatory fields": {
 			"/events/1",
@@ -693,7 +690,7 @@ func TestUpdatingEventIntegration(t *testing.T) {
 				"alert": "2019-12-10T14:00:00.000Z"
 			 }`),
 			400,
-			`{"Status":400,"Data":"validator : invalid data format"}`,
+			`{"type":"/problems/validation","title":"Invalid request","status":400,"detail":"validator : invalid data format","instance":"/events/1","fields":[{"field":"name","message":"failed on the 'required' rule"},{"field":"start","message":"failed on the 'required' rule"},{"field":"end","message":"failed on the 'required' rule"}]}`,
 		},
 		"Ok updated event": {
 			"/events/1",
@@ -712,8 +709,7 @@ func TestUpdatingEventIntegration(t *testing.T) {
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
 			//preset
-			downMigrate := false
-			app, err := New(downMigrate)
+			app, err := New(testConfig())
 			if err != nil {
 				t.Errorf("error launching app : " + err.Error())
 			}
@@ -741,11 +737,11 @@ func TestUpdatingEventIntegration(t *testing.T) {
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.UsersRepo.AddUser(structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
+			_, err = app.UsersRepo.AddUser(context.Background(), structs.CreateUser{Username: "test", Password: "12345678", Location: "Local"})
 			if err != nil {
 				t.Errorf(err.Error())
 			}
-			_, err = app.EventsRepo.Add(structs.Event{Name: "Ok event", Start: time.Now(), End: time.Now().Add(time.Hour)})
+			_, err = app.EventsRepo.Add(context.Background(), structs.Event{Name: "Ok event", Start: time.Now(), End: time.Now().Add(time.Hour)})
 
 			if err != nil {
 				t.Errorf("error occured when adding event : " + err.Error())
@@ -810,3 +806,9 @@ func TestUpdatingEventIntegration(t *testing.T) {
 		})
 	}
 }
+
+func testConfig() *config.Config {
+	conf := config.Default()
+	conf.DSN = os.Getenv("DSN")
+	return conf
+}
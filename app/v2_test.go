@@ -0,0 +1,185 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/api"
+	"github.com/dkucheru/Calendar/config"
+)
+
+func TestVersionedRoutes(t *testing.T) {
+	conf := config.Default()
+	conf.Storage = config.StorageMemory
+	conf.Address = "localhost:8182"
+	app, err := New(conf)
+	if err != nil {
+		t.Fatalf("error launching app : %v", err)
+	}
+	go app.Run()
+	defer app.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	steps := []struct {
+		name        string
+		method      string
+		url         string
+		body        string
+		codeExpect  int
+		errExpected bool
+		total       int
+	}{
+		{"Add user", "POST", "/v2/users", `{"username":"test","password":"12345678","location":"UTC"}`, 201, false, -1},
+		{"Add event", "POST", "/v2/events", `{"name":"first","start":"2021-12-10T13:45:00Z","end":"2021-12-10T14:00:00Z"}`, 201, false, -1},
+		{"Add event with v1 prefix", "POST", "/v1/events", `{"name":"second","start":"2021-12-11T13:45:00Z","end":"2021-12-11T14:00:00Z"}`, 200, false, -1},
+		{"List events", "GET", "/v2/events?limit=1", "", 200, false, 2},
+		{"Invalid event", "POST", "/v2/events", `{"name":"third"}`, 400, true, -1},
+		{"Other user", "GET", "/v2/users/other", "", 403, true, -1},
+		{"Delete event", "DELETE", "/v2/events/1", "", 204, false, -1},
+		{"Deleted event", "GET", "/v2/events/1", "", 404, true, -1},
+	}
+
+	client := http.Client{}
+	for _, step := range steps {
+		req, _ := http.NewRequest(step.method, "http://"+conf.Address+step.url, bytes.NewBufferString(step.body))
+		req.SetBasicAuth("test", "12345678")
+		response, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%v : error sending request : %v", step.name, err)
+		}
+		if response.StatusCode != step.codeExpect {
+			t.Errorf("%v : unexpected response code %v, wanted %v", step.name, response.StatusCode, step.codeExpect)
+		}
+		if step.codeExpect == http.StatusNoContent || step.url[:3] != "/v2" {
+			response.Body.Close()
+			continue
+		}
+		var envelope api.Envelope
+		if err = json.NewDecoder(response.Body).Decode(&envelope); err != nil {
+			t.Errorf("%v : response is not an envelope : %v", step.name, err)
+		}
+		response.Body.Close()
+		if (envelope.Error != nil) != step.errExpected {
+			t.Errorf("%v : unexpected error %+v", step.name, envelope.Error)
+		}
+		if step.total >= 0 && (envelope.Meta == nil || envelope.Meta.Pagination.Total != step.total) {
+			t.Errorf("%v : wrong pagination %+v", step.name, envelope.Meta)
+		}
+	}
+}
+
+func TestRetriedBooking(t *testing.T) {
+	conf := config.Default()
+	conf.Storage = config.StorageMemory
+	conf.Address = "localhost:8183"
+	app, err := New(conf)
+	if err != nil {
+		t.Fatalf("error launching app : %v", err)
+	}
+	go app.Run()
+	defer app.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	monday := time.Now().UTC().AddDate(0, 0, 7)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	start := time.Date(monday.Year(), monday.Month(), monday.Day(), 9, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	booking := `{"start":"` + start + `","name":"Bob","email":"bob@example.com"}`
+
+	steps := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		key        string
+		codeExpect int
+		replayed   bool
+	}{
+		{"Add user", "POST", "/v2/users", `{"username":"test","password":"12345678","location":"UTC"}`, "", 201, false},
+		{"Add page", "PUT", "/v2/users/test/booking-pages/intro", `{"title":"Intro","duration":"30m","windows":{"monday":[{"start":"09:00","end":"10:00"}]}}`, "", 200, false},
+		{"Book", "POST", "/v2/book/intro", booking, "guest-key", 201, false},
+		{"Retry the booking", "POST", "/v2/book/intro", booking, "guest-key", 201, true},
+		{"Book again without a key", "POST", "/v2/book/intro", booking, "", 409, false},
+	}
+
+	client := http.Client{}
+	var token string
+	for _, step := range steps {
+		req, _ := http.NewRequest(step.method, "http://"+conf.Address+step.url, bytes.NewBufferString(step.body))
+		if step.url[:8] != "/v2/book" {
+			req.SetBasicAuth("test", "12345678")
+		}
+		if step.key != "" {
+			req.Header.Set("Idempotency-Key", step.key)
+		}
+		response, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%v : error sending request : %v", step.name, err)
+		}
+		var envelope struct {
+			Data struct {
+				CancelToken string `json:"cancel_token"`
+			} `json:"data"`
+		}
+		json.NewDecoder(response.Body).Decode(&envelope)
+		response.Body.Close()
+		if response.StatusCode != step.codeExpect {
+			t.Errorf("%v : unexpected response code %v, wanted %v", step.name, response.StatusCode, step.codeExpect)
+		}
+		if replayed := response.Header.Get("Idempotent-Replayed") == "true"; replayed != step.replayed {
+			t.Errorf("%v : replayed is %v, wanted %v", step.name, replayed, step.replayed)
+		}
+		if step.replayed && envelope.Data.CancelToken != token {
+			t.Errorf("%v : got cancel token %q, wanted %q", step.name, envelope.Data.CancelToken, token)
+		}
+		token = envelope.Data.CancelToken
+	}
+}
+
+func TestRetriedEventCreation(t *testing.T) {
+	conf := config.Default()
+	conf.Storage = config.StorageMemory
+	conf.Address = "localhost:8184"
+	app, err := New(conf)
+	if err != nil {
+		t.Fatalf("error launching app : %v", err)
+	}
+	go app.Run()
+	defer app.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := http.Client{}
+	send := func(url string, body string, key string) *http.Response {
+		req, _ := http.NewRequest("POST", "http://"+conf.Address+url, bytes.NewBufferString(body))
+		req.SetBasicAuth("test", "12345678")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		response, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("error sending request : %v", err)
+		}
+		response.Body.Close()
+		return response
+	}
+	send("/v2/users", `{"username":"test","password":"12345678","location":"UTC"}`, "")
+
+	event := `{"name":"first","start":"2021-12-10T13:45:00Z","end":"2021-12-10T14:00:00Z"}`
+	first := send("/v2/events", event, "event-key")
+	retried := send("/v2/events", event, "event-key")
+	if retried.StatusCode != http.StatusCreated || retried.Header.Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected a replayed 201, got %v replayed %q", retried.StatusCode, retried.Header.Get("Idempotent-Replayed"))
+	}
+	for _, name := range []string{"Location", "Content-Type"} {
+		if first.Header.Get(name) == "" || retried.Header.Get(name) != first.Header.Get(name) {
+			t.Errorf("expected the retry to have the %v %q of the first response, got %q", name, first.Header.Get(name), retried.Header.Get(name))
+		}
+	}
+	if retried.Header.Get("X-Request-Id") == first.Header.Get("X-Request-Id") {
+		t.Errorf("expected the retry to keep its own request id, got %q twice", retried.Header.Get("X-Request-Id"))
+	}
+}
@@ -1,40 +1,83 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"math/rand"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/dkucheru/Calendar/app"
+	"github.com/dkucheru/Calendar/config"
+	"github.com/dkucheru/Calendar/logger"
 	"github.com/dkucheru/Calendar/structs"
 )
 
 var CheckSignals chan os.Signal
 
+const usage = `usage:
+  calendar [serve] [flags]          start the http server
+  calendar migrate status [flags]   list migrations and whether they are applied
+  calendar migrate up [N] [flags]   apply N pending migrations, all of them by default
+  calendar migrate down N [flags]   roll back the N most recently applied migrations
+  calendar migrate redo [flags]     roll back the last migration and apply it again
+
+run "calendar serve -h" or "calendar migrate -h" to list flags`
+
 func main() {
-	migrateDown := flag.Bool("down", false, "call to sql-migrate down")
-	flag.Parse()
+	args := os.Args[1:]
+	command := "serve"
+	if len(args) > 0 && !isFlag(args[0]) {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "serve":
+		err = serve(args)
+	case "migrate":
+		err = runMigrate(args)
+	case "help":
+		fmt.Println(usage)
+	default:
+		err = fmt.Errorf("unknown command %q\n%v", command, usage)
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+}
+
+func isFlag(arg string) bool {
+	return len(arg) > 0 && arg[0] == '-'
+}
+
+func serve(args []string) error {
+	conf, err := config.Load(args, os.LookupEnv)
+	if err != nil {
+		return err
+	}
 	structs.GlobalId = 1
 	rand.Seed(time.Now().Unix())
-	appNew, err := app.New(*migrateDown)
+	appNew, err := app.New(conf)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	go func() {
-		err = appNew.Run()
-		if err != nil {
-			log.Fatal(err)
+		err := appNew.Run()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("server stopped : %v", err)
+			os.Exit(1)
 		}
 	}()
 	defer appNew.Stop()
-	log.Printf("Started server")
+	logger.WithField("address", conf.Address).Infof("started server")
 	CheckSignals = make(chan os.Signal, 1)
 	signal.Notify(CheckSignals, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	log.Println(fmt.Sprint(<-CheckSignals))
-	log.Println("Stopping API server.")
+	logger.WithField("signal", fmt.Sprint(<-CheckSignals)).Infof("stopping API server")
+	return nil
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dkucheru/Calendar/config"
+	"github.com/dkucheru/Calendar/db"
+)
+
+func runMigrate(args []string) error {
+	if len(args) == 0 || isFlag(args[0]) {
+		return fmt.Errorf("migrate needs a subcommand\n%v", usage)
+	}
+	subcommand, args := args[0], args[1:]
+
+	// the optional count goes right after the subcommand, flags follow it
+	count := 0
+	if len(args) > 0 && !isFlag(args[0]) {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of migrations %q", args[0])
+		}
+		count, args = n, args[1:]
+	}
+
+	fs := flag.NewFlagSet("calendar migrate "+subcommand, flag.ContinueOnError)
+	force := fs.Bool("force", false, "do not ask for confirmation before rolling migrations back")
+	conf, err := config.LoadFlagSet(fs, args, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments : %v", strings.Join(fs.Args(), " "))
+	}
+	if conf.Storage != config.StoragePostgres {
+		return fmt.Errorf("migrations are only used by postgres storage, configured storage is %q", conf.Storage)
+	}
+
+	conn, err := db.Connect(conf.DSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch subcommand {
+	case "status":
+		return printStatus(conn, os.Stdout)
+	case "up":
+		_, err = db.MigrateUp(conn, count)
+		return err
+	case "down":
+		if count == 0 {
+			return errors.New("migrate down needs the number of migrations to roll back")
+		}
+		if err = confirmRollback(conn, count, *force); err != nil {
+			return err
+		}
+		_, err = db.MigrateDown(conn, count)
+		return err
+	case "redo":
+		if count != 0 {
+			return errors.New("migrate redo does not take a number of migrations")
+		}
+		if err = confirmRollback(conn, 1, *force); err != nil {
+			return err
+		}
+		return db.MigrateRedo(conn)
+	}
+	return fmt.Errorf("unknown migrate subcommand %q\n%v", subcommand, usage)
+}
+
+func printStatus(conn *sql.DB, out io.Writer) error {
+	status, err := db.GetMigrationStatus(context.Background(), conn)
+	if err != nil {
+		return err
+	}
+	for _, m := range status {
+		state := "pending"
+		if m.Applied {
+			state = "applied " + m.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(out, "%-60s %v\n", m.Id, state)
+	}
+	return nil
+}
+
+// confirmRollback lists the migrations that are about to be rolled back and
+// asks the operator to confirm, unless --force was given.
+func confirmRollback(conn *sql.DB, count int, force bool) error {
+	status, err := db.GetMigrationStatus(context.Background(), conn)
+	if err != nil {
+		return err
+	}
+	var affected []string
+	for i := len(status) - 1; i >= 0 && len(affected) < count; i-- {
+		if status[i].Applied {
+			affected = append(affected, status[i].Id)
+		}
+	}
+	if len(affected) == 0 {
+		return errors.New("there are no applied migrations to roll back")
+	}
+
+	fmt.Printf("The following migrations will be rolled back, data they created may be lost :\n  %v\n",
+		strings.Join(affected, "\n  "))
+	if force {
+		return nil
+	}
+	fmt.Print("Type 'yes' to continue : ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if strings.TrimSpace(answer) != "yes" {
+		return errors.New("aborted, nothing was rolled back")
+	}
+	return nil
+}
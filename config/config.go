@@ -0,0 +1,272 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageFile     = "file"
+
+	MigrateUp   = "up"
+	MigrateNone = "none"
+)
+
+// Config holds everything needed to start the application.
+// Values are resolved in the following order, each step overriding the previous one:
+// defaults, config file, environment variables, command line flags.
+type Config struct {
+	Storage         string
+	DSN             string
+	DataDir         string
+	Address         string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	ShutdownTimeout time.Duration
+	RequestTimeout  time.Duration
+	QueryTimeout    time.Duration
+	LogLevel        string
+	LogFormat       string
+	Migrate         string
+	IdempotencyTTL  time.Duration
+	// WeekStart is the weekday calendar views begin weeks on, unless a request picks another one
+	WeekStart string
+}
+
+func Default() *Config {
+	return &Config{
+		Storage:         StoragePostgres,
+		DataDir:         "data",
+		Address:         ":8080",
+		ReadTimeout:     10 * time.Second,
+		WriteTimeout:    10 * time.Second,
+		ShutdownTimeout: 5 * time.Second,
+		RequestTimeout:  8 * time.Second,
+		QueryTimeout:    5 * time.Second,
+		LogLevel:        "info",
+		LogFormat:       logger.FormatLogfmt,
+		Migrate:         MigrateUp,
+		IdempotencyTTL:  24 * time.Hour,
+		WeekStart:       "monday",
+	}
+}
+
+// fileConfig mirrors Config in the format used by the optional JSON config file.
+// Durations are written as strings accepted by time.ParseDuration, e.g. "10s".
+type fileConfig struct {
+	Storage         *string `json:"storage"`
+	DSN             *string `json:"dsn"`
+	DataDir         *string `json:"data_dir"`
+	Address         *string `json:"address"`
+	ReadTimeout     *string `json:"read_timeout"`
+	WriteTimeout    *string `json:"write_timeout"`
+	ShutdownTimeout *string `json:"shutdown_timeout"`
+	RequestTimeout  *string `json:"request_timeout"`
+	QueryTimeout    *string `json:"query_timeout"`
+	LogLevel        *string `json:"log_level"`
+	LogFormat       *string `json:"log_format"`
+	Migrate         *string `json:"migrate"`
+	IdempotencyTTL  *string `json:"idempotency_ttl"`
+	WeekStart       *string `json:"week_start"`
+}
+
+// setting describes one configuration value and every source it can be read from.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	file  func(f *fileConfig) *string
+	apply func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{"storage", "CALENDAR_STORAGE", "storage backend : postgres, memory or file",
+		func(f *fileConfig) *string { return f.Storage },
+		func(c *Config, v string) error { c.Storage = v; return nil }},
+	{"dsn", "DSN", "postgres connection string",
+		func(f *fileConfig) *string { return f.DSN },
+		func(c *Config, v string) error { c.DSN = v; return nil }},
+	{"data-dir", "CALENDAR_DATA_DIR", "directory used by the file storage backend",
+		func(f *fileConfig) *string { return f.DataDir },
+		func(c *Config, v string) error { c.DataDir = v; return nil }},
+	{"address", "CALENDAR_ADDRESS", "address the http server listens on",
+		func(f *fileConfig) *string { return f.Address },
+		func(c *Config, v string) error { c.Address = v; return nil }},
+	{"read-timeout", "CALENDAR_READ_TIMEOUT", "http server read timeout",
+		func(f *fileConfig) *string { return f.ReadTimeout },
+		func(c *Config, v string) error { return parseDuration(&c.ReadTimeout, "read-timeout", v) }},
+	{"write-timeout", "CALENDAR_WRITE_TIMEOUT", "http server write timeout",
+		func(f *fileConfig) *string { return f.WriteTimeout },
+		func(c *Config, v string) error { return parseDuration(&c.WriteTimeout, "write-timeout", v) }},
+	{"shutdown-timeout", "CALENDAR_SHUTDOWN_TIMEOUT", "time given to in-flight requests on shutdown",
+		func(f *fileConfig) *string { return f.ShutdownTimeout },
+		func(c *Config, v string) error { return parseDuration(&c.ShutdownTimeout, "shutdown-timeout", v) }},
+	{"request-timeout", "CALENDAR_REQUEST_TIMEOUT", "deadline for handling a single request, 0 disables it",
+		func(f *fileConfig) *string { return f.RequestTimeout },
+		func(c *Config, v string) error { return parseDuration(&c.RequestTimeout, "request-timeout", v) }},
+	{"query-timeout", "CALENDAR_QUERY_TIMEOUT", "deadline for a single database query, 0 disables it",
+		func(f *fileConfig) *string { return f.QueryTimeout },
+		func(c *Config, v string) error { return parseDuration(&c.QueryTimeout, "query-timeout", v) }},
+	{"log-level", "CALENDAR_LOG_LEVEL", "log level : debug, info, warn or error",
+		func(f *fileConfig) *string { return f.LogLevel },
+		func(c *Config, v string) error { c.LogLevel = v; return nil }},
+	{"log-format", "CALENDAR_LOG_FORMAT", "log format : logfmt or json",
+		func(f *fileConfig) *string { return f.LogFormat },
+		func(c *Config, v string) error { c.LogFormat = v; return nil }},
+	{"migrate", "CALENDAR_MIGRATE", "migrations applied on start : up or none",
+		func(f *fileConfig) *string { return f.Migrate },
+		func(c *Config, v string) error { c.Migrate = v; return nil }},
+	{"idempotency-ttl", "CALENDAR_IDEMPOTENCY_TTL", "how long responses to requests with an Idempotency-Key are kept for retries",
+		func(f *fileConfig) *string { return f.IdempotencyTTL },
+		func(c *Config, v string) error { return parseDuration(&c.IdempotencyTTL, "idempotency-ttl", v) }},
+	{"week-start", "CALENDAR_WEEK_START", "weekday weeks begin on in calendar views, e.g. monday or sunday",
+		func(f *fileConfig) *string { return f.WeekStart },
+		func(c *Config, v string) error { c.WeekStart = v; return nil }},
+}
+
+func parseDuration(dest *time.Duration, name string, value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %v : %v", value, name, err)
+	}
+	*dest = d
+	return nil
+}
+
+// Load builds the configuration from args (without the program name),
+// the environment accessed through lookupEnv and the config file named by
+// the --config flag or the CALENDAR_CONFIG variable.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	fs := flag.NewFlagSet("calendar", flag.ContinueOnError)
+	conf, err := LoadFlagSet(fs, args, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments : %v", strings.Join(fs.Args(), " "))
+	}
+	return conf, nil
+}
+
+// LoadFlagSet works like Load, but registers the configuration flags on fs
+// so that callers can add flags of their own. Arguments left after parsing
+// are available through fs.Args().
+func LoadFlagSet(fs *flag.FlagSet, args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	conf := Default()
+
+	configPath := fs.String("config", "", "path to a JSON config file")
+	flagValues := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		flagValues[s.flag] = fs.String(s.flag, "", s.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	path := *configPath
+	if path == "" {
+		path, _ = lookupEnv("CALENDAR_CONFIG")
+	}
+	if path != "" {
+		if err := conf.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := lookupEnv(s.env); ok && value != "" {
+			if err := s.apply(conf, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				flagErr = s.apply(conf, *flagValues[s.flag])
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file : %v", err)
+	}
+	var f fileConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&f); err != nil {
+		return fmt.Errorf("parsing config file %v : %v", path, err)
+	}
+	for _, s := range settings {
+		if value := s.file(&f); value != nil {
+			if err = s.apply(c, *value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) Validate() error {
+	switch c.Storage {
+	case StoragePostgres:
+		if c.DSN == "" {
+			return errors.New("dsn is required for postgres storage")
+		}
+	case StorageFile:
+		if c.DataDir == "" {
+			return errors.New("data-dir is required for file storage")
+		}
+	case StorageMemory:
+	default:
+		return fmt.Errorf("unknown storage backend %q", c.Storage)
+	}
+	if c.Address == "" {
+		return errors.New("address must not be empty")
+	}
+	if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.ShutdownTimeout < 0 || c.RequestTimeout < 0 || c.QueryTimeout < 0 {
+		return errors.New("timeouts must not be negative")
+	}
+	if c.IdempotencyTTL <= 0 {
+		return errors.New("idempotency-ttl must be positive")
+	}
+	if _, err := logger.ParseLevel(c.LogLevel); err != nil {
+		return err
+	}
+	if err := logger.ValidFormat(c.LogFormat); err != nil {
+		return err
+	}
+	switch c.Migrate {
+	case MigrateUp, MigrateNone:
+	default:
+		return fmt.Errorf("unknown migrate mode %q", c.Migrate)
+	}
+	if _, ok := structs.ParseWeekday(c.WeekStart); !ok {
+		return fmt.Errorf("unknown week start %q", c.WeekStart)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calendar-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "calendar.json")
+	err = ioutil.WriteFile(configFile, []byte(`{
+		"storage": "file",
+		"data_dir": "/var/lib/calendar",
+		"address": ":9000",
+		"read_timeout": "3s"
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badFile := filepath.Join(dir, "bad.json")
+	err = ioutil.WriteFile(badFile, []byte(`{"unknown": true}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		args         []string
+		env          map[string]string
+		check        func(c *Config) bool
+		errorMessage string
+	}{
+		"Defaults with dsn from env": {
+			nil,
+			map[string]string{"DSN": "postgres://localhost/calendar"},
+			func(c *Config) bool {
+				return c.Storage == StoragePostgres && c.Address == ":8080" && c.DSN == "postgres://localhost/calendar"
+			},
+			"",
+		},
+		"Postgres without dsn": {
+			nil,
+			nil,
+			nil,
+			"dsn is required",
+		},
+		"Idempotency ttl from env": {
+			[]string{"--storage", "memory"},
+			map[string]string{"CALENDAR_IDEMPOTENCY_TTL": "1h"},
+			func(c *Config) bool { return c.IdempotencyTTL == time.Hour },
+			"",
+		},
+		"Zero idempotency ttl": {
+			[]string{"--storage", "memory", "--idempotency-ttl", "0s"},
+			nil,
+			nil,
+			"idempotency-ttl must be positive",
+		},
+		"Week start from env": {
+			[]string{"--storage", "memory"},
+			map[string]string{"CALENDAR_WEEK_START": "Sunday"},
+			func(c *Config) bool { return c.WeekStart == "Sunday" },
+			"",
+		},
+		"Unknown week start": {
+			[]string{"--storage", "memory", "--week-start", "someday"},
+			nil,
+			nil,
+			"unknown week start",
+		},
+		"Memory storage from flag": {
+			[]string{"--storage", "memory", "--address", ":9090"},
+			nil,
+			func(c *Config) bool { return c.Storage == StorageMemory && c.Address == ":9090" },
+			"",
+		},
+		"Config file values": {
+			[]string{"--config", configFile},
+			nil,
+			func(c *Config) bool {
+				return c.Storage == StorageFile && c.DataDir == "/var/lib/calendar" && c.ReadTimeout == 3*time.Second
+			},
+			"",
+		},
+		"Env overrides file and flag overrides env": {
+			[]string{"--address", ":7000"},
+			map[string]string{
+				"CALENDAR_CONFIG":  configFile,
+				"CALENDAR_ADDRESS": ":6000",
+				"CALENDAR_STORAGE": "memory",
+			},
+			func(c *Config) bool {
+				return c.Storage == StorageMemory && c.Address == ":7000" && c.ReadTimeout == 3*time.Second
+			},
+			"",
+		},
+		"Unknown storage": {
+			[]string{"--storage", "mongo"},
+			nil,
+			nil,
+			"unknown storage backend",
+		},
+		"Bad duration": {
+			[]string{"--storage", "memory", "--write-timeout", "soon"},
+			nil,
+			nil,
+			"invalid value",
+		},
+		"Bad log level": {
+			[]string{"--storage", "memory", "--log-level", "loud"},
+			nil,
+			nil,
+			"unknown log level",
+		},
+		"Unknown field in file": {
+			[]string{"--config", badFile},
+			nil,
+			nil,
+			"unknown field",
+		},
+		"Missing file": {
+			[]string{"--config", filepath.Join(dir, "missing.json")},
+			nil,
+			nil,
+			"reading config file",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := test.env[key]
+				return value, ok
+			}
+			conf, err := Load(test.args, lookupEnv)
+			if test.errorMessage != "" {
+				if err == nil || !strings.Contains(err.Error(), test.errorMessage) {
+					t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error : %v", err)
+				return
+			}
+			if !test.check(conf) {
+				t.Errorf("unexpected config : %+v", conf)
+			}
+		})
+	}
+}
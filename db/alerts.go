@@ -0,0 +1,316 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+type AlertsDBRepository struct {
+	Conn         *sql.DB
+	QueryTimeout time.Duration
+}
+
+func NewAlertsDBRepository(conn *sql.DB) (*AlertsDBRepository, error) {
+	return &AlertsDBRepository{Conn: conn}, nil
+}
+
+// alertColumns are read by every query returning alerts from alertSubjects, in the order
+// scanAlert expects. Names and owners come from the events and tasks, so they never get out of date.
+const alertColumns = `a.alert_id, COALESCE(a.eventid, 0), COALESCE(a.taskid, 0), COALESCE(e.event_name, t.title),
+	COALESCE(e.event_owner, t.username), a.state, a.alert_at, a.due_at, a.deliveries, a.updated_at`
+
+// alertSubjects joins alerts with the event or the task they remind of.
+const alertSubjects = `LEFT JOIN events e ON e.eventid = a.eventid LEFT JOIN tasks t ON t.taskid = a.taskid`
+
+func scanAlert(row rowScanner) (structs.AlertInstance, error) {
+	var a structs.AlertInstance
+	err := row.Scan(&a.Id, &a.EventId, &a.TaskId, &a.EventName, &a.Owner, &a.State, &a.AlertAt, &a.DueAt, &a.Deliveries, &a.UpdatedAt)
+	if err != nil {
+		return structs.AlertInstance{}, err
+	}
+	a.AlertAt, a.DueAt, a.UpdatedAt = a.AlertAt.UTC(), a.DueAt.UTC(), a.UpdatedAt.UTC()
+	return a, nil
+}
+
+// Schedule drops the pending alert of the event or the task and adds a for its current alert
+// time. An alert time that already has an instance, e.g. one that was delivered, is not added again.
+func (db *AlertsDBRepository) Schedule(ctx context.Context, a structs.AlertInstance) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM alert_instances
+	WHERE eventid IS NOT DISTINCT FROM $1 AND taskid IS NOT DISTINCT FROM $2 AND state = 'pending' AND alert_at <> $3;`
+	if _, err = tx.ExecContext(ctx, query, nullableId(a.EventId), nullableId(a.TaskId), a.AlertAt.UTC()); err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if !a.AlertAt.IsZero() {
+		query = `INSERT INTO alert_instances (eventid, taskid, state, alert_at, due_at, deliveries, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6)
+		ON CONFLICT DO NOTHING;`
+		_, err = tx.ExecContext(ctx, query, nullableId(a.EventId), nullableId(a.TaskId), a.State, a.AlertAt.UTC(), a.DueAt.UTC(), a.UpdatedAt.UTC())
+		if err != nil {
+			return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return nil
+}
+
+func (db *AlertsDBRepository) Cancel(ctx context.Context, eventId int) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	_, err := db.Conn.ExecContext(ctx, `DELETE FROM alert_instances WHERE eventid = $1;`, eventId)
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return nil
+}
+
+func (db *AlertsDBRepository) CancelTask(ctx context.Context, taskId int) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	_, err := db.Conn.ExecContext(ctx, `DELETE FROM alert_instances WHERE taskid = $1;`, taskId)
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return nil
+}
+
+// nullableId stores the id of the event or the task an alert does not belong to as NULL.
+func nullableId(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// Deliver marks the alerts due at now as delivered. Every alert is only
+// returned once, even when several instances of the service deliver them.
+func (db *AlertsDBRepository) Deliver(ctx context.Context, now time.Time) ([]structs.AlertInstance, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `WITH a AS (
+		UPDATE alert_instances SET state = 'delivered', deliveries = deliveries + 1, updated_at = $1
+		WHERE state IN ('pending', 'snoozed') AND due_at <= $1
+		RETURNING *
+	)
+	SELECT ` + alertColumns + ` FROM a ` + alertSubjects + `
+	ORDER BY a.due_at, a.alert_id;`
+	return db.query(ctx, query, now.UTC())
+}
+
+func (db *AlertsDBRepository) Get(ctx context.Context, p structs.AlertParams) ([]structs.AlertInstance, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `SELECT ` + alertColumns + `
+	FROM alert_instances a ` + alertSubjects + `
+	WHERE (COALESCE(e.event_owner, t.username) = $1 OR $1 = '') AND (a.state = $2 OR $2 = '')
+	ORDER BY a.due_at, a.alert_id;`
+	return db.query(ctx, query, p.Owner, p.State)
+}
+
+func (db *AlertsDBRepository) query(ctx context.Context, query string, args ...interface{}) ([]structs.AlertInstance, error) {
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer rows.Close()
+	list := make([]structs.AlertInstance, 0)
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		list = append(list, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	return list, nil
+}
+
+func (db *AlertsDBRepository) GetByID(ctx context.Context, id int) (structs.AlertInstance, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `SELECT ` + alertColumns + `
+	FROM alert_instances a ` + alertSubjects + `
+	WHERE a.alert_id = $1;`
+	a, err := scanAlert(db.Conn.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			message := "alert with id [" + fmt.Sprint(id) + "] does not exist"
+			return structs.AlertInstance{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+		}
+		return structs.AlertInstance{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return a, nil
+}
+
+// Update stores the new state of a, as long as the alert is still in the state previous.
+func (db *AlertsDBRepository) Update(ctx context.Context, a structs.AlertInstance, previous structs.AlertState) (structs.AlertInstance, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `UPDATE alert_instances SET state = $1, due_at = $2, updated_at = $3
+	WHERE alert_id = $4 AND state = $5;`
+	res, err := db.Conn.ExecContext(ctx, query, a.State, a.DueAt.UTC(), a.UpdatedAt.UTC(), a.Id, previous)
+	if err != nil {
+		return structs.AlertInstance{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return structs.AlertInstance{}, alertChangedError(a.Id)
+	}
+	return a, nil
+}
+
+func (db *AlertsDBRepository) ClearRepoData() error {
+	_, err := db.Conn.Exec("TRUNCATE alert_instances RESTART IDENTITY;")
+	if err != nil {
+		return fmt.Errorf("%w : error occured when truncating alert_instances table : %v", structs.ErrPostgres, err.Error())
+	}
+	return nil
+}
+
+func alertChangedError(id int) error {
+	return structs.NewError(structs.KindConflict, "alert ["+fmt.Sprint(id)+"] was changed at the same time, try again")
+}
+
+// AlertsInMemoryRepository is used with the memory storage and kept on disk by AlertsFileRepository.
+type AlertsInMemoryRepository struct {
+	Alerts map[int]structs.AlertInstance
+	NextId int
+	mu     sync.RWMutex
+}
+
+func NewAlertsInMemoryRepository() (*AlertsInMemoryRepository, error) {
+	return &AlertsInMemoryRepository{Alerts: make(map[int]structs.AlertInstance), NextId: 1}, nil
+}
+
+func (m *AlertsInMemoryRepository) Schedule(ctx context.Context, a structs.AlertInstance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	scheduled := a.AlertAt.IsZero()
+	for id, existing := range m.Alerts {
+		if existing.EventId != a.EventId || existing.TaskId != a.TaskId {
+			continue
+		}
+		if existing.AlertAt.Equal(a.AlertAt) {
+			scheduled = true
+		} else if existing.State == structs.AlertPending {
+			delete(m.Alerts, id)
+			continue
+		}
+		existing.EventName, existing.Owner = a.EventName, a.Owner
+		m.Alerts[id] = existing
+	}
+	if !scheduled {
+		a.Id = m.NextId
+		m.NextId++
+		m.Alerts[a.Id] = a
+	}
+	return nil
+}
+
+func (m *AlertsInMemoryRepository) Cancel(ctx context.Context, eventId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, a := range m.Alerts {
+		if a.EventId == eventId && a.TaskId == 0 {
+			delete(m.Alerts, id)
+		}
+	}
+	return nil
+}
+
+func (m *AlertsInMemoryRepository) CancelTask(ctx context.Context, taskId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, a := range m.Alerts {
+		if a.TaskId == taskId && a.EventId == 0 {
+			delete(m.Alerts, id)
+		}
+	}
+	return nil
+}
+
+func (m *AlertsInMemoryRepository) Deliver(ctx context.Context, now time.Time) ([]structs.AlertInstance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delivered := make([]structs.AlertInstance, 0)
+	for id, a := range m.Alerts {
+		if (a.State == structs.AlertPending || a.State == structs.AlertSnoozed) && !a.DueAt.After(now) {
+			a.State, a.UpdatedAt = structs.AlertDelivered, now
+			a.Deliveries++
+			m.Alerts[id] = a
+			delivered = append(delivered, a)
+		}
+	}
+	sortAlerts(delivered)
+	return delivered, nil
+}
+
+func (m *AlertsInMemoryRepository) Get(ctx context.Context, p structs.AlertParams) ([]structs.AlertInstance, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]structs.AlertInstance, 0)
+	for _, a := range m.Alerts {
+		if (p.Owner == "" || a.Owner == p.Owner) && (p.State == "" || a.State == p.State) {
+			list = append(list, a)
+		}
+	}
+	sortAlerts(list)
+	return list, nil
+}
+
+func (m *AlertsInMemoryRepository) GetByID(ctx context.Context, id int) (structs.AlertInstance, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.Alerts[id]
+	if !ok {
+		message := "alert with id [" + fmt.Sprint(id) + "] does not exist"
+		return structs.AlertInstance{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+	}
+	return a, nil
+}
+
+func (m *AlertsInMemoryRepository) Update(ctx context.Context, a structs.AlertInstance, previous structs.AlertState) (structs.AlertInstance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.Alerts[a.Id]
+	if !ok || stored.State != previous {
+		return structs.AlertInstance{}, alertChangedError(a.Id)
+	}
+	stored.State, stored.DueAt, stored.UpdatedAt = a.State, a.DueAt, a.UpdatedAt
+	m.Alerts[a.Id] = stored
+	return stored, nil
+}
+
+func (m *AlertsInMemoryRepository) ClearRepoData() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Alerts = make(map[int]structs.AlertInstance)
+	m.NextId = 1
+	return nil
+}
+
+// sortAlerts orders alerts the way the database returns them.
+func sortAlerts(alerts []structs.AlertInstance) {
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].DueAt.Equal(alerts[j].DueAt) {
+			return alerts[i].DueAt.Before(alerts[j].DueAt)
+		}
+		return alerts[i].Id < alerts[j].Id
+	})
+}
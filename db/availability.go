@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// GetAvailability reads the profile of user, users without one get an empty profile.
+func (db *UsersDBRepository) GetAvailability(ctx context.Context, user string) (structs.Availability, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	a := structs.Availability{Weekly: make(map[string][]structs.TimeRange), Overrides: make([]structs.DateOverride, 0)}
+
+	err := db.Conn.QueryRowContext(ctx, `SELECT holiday_calendar FROM users WHERE username = $1;`, user).Scan(&a.Holidays)
+	if err != nil && err != sql.ErrNoRows {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+
+	query := `SELECT weekday, start_minute, end_minute FROM user_working_hours
+	WHERE username = $1 ORDER BY weekday, start_minute;`
+	rows, err := db.Conn.QueryContext(ctx, query, user)
+	if err != nil {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var weekday, start, end int
+		if err := rows.Scan(&weekday, &start, &end); err != nil {
+			return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		day := structs.Weekdays[weekday]
+		a.Weekly[day] = append(a.Weekly[day], structs.TimeRange{Start: structs.FormatClock(start), End: structs.FormatClock(end)})
+	}
+	if err = rows.Err(); err != nil {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+
+	query = `SELECT override_date, start_minute, end_minute FROM user_availability_overrides
+	WHERE username = $1 ORDER BY override_date, start_minute NULLS FIRST;`
+	overrides, err := db.Conn.QueryContext(ctx, query, user)
+	if err != nil {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer overrides.Close()
+	for overrides.Next() {
+		var date sql.NullTime
+		var start, end sql.NullInt64
+		if err := overrides.Scan(&date, &start, &end); err != nil {
+			return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		day := date.Time.Format(structs.DateLayout)
+		if n := len(a.Overrides); n == 0 || a.Overrides[n-1].Date != day {
+			a.Overrides = append(a.Overrides, structs.DateOverride{Date: day, Ranges: make([]structs.TimeRange, 0)})
+		}
+		if start.Valid {
+			o := &a.Overrides[len(a.Overrides)-1]
+			o.Ranges = append(o.Ranges, structs.TimeRange{
+				Start: structs.FormatClock(int(start.Int64)),
+				End:   structs.FormatClock(int(end.Int64)),
+			})
+		}
+	}
+	if err = overrides.Err(); err != nil {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	return a, nil
+}
+
+// UpdateAvailability replaces the profile of user in a single transaction.
+func (db *UsersDBRepository) UpdateAvailability(ctx context.Context, user string, a structs.Availability) (structs.Availability, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	var found string
+	err = tx.QueryRowContext(ctx, `SELECT username FROM users WHERE username = $1 FOR UPDATE;`, user).Scan(&found)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			message := "user with username [" + user + "] does not exist"
+			return structs.Availability{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+		}
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE users SET holiday_calendar = $2 WHERE username = $1;`, user, a.Holidays); err != nil {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	for _, query := range []string{
+		`DELETE FROM user_working_hours WHERE username = $1;`,
+		`DELETE FROM user_availability_overrides WHERE username = $1;`,
+	} {
+		if _, err = tx.ExecContext(ctx, query, user); err != nil {
+			return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+		}
+	}
+
+	query := `INSERT INTO user_working_hours (username, weekday, start_minute, end_minute) VALUES ($1, $2, $3, $4);`
+	for weekday, day := range structs.Weekdays {
+		for _, r := range a.Weekly[day] {
+			start, end := r.Minutes()
+			if _, err = tx.ExecContext(ctx, query, user, weekday, start, end); err != nil {
+				return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+			}
+		}
+	}
+	query = `INSERT INTO user_availability_overrides (username, override_date, start_minute, end_minute) VALUES ($1, $2, $3, $4);`
+	for _, o := range a.Overrides {
+		var err error
+		if len(o.Ranges) == 0 {
+			_, err = tx.ExecContext(ctx, query, user, o.Date, nil, nil)
+		}
+		for _, r := range o.Ranges {
+			start, end := r.Minutes()
+			if _, err = tx.ExecContext(ctx, query, user, o.Date, start, end); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return structs.Availability{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return a, nil
+}
+
+func (u *UsersRepository) GetAvailability(ctx context.Context, user string) (structs.Availability, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	a, ok := u.Availability[user]
+	if !ok {
+		return structs.Availability{Weekly: make(map[string][]structs.TimeRange), Overrides: make([]structs.DateOverride, 0)}, nil
+	}
+	return a, nil
+}
+
+func (u *UsersRepository) UpdateAvailability(ctx context.Context, user string, a structs.Availability) (structs.Availability, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.Users[user]; !ok {
+		message := "user with username [" + user + "] does not exist"
+		return structs.Availability{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+	}
+	// profiles are normalized, their slices are new and never changed afterwards
+	u.Availability[user] = a
+	return a, nil
+}
@@ -1,6 +1,18 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
 // ../migrations/20210721143846-create_user_table.sql
+// ../migrations/20261019100000-create_idempotency_keys_table.sql
+// ../migrations/20261019154639-add_all_day_events.sql
+// ../migrations/20261019154746-add_event_time_zones.sql
+// ../migrations/20261019155110-add_event_owner.sql
+// ../migrations/20261019155643-create_event_reminders_table.sql
+// ../migrations/20261019160117-create_alert_instances_table.sql
+// ../migrations/20261019160334-create_availability_tables.sql
+// ../migrations/20261019161206-create_resources_tables.sql
+// ../migrations/20261019162130-create_booking_tables.sql
+// ../migrations/20261019162512-add_user_holiday_calendar.sql
+// ../migrations/20261019163155-create_tasks_table.sql
+// ../migrations/20261019171836-add_idempotency_response_headers.sql
 
 package db
 
@@ -109,6 +121,378 @@ func bindataMigrations20210721143846createusertableSql() (*asset, error) {
 }
 
 
+var _bindataMigrations20261019100000createidempotencykeystableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\x41\x4f\xc2\x40\x10\x85\xef\xfb\x2b\xde\x8d\x36\x4a\x62\x8c\x78\xe1\xb4\xd0\x45\x36\x96\x96\x2c\x5b\x05\x2f\x4d\x85\x89\x10\x43\x5b\xbb\x4b\xb4\xff\xde\x94\x5a\x69\x20\x48\x62\x7b\x9a\x99\xfd\xde\xbc\xe4\x0d\xeb\x76\x71\xb5\xdd\xbc\x15\x89\x25\x44\x39\x1b\x2a\xc1\xb5\x80\xe6\x03\x5f\x40\x8e\x10\x84\x1a\x62\x2e\x67\x7a\x86\xcd\x8a\xb6\x79\x66\x29\x5d\x96\xf1\x3b\x95\x06\x0e\x03\x70\xdc\xc6\x13\x57\xc3\x31\x57\xce\x6d\xaf\xe7\xe2\xe4\xab\xf4\x82\xc8\xf7\xaf\xf7\x6c\x41\x1f\x3b\x32\x36\x5e\x27\x66\x5d\xd5\x0d\x7b\x7f\xe7\xe2\x12\x6b\x6c\x62\x77\xa6\x99\x01\x32\xd0\xe2\x41\xa8\xa6\x3c\xc3\xc2\x13\x23\x1e\xf9\x1a\x37\xb5\xca\x32\x4b\x2d\xa5\x36\xb6\x65\x4e\x6d\x07\x7f\xbb\xff\x55\xe9\x74\x6a\x99\xd7\x6c\x55\x36\x8f\xaa\x7f\xb0\xd0\x82\xff\x6c\x28\x28\xb1\xb4\x8a\x13\x5b\x55\x80\x96\x13\x31\xd3\x7c\x32\xc5\xb3\xd4\xe3\x30\xd2\xfb\x0e\x5e\xc2\x40\xb4\x36\xd4\x2c\x7d\xe5\x9b\x82\xcc\xbf\xd8\xa9\x92\x13\xae\x16\x78\x14\x0b\x38\x47\x21\xb9\xcc\xed\xb3\x26\x6b\x19\x78\x62\x7e\x21\xeb\xb8\xe5\x24\x0c\x4e\xc6\x70\x0e\xf3\x4a\xb9\x7d\x54\x5e\xf6\x99\x32\x4f\x85\xd3\xc3\x51\x9d\x59\xd2\x67\xdf\x00\x00\x00\xff\xff\x03\x00\xac\x78\x8e\x74\x8e\x02\x00\x00")
+
+func bindataMigrations20261019100000createidempotencykeystableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019100000createidempotencykeystableSql,
+		"../migrations/20261019100000-create_idempotency_keys_table.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019100000createidempotencykeystableSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019100000createidempotencykeystableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019100000-create_idempotency_keys_table.sql",
+		size: 654,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792424322, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019154639addalldayeventsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x53\xd1\x6e\xa3\x30\x10\x7c\xe7\x2b\xe6\x31\xd1\x85\x2f\x88\x72\x92\x13\x8c\x82\xce\x07\x11\x18\xdd\xbd\x21\xab\x58\x2d\xaa\x4b\xa2\x98\xa6\xcd\xdf\x57\x36\x26\x85\x94\x8a\x26\x52\x5e\xb2\xcb\xcc\xce\xcc\xae\x7d\x1f\xbf\x5e\xaa\xc7\xa3\x68\x24\xf2\x83\x47\x18\xa7\x29\x38\x59\x33\x0a\x79\x92\x75\xa3\x41\x82\x00\x9b\x84\xe5\x7f\x63\x44\x21\xe2\x84\x83\xfe\x8f\x32\x9e\xb5\xfd\x42\x28\x55\x94\xe2\x8c\x75\x92\x30\x4a\x62\xfb\x41\x9c\x33\x86\x80\x86\x24\x67\x1c\x21\x61\x19\x5d\xde\xc1\xac\x1b\x71\x6c\x8a\xd2\x28\x0b\x08\xbf\x8b\x42\xd6\xe5\x14\x81\xe5\x74\x14\xbd\xb9\x08\xd2\x64\x77\x31\xf3\x53\xa8\xac\xcb\x6b\xa0\xe7\xfb\x10\x4a\xf9\x26\x23\x07\x7c\x96\xf2\x80\x7d\xad\xce\x30\xda\xf4\x02\xcd\x93\xac\x8e\x30\x60\x53\x40\xa5\x21\xdf\x1f\xd4\xab\xae\x4e\x72\x74\xb0\xdd\x48\x9c\xf1\x94\x44\x31\x77\xd5\x6e\x11\xd6\xaf\xc6\x66\x4b\x37\x7f\x30\xf3\x00\x60\x36\xdc\x14\x89\x03\x27\xb7\x75\x1a\x65\xd6\x63\xaf\x6e\x94\xb8\xaa\x25\x30\xbf\x2b\x94\x1d\x83\x28\xbb\x38\x1d\xc2\xdb\xf6\xef\x2f\x88\xb9\xe5\x4b\x52\xcc\x4c\x42\xd3\xba\x46\xc9\xfb\x9d\x69\x7d\xdf\x68\x73\xad\xb9\x37\x6f\x77\x74\x79\x05\xc1\xfe\xad\x1e\x4b\xdd\xee\xb5\x17\x7b\x14\x0e\x6e\x4d\x77\x3e\xec\x64\xbd\xf4\xf2\x9d\x39\xba\x0e\x9d\xd1\xce\x6e\x6b\x6e\xd5\xff\x67\x21\x0b\x57\x31\x06\x57\xd7\x6a\xff\x6d\x69\x4a\x87\x79\xdd\x76\xce\x66\xfe\x3d\xd7\x3c\x89\x73\xb1\x74\x4f\x70\xf4\xf9\xdd\x0c\xfc\x4c\xe5\x66\xa8\x50\xaa\x28\xc5\x79\xe9\x7d\x00\x00\x00\xff\xff\x03\x00\xfd\x0b\x1b\x7b\xda\x04\x00\x00")
+
+func bindataMigrations20261019154639addalldayeventsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019154639addalldayeventsSql,
+		"../migrations/20261019154639-add_all_day_events.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019154639addalldayeventsSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019154639addalldayeventsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019154639-add_all_day_events.sql",
+		size: 1242,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792424747, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019154746addeventtimezonesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x2d\x4b\xcd\x2b\x29\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x86\xc8\xc7\x17\x97\x24\x16\x95\xc4\x97\x54\x29\x84\x39\x06\x39\x7b\x38\x06\x69\x18\x99\x9a\x6a\x82\x95\xf9\x85\xfa\xf8\x28\xb8\xb8\xba\x39\x86\xfa\x84\x28\xa8\xab\x5b\x93\x61\x78\x6a\x5e\x0a\x71\x46\x73\x21\xfb\xc3\x25\xbf\x3c\x0f\x9b\x65\x2e\x41\xfe\x01\x48\xb6\x61\xb1\xc9\x9a\x54\x6d\xc5\x25\x89\x45\x25\xf1\x25\x55\xd6\x5c\x00\x00\x00\x00\xff\xff\x03\x00\xf7\x27\x6e\x2a\x49\x01\x00\x00")
+
+func bindataMigrations20261019154746addeventtimezonesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019154746addeventtimezonesSql,
+		"../migrations/20261019154746-add_event_time_zones.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019154746addeventtimezonesSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019154746addeventtimezonesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019154746-add_event_time_zones.sql",
+		size: 329,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792424840, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019155110addeventownerSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8f\xb1\x6a\xc3\x30\x14\x45\x77\x7d\xc5\xdd\x92\x50\xbc\x14\x32\x79\x52\x2d\x85\x1a\x54\xb9\x28\x72\xc9\x16\xdc\xf8\xb6\x94\x12\xc9\xc8\xa6\xa6\x7f\x5f\x70\x1a\xec\xc1\xf3\x7b\xe7\x5c\x4e\x96\xe1\xe1\xfa\xf5\x99\x9a\x81\xa8\x3b\x91\x65\xe0\x0f\xc3\xd0\xe3\x92\xd8\x0c\x6c\xf1\xce\x8f\x98\x88\x38\x06\xa6\x1e\x23\x13\x91\x78\x89\xa9\x65\x8b\x6f\xb2\x43\x13\xc0\x6b\x37\xfc\xde\x5e\x84\x34\x5e\x3b\x78\xf9\x64\xf4\x5d\x25\x95\x42\x51\x99\xfa\xc5\xa2\x3c\xc0\x56\x1e\xfa\x54\x1e\xfd\xf1\x76\x3f\x4f\x1c\xde\xa4\x2b\x9e\xa5\xdb\x3e\xee\xf7\xbb\xe9\xc7\xd6\xc6\x40\xe9\x83\xac\x8d\xc7\x66\x93\x0b\x51\x38\x2d\xbd\x46\x69\x95\x3e\xad\x99\xfa\x7f\x55\x65\xef\xcb\xdb\xc5\xc2\x2e\x17\x62\x59\xab\xe2\x18\x84\x72\xd5\xeb\x2c\x5c\x91\xe5\x6b\x41\x13\x35\x17\x2d\xb1\x73\x1c\x03\x53\x2e\xfe\x00\x00\x00\xff\xff\x03\x00\xb5\x2a\x73\x49\x58\x01\x00\x00")
+
+func bindataMigrations20261019155110addeventownerSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019155110addeventownerSql,
+		"../migrations/20261019155110-add_event_owner.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019155110addeventownerSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019155110addeventownerSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019155110-add_event_owner.sql",
+		size: 344,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792424945, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019155643createeventreminderstableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\x4f\x6f\x9b\x40\x10\xc5\xef\x7c\x8a\x77\x8b\x51\xed\x43\xa5\x2a\x97\x9c\xd6\x30\x76\x56\xc5\x8b\xbb\x2c\x6d\xd2\x0b\x42\xf6\x38\x59\x35\xfc\x11\x6c\x1d\xf5\xdb\x57\x6b\x83\x1d\x29\x76\xff\xc0\x65\x04\xf3\x7e\x6f\x56\xf3\x76\x36\xc3\x87\xca\x3e\x75\xa5\x63\xe4\x6d\x30\x9b\xa1\xe3\xca\xd6\x5b\xee\x7a\x94\x1d\xe3\x07\xb7\x0e\xb6\x86\x7b\x66\x34\xdd\x96\x3b\x5f\xfd\xc2\x2b\x77\x8c\x27\xbb\xe7\x7a\x3a\x08\x8a\xd2\xc1\xf6\xd8\x34\x55\xfb\xd3\xf1\x16\xbb\xae\xa9\x7c\x2f\x78\xcf\xb5\x83\xb3\x15\xf7\x41\xa4\x49\x18\x82\x11\xf3\x84\x20\x17\x50\xa9\x01\x3d\xc8\xcc\x64\xc7\xb6\xe2\x6c\x3e\x09\x00\x9c\x86\x29\xec\x16\x98\xcb\x65\x46\x5a\x8a\x04\x97\x1e\xcf\x52\x79\x92\x4c\x0f\xc2\x03\xce\x8b\xfc\x3b\x97\x4b\xa9\xcc\xd8\x78\x4d\x08\x4d\x0b\xd2\xa4\x22\x1a\xa6\xe9\x31\x19\x30\x21\x52\x85\x98\x12\x32\x84\x48\x64\x91\x88\xe9\x68\xd3\x36\xbd\x75\xb6\xa9\x7d\x0d\xa9\x0c\x2d\x49\x8f\xdc\x2b\x36\xd3\xe1\x60\x2f\xa5\xb3\x7b\x2e\x5c\x03\x7c\x15\x3a\xba\x17\x7a\xf2\xf1\x36\x1c\xbb\xaf\x0a\x9b\xdd\xae\x67\x57\xf4\x2d\x6f\xce\xc2\xdb\x4f\x7f\x17\x96\xae\xf0\x4b\xf0\xe5\xbf\x3a\x22\xa6\x85\xc8\x13\x83\x9b\x9b\x23\x63\xf3\x5c\xd6\x35\xbf\xfc\x0f\x63\x3c\xee\x98\x11\x00\x46\xae\x28\x33\x62\xb5\xc6\x37\x69\xee\xd3\xdc\x1c\xbe\xe0\x7b\xaa\xe8\x9d\x70\xad\xe5\x4a\xe8\x47\x7c\xa6\x47\x4c\xde\xa4\x21\x3c\x72\x73\x25\xbf\xe4\x74\xda\xd3\xf4\xb4\x90\x30\x08\xef\x82\x31\x6f\x52\xc5\xf4\xf0\xe7\xbc\x0d\x95\x9f\x30\x55\xef\xc3\x78\xfa\xeb\xa9\x6f\xef\x4c\xdc\xbc\xd6\x41\xac\xd3\xf5\x39\xd4\x97\x0d\xee\x82\xdf\x00\x00\x00\xff\xff\x03\x00\xcb\x2d\x19\x44\x6b\x03\x00\x00")
+
+func bindataMigrations20261019155643createeventreminderstableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019155643createeventreminderstableSql,
+		"../migrations/20261019155643-create_event_reminders_table.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019155643createeventreminderstableSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019155643createeventreminderstableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019155643-create_event_reminders_table.sql",
+		size: 875,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792425317, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019160117createalertinstancestableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x53\xcd\x6e\xdb\x3c\x10\xbc\xeb\x29\xe6\x66\x09\x9f\x0d\x7c\xbd\xf4\x92\x93\x62\xad\x63\xa2\xb2\xec\x4a\x54\x93\xf4\x12\x10\xe1\x3a\x16\x6a\x93\x06\x49\xc7\x68\x9f\xbe\xd0\x9f\x5b\xa4\x6e\x81\x50\x17\x62\xb4\x33\xb3\xda\x1d\xcd\x66\xf8\xef\xd0\xbc\x38\x15\x18\xf5\x31\x9a\xcd\x60\x0d\xc3\xd9\x33\xb6\xd6\x81\x5f\xd9\x7d\x87\xda\xb3\x0b\x08\xcd\x81\x61\xb7\x50\xa6\x85\x4d\x98\xc2\xa8\x03\x7b\x28\xa3\x61\xcf\x86\x9d\x87\x72\x0c\xc7\x4a\x63\xeb\xec\x01\x61\xc7\x7d\x65\x34\x2f\x29\x95\x04\x99\xde\xe6\x04\xb1\x40\xb1\x96\xa0\x07\x51\xc9\xaa\xd7\x7e\x6a\x8c\x0f\xca\x3c\xb3\x47\x1c\x01\x18\x51\xdd\xde\x71\x2b\xee\x2a\x2a\x45\x9a\xe3\xda\x69\xb5\x8a\x3a\xcf\xa7\x1d\xb1\xf3\x1b\x78\x2d\x51\x14\x72\x2c\xfc\x1b\x11\x25\x2d\xa8\xa4\x62\x4e\x55\xdf\xae\x47\x3c\xc8\x24\x58\x17\xc8\x28\x27\x49\x98\xa7\xd5\x3c\xcd\xa8\xb7\xf1\xa1\x9d\xd7\x70\xbe\xa4\xe5\x7c\x99\x96\xf1\x87\x8f\xc9\x08\x5d\xb1\x99\xfe\xf6\x61\x2a\xb4\x77\x48\xb1\xa2\x4a\xa6\xab\x0d\xee\x85\x5c\xae\x6b\xd9\x21\xf8\xba\x2e\xe8\x0f\xa2\x3e\xf1\x48\x7b\x27\x91\xf7\xcd\x2b\xbb\x86\x3d\x00\x51\x48\xba\xa3\x72\x90\x79\xfb\x8c\x44\x64\xb4\x48\xeb\x5c\xe2\xff\x5e\xe2\x74\xd4\x2a\xb0\xee\xfd\xdf\xe3\xbd\x29\xc5\x2a\x2d\x1f\xf1\x89\x1e\x11\x8f\x3b\x4d\xfa\x77\x75\x21\x3e\xd7\x74\x19\xf5\xf4\x32\x9a\x24\x4a\x6e\xa2\x36\x88\x6d\x7e\x74\xe3\x8f\x2a\x3c\xef\xd8\x61\x6f\xed\x37\xdf\xa5\xf2\xc8\x46\x37\xe6\xa5\x4b\x9e\x37\xd6\xfe\x60\xdd\xb3\x3d\xc2\x4e\x85\x2e\x86\xfa\xc4\x63\xec\x44\x91\xd1\xc3\xbf\x63\xf7\xa4\x4f\xdc\xee\xfa\x0d\x8c\xb8\x1f\x7b\x82\xfb\x25\x95\x34\xac\x5d\x14\x88\x27\x43\x0f\x93\x29\x26\x43\x0b\x93\xa1\xed\xcb\xef\x94\xd9\xb3\x89\xb2\x72\xbd\xf9\x15\xfc\xeb\xee\x37\xd1\x4f\x00\x00\x00\xff\xff\x03\x00\x62\x9a\xf8\xe8\x86\x03\x00\x00")
+
+func bindataMigrations20261019160117createalertinstancestableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019160117createalertinstancestableSql,
+		"../migrations/20261019160117-create_alert_instances_table.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019160117createalertinstancestableSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019160117createalertinstancestableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019160117-create_alert_instances_table.sql",
+		size: 902,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792425529, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019160334createavailabilitytablesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x94\x41\x6f\x9c\x3e\x10\xc5\xef\x7c\x8a\x77\x04\xfd\x77\xa5\xfd\x57\x49\x2f\x9b\x54\x22\xe0\x28\x28\x04\x22\x20\x6d\x72\x42\x6e\x70\x17\x6b\xc1\x54\xd8\x04\xed\xb7\xaf\x0c\x86\x0d\x51\x9a\xed\x21\x7b\x62\xec\x99\x37\x6f\xe7\x37\xf2\x7a\x8d\xff\x6a\xbe\x6b\xa9\x62\x78\xf8\x6d\xad\xd7\x50\xbc\x66\x12\xcd\x2f\x14\xf4\x00\xda\x32\xd4\x5c\x74\x8a\x49\x48\x2e\x9e\x75\x54\x08\xbe\x2b\x15\xb8\x80\x2a\x19\xaa\xe6\x99\x2a\xde\x08\x5d\xa1\xe3\x4e\xb2\x76\x85\x9e\xb1\xbd\xae\xdf\x80\x4b\xc8\x4e\x14\xf4\x60\x79\x09\x71\x33\x82\xcc\xbd\x0a\x09\x82\x6b\x44\x71\x06\xf2\x18\xa4\x59\x3a\x14\xe5\x7d\xd3\xee\xb9\xd8\xe5\x65\xd3\xb5\x12\xb6\x05\x60\xb8\x10\xb4\x66\xfa\x1b\xf8\xee\x26\xde\x8d\x9b\xd8\x5f\xce\xcf\x1d\x60\x10\x88\x1e\xc2\x10\x09\xb9\x26\x09\x89\x3c\x32\x2a\x49\xd8\x53\x9d\x83\x38\x82\x4f\x42\x92\x11\x78\x6e\xea\xb9\x3e\x59\x0d\xc2\x93\x41\xfd\x0d\xa4\x77\x6e\x18\x06\x51\x66\xc2\x59\xd8\xbb\x21\xde\x2d\xec\x29\xf9\x8a\x64\x3f\x08\x89\xb0\x81\x1b\xf9\xf8\xea\x8c\x52\x52\xd1\x56\xe5\xe3\x94\xfe\x2a\x35\xa6\x32\x51\xcc\x89\x27\x52\x4d\xeb\x85\xf8\xb7\x4b\xd3\x7a\x71\x7a\xf1\x5a\x56\x1b\x7b\x15\x5e\x5c\xe2\xff\xb3\xb3\x8d\x71\x7a\x9f\x04\x77\x6e\xf2\x84\x5b\xf2\x74\x1c\xd1\x0c\x6b\xb5\xf8\x27\x8e\xe5\x6c\x2d\xbd\x0f\x54\xa0\x79\x61\x6d\xcb\x0b\x86\x9e\xab\xb2\xe9\xd4\xbc\x12\x35\xdd\x33\x39\xac\x41\x5f\x36\x15\x43\xa1\xd7\xa8\x13\xf4\x85\xf2\x8a\xfe\xac\xd8\x49\xe6\x26\x93\x57\x5c\x1d\xf2\xa9\xcd\xfb\xf0\x3f\x93\xfe\xd4\x29\x1f\x0c\xc3\xd7\x1e\x8f\xbf\x25\x88\xc5\xac\x8f\xd0\xde\x03\xfa\xe6\xd2\x20\xb4\x17\x0a\x41\x3a\x40\x7e\x0b\xca\x1c\x3b\x88\x13\x6b\xf2\xf1\x59\xf0\x47\x94\x06\x45\x10\xf9\xe4\xf1\xdf\x51\x8c\x13\x8a\xa3\x8f\x71\x4d\x03\x5f\x2d\x27\x6b\x36\x68\x7e\x60\xfc\xa6\x17\x96\x9f\xc4\xf7\xc7\x85\x38\xed\x60\xfb\x41\xc5\xe2\xc9\xd8\x5a\x7f\x00\x00\x00\xff\xff\x03\x00\x31\x04\xed\x7e\xcd\x04\x00\x00")
+
+func bindataMigrations20261019160334createavailabilitytablesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019160334createavailabilitytablesSql,
+		"../migrations/20261019160334-create_availability_tables.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019160334createavailabilitytablesSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019160334createavailabilitytablesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019160334-create_availability_tables.sql",
+		size: 1229,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792425723, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019161206createresourcestablesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x54\x4f\x4f\xdb\x4e\x10\xbd\xfb\x53\xbc\x13\x24\xfa\x25\x08\x7e\x15\xbd\x44\x54\x32\xce\x12\x2c\x5c\x07\xfc\xa7\x85\x53\xb4\xb1\xa7\x61\x85\xb3\x6b\xed\xae\x09\xf9\xf6\x95\x1d\xdb\x0d\x21\xb4\x7b\xf2\xd8\x6f\xde\xbc\x99\x79\xde\xf1\x18\xff\xad\xc5\x4a\x73\x4b\x48\x4b\x67\x3c\xc6\xd2\x6a\xa2\xc5\x4a\x18\x8b\x82\xac\x81\x7d\x26\xd0\x5b\x56\x54\x46\x28\x89\x4c\x49\x63\x35\x17\xd2\x22\x53\xeb\x92\x6b\x82\x26\xa3\x2a\x9d\x11\x44\x6e\xb0\x11\xf6\x19\x57\x23\x64\x9a\xb8\x15\x72\x05\x61\x21\x89\x72\x03\x0e\x53\x95\xa4\x2b\x43\xba\x2e\xa3\x34\x38\xac\xae\x8c\xa5\x1c\xf4\x66\x49\x36\xfc\x4a\xe2\x5e\x19\xbb\xd2\x14\x3f\x04\xb8\xf8\x02\x2e\x73\x14\xdc\x92\x76\xbc\x88\xb9\x09\x03\x7b\x4c\x58\x18\xfb\xf3\x10\xfe\x0d\xc2\x79\x02\xf6\xe8\xc7\x49\xbc\xa7\x7b\xe2\x74\xd8\xc4\xbd\x0e\xd8\x01\xae\x93\x6b\x30\x70\x00\xf4\xf2\x17\x22\x47\x7b\xae\xfd\x59\xcc\x22\xdf\x0d\xd0\x9c\x3a\x39\x4c\x83\x60\xf4\x1e\x2f\xf9\x9a\x76\xf8\x1f\x6e\xe4\xdd\xba\xd1\xe0\xff\xcb\xcb\xe1\xe7\xf8\x17\x21\xf3\xf7\xf8\x8b\xaf\xc3\x8f\xfc\x19\x2f\x79\x26\xec\xb6\x13\x03\xc0\x0f\x13\x36\x63\x51\x17\x76\x78\x4c\xd9\x8d\x9b\x06\x09\xce\xe1\xdd\x32\xef\x0e\x83\x3e\xf7\xdb\x15\xce\x87\x07\xf5\x0b\x95\x71\x5b\xcf\xf8\x33\xbd\x3d\xdf\xe9\xe9\x2e\xf5\x3e\xf2\xbf\xbb\xd1\x13\xee\xd8\x13\x06\x3d\x8f\xc8\x5b\xe6\x34\xf4\x1f\x52\xb6\xf7\xa5\x9e\xc8\xd0\x19\x4e\x9c\x7a\xc1\x4b\xa5\x5e\x28\x47\xa6\x4a\x41\x3b\x17\x59\xb1\x26\x03\xf5\xab\x09\xe8\x95\xa4\x1d\x81\x17\xc5\x38\xe7\xdb\x5d\x68\x9a\x24\x6c\x9e\x55\x41\x48\x13\x0f\x39\xdf\x9a\xb3\x9a\xcc\xed\xdb\x40\xc6\x25\xa4\xb2\x58\x52\x57\x62\xb9\x85\xdd\xa8\x8e\x82\x5b\xa8\x57\xd2\x05\x2f\xcb\xda\x7f\x4d\xd1\xb3\xbf\x19\xa2\xc9\x5b\x1c\xda\xa2\x79\xdb\x59\xe2\xda\x9f\xf9\x61\xf2\x6e\xf6\x11\xbb\x61\x11\x0b\x3d\xd6\x12\x18\x0c\xda\x94\x21\xe6\x21\xa6\x2c\x60\x09\x83\xe7\xc6\x9e\x3b\x65\x07\x9b\x10\xf9\xbf\x28\xf7\xd4\x7c\x9c\x7b\xdb\x76\xfd\x08\x24\x71\xe4\x86\x33\xb6\xc7\x73\x64\x77\xad\xb2\x11\x3e\x92\x79\xf3\x30\x4e\x22\xb7\x6e\xef\x60\x10\x0b\xa9\x16\xb9\xaa\x96\x05\x2d\xea\x8a\xf5\x30\xd9\xa3\x17\xa4\x53\x86\x34\xf6\xc3\x19\x9a\x3b\x62\xdf\x17\xf8\xe9\x27\xb7\xf5\xdf\xdf\x2a\x6c\xc2\x93\x93\xde\x12\xfd\x4d\x33\x55\x1b\xe9\x4c\xa3\xf9\xfd\x9f\x85\x1c\x5f\xc6\xe4\x38\x4a\x93\x51\x95\xce\xc8\x4c\x9c\xdf\x00\x00\x00\xff\xff\x03\x00\x88\xe2\x39\xb7\xc1\x04\x00\x00")
+
+func bindataMigrations20261019161206createresourcestablesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019161206createresourcestablesSql,
+		"../migrations/20261019161206-create_resources_tables.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019161206createresourcestablesSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019161206createresourcestablesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019161206-create_resources_tables.sql",
+		size: 1217,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792426181, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019162130createbookingtablesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x56\x4d\x6f\xe2\x30\x10\xbd\xf3\x2b\xe6\x56\xa2\x25\x12\xad\x68\x2f\xfd\x90\x52\x70\xdb\xa8\x10\xba\x21\x6c\xdb\xbd\x44\x2e\x99\x06\x8b\xe0\x20\xdb\x69\xb6\xff\x7e\xe5\x24\x86\x90\x02\x4b\xb7\x3d\x20\xdb\xf3\xde\xd8\xf3\xde\x0c\xd8\x36\xfc\x58\xb2\x58\x50\x85\x30\x5d\xb5\x6c\x1b\xa2\x4c\x50\xc5\x52\x2e\x81\x0a\x84\x7c\x9e\x26\x08\x4b\xc6\x33\x85\xb2\x03\x2b\x1a\xa3\x84\x9c\xa9\x79\x9a\x29\xc8\x19\x8f\xd2\x5c\x42\xfa\xfe\x8e\x02\xd4\x1c\x21\x4f\xc5\x82\xf1\x18\xe6\x69\x26\xf4\xbe\xde\x64\x02\xd2\x9c\xa3\x68\xf5\x7d\xe2\x04\x04\x02\xe7\x76\x48\xc0\xbd\x03\x6f\x1c\x00\x79\x71\x27\xc1\x04\xde\xd2\x54\xe3\xc2\x92\xbf\xdd\x02\x00\x90\x49\x16\x43\xf3\xef\x97\xe3\xf7\x1f\x1c\xbf\x7d\xd1\xb3\x8a\xb5\xe6\xf0\xa6\xc3\x61\xa7\x80\x64\x12\x05\xa7\x4b\xdc\x09\x39\x3b\x3f\xb7\xea\x10\xf0\xc9\x1d\xf1\x89\xd7\x27\x93\x02\x28\xa1\x6d\xf0\x16\x8c\x3d\x18\x90\x21\x09\x08\xf4\x9d\x49\xdf\x19\x90\x32\x81\x62\x2a\xc1\x7d\x77\x3a\xed\x76\xb7\x12\x94\x90\x08\xe5\x4c\xb0\x95\x2e\xe9\x2e\xc8\x59\xcf\xaa\x41\x60\x40\xee\x9c\xe9\x30\x80\x93\x93\x0a\x5d\xa9\x11\x56\x12\xe8\x3d\x70\xbd\x80\xdc\x13\xdf\x90\x6d\xd0\xfd\x07\xd2\x7f\x84\xf6\x17\xd0\x0d\x74\xad\x92\x2f\x16\x94\x67\x09\x15\x4c\x7d\x6e\x28\xff\xc5\xb7\x0b\xb4\xa1\x7c\xcb\xb4\xfc\x1b\xb6\xc3\x57\x34\x0f\xec\x1a\xf2\x06\xfc\xe6\x7a\x4d\xbc\xa4\x7f\xc2\x15\x8a\x30\xa2\x9f\x86\xe6\x3b\xc4\x75\x78\x8d\xf5\xc9\x77\x47\x8e\xff\x0a\x8f\xe4\x15\xda\xda\x63\x56\xcb\xba\x6c\x19\x73\xba\xde\x80\xbc\x1c\x32\x67\x68\x3c\xa2\x2d\xd2\xb0\xad\x39\xd2\x7c\xb6\x0d\x8a\x2d\xb1\x68\x02\x7d\x03\xdd\x4c\xe6\x8d\x92\xf1\x99\x5e\x45\x9c\xc5\x73\x05\x8c\xeb\x36\x81\x24\x9d\x15\x52\x57\x6d\x53\x36\x4d\x07\x72\xc4\x85\x26\xe8\x02\x93\x20\x33\x1e\xd1\xcf\x63\x3b\x29\x34\x0d\xba\xab\xa1\x8c\x05\x2f\x7a\xd6\x9e\xa6\x68\xbc\x4e\xc3\xf7\x36\x86\xb9\xa5\x66\x06\x98\x8c\x9c\xe1\xd0\xf5\x82\x86\x44\x95\x30\x26\xf6\x96\x04\xcf\x84\x78\xd0\x05\xc7\x1b\xc0\x45\xa5\x8f\x54\x54\xa8\xca\x0e\xfb\x98\xca\x48\xe4\xd1\x3a\xee\x70\x64\x95\x78\x8b\x5a\x5b\xa2\x48\xbc\xb5\x7b\x55\x67\xd5\xa7\xb5\xe5\xd5\x35\x9c\xf6\x7a\x7b\x7d\xb4\x96\xaa\xb3\x45\x59\xda\xcb\xb6\x81\x9a\x82\x42\xc2\x3e\x50\x02\x95\x90\xa4\x3c\xd6\x9f\x4c\x49\xc0\x0f\xe4\xaa\x03\x11\x26\xa8\xf4\x08\xa5\xc5\xb0\x85\x05\xe2\x4a\x16\xfe\x28\x02\x74\x2c\xcc\x04\x52\x85\xd1\x11\x36\x30\xd2\xcf\x28\x9f\x61\x12\xaa\x74\x81\xfc\x8b\xf6\xcd\xff\xed\xda\x35\xc6\xf0\x71\xd0\xff\xb5\x51\xf1\x46\x16\x19\xba\x5b\xf7\x7e\xad\xe8\x71\xc9\xaa\x22\xb5\x2b\xa2\xbd\x89\xe2\x0c\xa5\x0a\xd7\xdf\x15\xcd\x11\xbe\x3b\x51\x1d\x8a\x4b\xca\x92\xef\x42\x4d\x29\x0a\x7f\x00\x04\xee\x88\x4c\x02\x67\xf4\x04\xcf\x6e\xf0\x30\x9e\x06\xc5\x0e\xfc\x1e\x7b\x64\x2f\x14\x79\x51\x9d\xef\x40\xb7\x9c\x5a\x77\x42\xe5\xe4\xa9\xe7\xfe\x9c\x92\x4d\xcd\x8e\x9c\x87\x32\xd4\xd6\xa8\xde\xb2\x19\x86\x95\xc4\x1d\xb3\x2e\x03\xaa\x1e\x58\xff\xd6\x18\xa4\x39\x6f\x0d\xfc\xf1\xd3\xc6\xbd\x0d\xf6\xcb\x83\xc7\x5b\xf3\xed\x88\x50\x79\xd9\xfa\x0b\x00\x00\xff\xff\x03\x00\xe9\xdc\x52\x07\xeb\x08\x00\x00")
+
+func bindataMigrations20261019162130createbookingtablesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019162130createbookingtablesSql,
+		"../migrations/20261019162130-create_booking_tables.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019162130createbookingtablesSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019162130createbookingtablesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019162130-create_booking_tables.sql",
+		size: 2283,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792426424, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019162512adduserholidaycalendarSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xce\x31\x4b\xc4\x30\x00\xc5\xf1\x3d\x9f\xe2\x6d\xa7\x68\x76\xe1\xa6\x78\xc9\xe1\x41\x6c\x25\x4d\xc4\x4d\x62\x9b\x9a\x42\x9b\x94\x24\xa5\xf4\xdb\x0b\x05\xa1\x83\xee\x7f\xde\xef\x51\x8a\x87\x69\xf8\x4e\xb6\x38\x98\x99\x50\x8a\xe2\x1d\xda\xb8\x84\x92\x36\xac\x3e\x66\x87\x79\xf9\x1a\x87\x16\x3e\x8e\x43\x67\xb7\xbc\x17\x4b\x76\x09\xde\x66\xc4\xbe\x7f\x84\x9b\xe6\xb2\xa1\x8f\x09\x21\x06\x47\x98\xd4\x42\x41\xb3\x67\x29\xf6\x30\x83\x71\x8e\x4b\x2d\xcd\x6b\x85\xdb\x15\x55\xad\x21\x3e\x6e\x8d\x6e\x7e\x47\x3f\x5b\x3b\xba\xd0\xd9\x84\x77\xa6\x2e\x2f\x4c\xdd\x3d\xdd\xef\x59\x65\xa4\x04\x17\x57\x66\xa4\xc6\xe9\x74\x26\xe4\xf8\x98\xc7\x35\xfc\xa1\x71\x55\xbf\x1d\xb8\x7f\xa8\x33\xf9\x01\x00\x00\xff\xff\x03\x00\x36\x57\x00\xdb\xfd\x00\x00\x00")
+
+func bindataMigrations20261019162512adduserholidaycalendarSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019162512adduserholidaycalendarSql,
+		"../migrations/20261019162512-add_user_holiday_calendar.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019162512adduserholidaycalendarSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019162512adduserholidaycalendarSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019162512-add_user_holiday_calendar.sql",
+		size: 253,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792427034, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019163155createtaskstableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x95\x51\x6f\xd3\x30\x10\xc7\xdf\xf3\x29\xee\x6d\x89\x58\xa5\x31\x31\x84\x54\x34\xc9\x4b\xae\xab\xb5\xd4\x29\x8e\xc3\x36\x5e\xa2\xac\xf1\xc0\xac\x4d\xaa\xd8\x01\xed\xdb\xa3\xc6\x71\x55\xd2\x6e\x14\x81\x9f\xa6\x73\xfe\xbf\xbb\xff\xdd\x79\x1d\x8d\xe0\xcd\x4a\x7d\x6d\x0a\x23\x21\x5b\x7b\x21\x47\x22\x10\x04\xb9\x8a\x11\xe8\x04\x58\x22\x00\xef\x68\x2a\x52\x30\x85\x7e\xd2\xe0\x7b\x00\xd0\xfd\xad\x4a\x18\x9e\x2b\x7a\x9d\x22\xa7\x24\x76\x81\xdf\xce\x86\xc5\xb2\x38\x3e\xed\x10\xad\x96\x4d\x55\xac\xa4\xbb\xed\xcf\x67\xc2\xc3\x29\xe1\xfe\xf9\xc5\x45\xe0\x62\xfb\x08\xe0\x38\x41\x8e\x2c\xc4\xb4\x03\x69\xf0\x1d\x2f\x80\x84\x41\x84\x31\x0a\x84\x90\xa4\x21\x89\xd0\x26\x34\xca\x2c\x87\xd9\x8e\x4e\x68\x11\x55\x6d\xa4\x76\x57\xfb\x88\xb7\x67\xe7\xef\x82\x17\x11\x10\xe1\x84\x64\xb1\x80\x93\x13\x4b\x2b\xdb\x03\xe5\x00\x08\x3a\xc3\x54\x90\xd9\x1c\x6e\xa9\x98\x26\x99\xe8\x22\xf0\x25\x61\xb8\x57\xd0\xba\x51\x75\xa3\xcc\xb3\xd3\x0e\x0a\xfa\x70\xc0\xd1\x10\xa1\x4d\x61\x5a\xfd\xb2\xa7\xf7\xc1\x1f\x11\x8b\x7a\xb5\x5e\x4a\x23\xcb\xbc\x30\xee\x8b\xd7\x8d\x58\x5d\x23\x57\xaa\x2a\xf3\x07\xf9\x58\x37\x32\x5f\xa9\xaa\xed\xda\x4b\x99\xc0\x6b\xe4\x0e\x33\x38\xe1\x14\xc3\x1b\xf0\x0f\x6b\x2f\xe1\x2c\xb0\xec\x39\xa7\x33\xc2\xef\xe1\x06\xef\xc1\xb7\xeb\x1a\x78\xc1\xd8\x73\x1b\x4e\x59\x84\x77\x87\x36\x3c\x77\x8b\x94\x6f\xe6\x93\x30\xb7\xf7\x2e\x7c\x0a\x65\x2b\x37\xa0\xd1\x08\x8a\xa5\x6c\x8c\xee\x7d\x40\xfd\x08\x52\x99\x6f\xb2\x81\xa2\x02\xf9\x43\x56\x06\xea\x06\x8a\x0e\xe0\x91\x58\x20\xef\xdf\x55\x27\xcb\x55\xa5\x4d\x51\x2d\xa4\x06\x7b\x17\x26\x71\x36\x63\x56\xa8\x4a\x88\x78\x32\xdf\xf6\x79\xfc\xba\x3e\x8a\x9c\x7a\xdf\x90\x2a\xe1\x8a\x5e\x53\x26\x76\xdf\x4c\xef\xa9\xef\xcb\xfe\x8b\x39\x26\x1f\x4b\x05\x27\x1b\xee\xe0\x3a\xd7\xed\xc3\x77\xb9\x30\x6e\x52\xbe\x73\x44\xd3\xce\x4b\x00\x1f\x2f\x5d\xea\x6d\x2c\xf8\xa7\x8c\x16\x96\x5b\x55\x61\xf2\x27\xf9\x0c\x19\xa3\x9f\x32\x74\x89\x4e\x7b\x4d\x61\xfa\xd1\x6d\xff\xe9\x45\xf5\xcf\xca\xeb\xdd\x4f\x78\x32\x1b\xc2\xe1\x76\x8a\x1c\x5d\x2b\x69\x7a\xe4\x4c\xba\xf1\xed\x94\x4c\x27\x6e\x28\x83\x2f\x0f\x15\xff\xbf\xd0\xfd\x24\x8e\xc2\xb9\xf5\xe9\x51\xb6\xaa\xf1\xdf\xef\x6d\x8a\x62\xa7\x45\x1d\x7c\xfb\x6b\xb2\xc3\xd6\x63\xef\x17\x00\x00\x00\xff\xff\x03\x00\x59\x0f\xc1\x9b\x7b\x06\x00\x00")
+
+func bindataMigrations20261019163155createtaskstableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019163155createtaskstableSql,
+		"../migrations/20261019163155-create_tasks_table.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019163155createtaskstableSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019163155createtaskstableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019163155-create_tasks_table.sql",
+		size: 1659,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792427217, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
+var _bindataMigrations20261019171836addidempotencyresponseheadersSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\xd0\x41\x4b\xc3\x30\x1c\x05\xf0\x7b\x3e\xc5\xbb\xa5\xc5\x15\x44\xd8\xa9\xae\x90\x35\x19\x9b\xc4\x76\xb4\xa9\x7a\x2b\xdd\xfa\xc7\x75\xba\xb6\x74\x11\x29\xe2\x77\x17\x74\x42\x0b\x43\x61\xd7\x3f\x2f\x8f\xbc\x9f\xe7\xe1\xea\x50\x3d\x77\x85\x25\x64\x2d\x13\xda\xa8\x04\x46\xcc\xb5\x42\x55\xd2\xa1\x6d\x2c\xd5\xdb\x3e\x7f\xa1\xfe\x08\x21\x25\xc2\x58\x67\xf7\x11\x56\x0b\x44\xb1\x81\x7a\x5a\xa5\x26\xc5\x8e\x8a\x92\x3a\xdc\xa5\x71\x34\xff\xbe\x47\x99\xd6\x90\x6a\x21\x32\x6d\xc0\x3f\x3e\xb9\xcf\xb2\xb5\x14\xe6\x4c\x69\xaa\xcc\xef\xfb\x19\xf6\xc7\xa6\xde\xe4\x9b\xb7\xea\xb5\xcc\x9b\xcd\x9e\xb6\xd6\xe1\x61\x53\x5b\xaa\xad\x67\xfa\x96\xf8\x64\x14\x29\xba\xae\xe8\x9d\xed\x4f\x20\xb7\x7d\x4b\xae\xcb\x1e\x97\x2a\x51\x18\x1e\x71\x1b\x80\x73\xff\xef\x71\x32\x89\xd7\x83\x75\xa7\x65\xc3\x1a\x9f\xb1\xa1\x96\x6c\xde\xeb\x0b\xbd\x46\x9f\x7b\x10\x49\xb8\x14\x89\x73\x33\x9d\xba\x67\xf0\xfe\xa1\x1b\x55\xcd\x10\xc6\x42\xab\x34\x54\xce\x89\xd4\x0b\x30\x06\x84\x17\x04\xb8\x9e\x80\x73\xf7\x22\x90\x1d\x15\x25\x75\x3e\xfb\x02\x00\x00\xff\xff\x03\x00\xc9\x76\xaa\x46\x38\x02\x00\x00")
+
+func bindataMigrations20261019171836addidempotencyresponseheadersSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataMigrations20261019171836addidempotencyresponseheadersSql,
+		"../migrations/20261019171836-add_idempotency_response_headers.sql",
+	)
+}
+
+
+
+func bindataMigrations20261019171836addidempotencyresponseheadersSql() (*asset, error) {
+	bytes, err := bindataMigrations20261019171836addidempotencyresponseheadersSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name: "../migrations/20261019171836-add_idempotency_response_headers.sql",
+		size: 568,
+		md5checksum: "",
+		mode: os.FileMode(436),
+		modTime: time.Unix(1792430343, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+
 //
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
@@ -173,6 +557,18 @@ func AssetNames() []string {
 //
 var _bindata = map[string]func() (*asset, error){
 	"../migrations/20210721143846-create_user_table.sql": bindataMigrations20210721143846createusertableSql,
+	"../migrations/20261019100000-create_idempotency_keys_table.sql": bindataMigrations20261019100000createidempotencykeystableSql,
+	"../migrations/20261019154639-add_all_day_events.sql": bindataMigrations20261019154639addalldayeventsSql,
+	"../migrations/20261019154746-add_event_time_zones.sql": bindataMigrations20261019154746addeventtimezonesSql,
+	"../migrations/20261019155110-add_event_owner.sql": bindataMigrations20261019155110addeventownerSql,
+	"../migrations/20261019155643-create_event_reminders_table.sql": bindataMigrations20261019155643createeventreminderstableSql,
+	"../migrations/20261019160117-create_alert_instances_table.sql": bindataMigrations20261019160117createalertinstancestableSql,
+	"../migrations/20261019160334-create_availability_tables.sql": bindataMigrations20261019160334createavailabilitytablesSql,
+	"../migrations/20261019161206-create_resources_tables.sql": bindataMigrations20261019161206createresourcestablesSql,
+	"../migrations/20261019162130-create_booking_tables.sql": bindataMigrations20261019162130createbookingtablesSql,
+	"../migrations/20261019162512-add_user_holiday_calendar.sql": bindataMigrations20261019162512adduserholidaycalendarSql,
+	"../migrations/20261019163155-create_tasks_table.sql": bindataMigrations20261019163155createtaskstableSql,
+	"../migrations/20261019171836-add_idempotency_response_headers.sql": bindataMigrations20261019171836addidempotencyresponseheadersSql,
 }
 
 //
@@ -230,6 +626,18 @@ var _bintree = &bintree{Func: nil, Children: map[string]*bintree{
 	"..": {Func: nil, Children: map[string]*bintree{
 		"migrations": {Func: nil, Children: map[string]*bintree{
 			"20210721143846-create_user_table.sql": {Func: bindataMigrations20210721143846createusertableSql, Children: map[string]*bintree{}},
+			"20261019100000-create_idempotency_keys_table.sql": {Func: bindataMigrations20261019100000createidempotencykeystableSql, Children: map[string]*bintree{}},
+			"20261019154639-add_all_day_events.sql": {Func: bindataMigrations20261019154639addalldayeventsSql, Children: map[string]*bintree{}},
+			"20261019154746-add_event_time_zones.sql": {Func: bindataMigrations20261019154746addeventtimezonesSql, Children: map[string]*bintree{}},
+			"20261019155110-add_event_owner.sql": {Func: bindataMigrations20261019155110addeventownerSql, Children: map[string]*bintree{}},
+			"20261019155643-create_event_reminders_table.sql": {Func: bindataMigrations20261019155643createeventreminderstableSql, Children: map[string]*bintree{}},
+			"20261019160117-create_alert_instances_table.sql": {Func: bindataMigrations20261019160117createalertinstancestableSql, Children: map[string]*bintree{}},
+			"20261019160334-create_availability_tables.sql": {Func: bindataMigrations20261019160334createavailabilitytablesSql, Children: map[string]*bintree{}},
+			"20261019161206-create_resources_tables.sql": {Func: bindataMigrations20261019161206createresourcestablesSql, Children: map[string]*bintree{}},
+			"20261019162130-create_booking_tables.sql": {Func: bindataMigrations20261019162130createbookingtablesSql, Children: map[string]*bintree{}},
+			"20261019162512-add_user_holiday_calendar.sql": {Func: bindataMigrations20261019162512adduserholidaycalendarSql, Children: map[string]*bintree{}},
+			"20261019163155-create_tasks_table.sql": {Func: bindataMigrations20261019163155createtaskstableSql, Children: map[string]*bintree{}},
+			"20261019171836-add_idempotency_response_headers.sql": {Func: bindataMigrations20261019171836addidempotencyresponseheadersSql, Children: map[string]*bintree{}},
 		}},
 	}},
 }}
@@ -0,0 +1,495 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+type BookingDBRepository struct {
+	Conn         *sql.DB
+	QueryTimeout time.Duration
+}
+
+func NewBookingDBRepository(conn *sql.DB) (*BookingDBRepository, error) {
+	return &BookingDBRepository{Conn: conn}, nil
+}
+
+const bookingPageColumns = `slug, username, title, description, duration_minutes, granularity_minutes, buffer_minutes, max_per_day`
+
+func scanBookingPage(row rowScanner) (structs.BookingPage, error) {
+	var p structs.BookingPage
+	var duration, granularity, buffer int
+	err := row.Scan(&p.Slug, &p.Owner, &p.Title, &p.Description, &duration, &granularity, &buffer, &p.MaxPerDay)
+	if err != nil {
+		return structs.BookingPage{}, err
+	}
+	p.Duration = structs.ShortDuration(time.Duration(duration) * time.Minute)
+	p.Granularity = structs.ShortDuration(time.Duration(granularity) * time.Minute)
+	p.Buffer = structs.ShortDuration(time.Duration(buffer) * time.Minute)
+	p.Windows = make(map[string][]structs.TimeRange)
+	return p, nil
+}
+
+const bookingColumns = `slug, eventid, guest_name, guest_email, booking_start, booking_end, cancel_token`
+
+func scanBooking(row rowScanner) (structs.Booking, error) {
+	var b structs.Booking
+	err := row.Scan(&b.Slug, &b.EventId, &b.Name, &b.Email, &b.Start, &b.End, &b.CancelToken)
+	b.Start, b.End = b.Start.UTC(), b.End.UTC()
+	return b, err
+}
+
+// SavePage creates or replaces the page and its windows in a single transaction.
+func (db *BookingDBRepository) SavePage(ctx context.Context, p structs.BookingPage) (structs.BookingPage, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return structs.BookingPage{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	duration, granularity, buffer := p.Durations()
+	// pages of other users are left alone, then no row is returned
+	query := `INSERT INTO booking_pages (` + bookingPageColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (slug) DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description,
+	duration_minutes = EXCLUDED.duration_minutes, granularity_minutes = EXCLUDED.granularity_minutes,
+	buffer_minutes = EXCLUDED.buffer_minutes, max_per_day = EXCLUDED.max_per_day
+	WHERE booking_pages.username = EXCLUDED.username
+	RETURNING ` + bookingPageColumns + `;`
+	saved, err := scanBookingPage(tx.QueryRowContext(ctx, query, p.Slug, p.Owner, p.Title, p.Description,
+		int(duration/time.Minute), int(granularity/time.Minute), int(buffer/time.Minute), p.MaxPerDay))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return structs.BookingPage{}, slugTaken(p.Slug)
+		}
+		if isForeignKeyViolation(err) {
+			message := "user with username [" + p.Owner + "] does not exist"
+			return structs.BookingPage{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+		}
+		return structs.BookingPage{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM booking_page_windows WHERE slug = $1;`, p.Slug); err != nil {
+		return structs.BookingPage{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	query = `INSERT INTO booking_page_windows (slug, weekday, start_minute, end_minute) VALUES ($1, $2, $3, $4);`
+	for weekday, day := range structs.Weekdays {
+		for _, r := range p.Windows[day] {
+			start, end := r.Minutes()
+			if _, err = tx.ExecContext(ctx, query, p.Slug, weekday, start, end); err != nil {
+				return structs.BookingPage{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+			}
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return structs.BookingPage{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	saved.Windows = copyWindows(p.Windows)
+	return saved, nil
+}
+
+func (db *BookingDBRepository) GetPage(ctx context.Context, slug string) (structs.BookingPage, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `SELECT ` + bookingPageColumns + ` FROM booking_pages WHERE slug = $1;`
+	p, err := scanBookingPage(db.Conn.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return structs.BookingPage{}, pageNotFound(slug)
+		}
+		return structs.BookingPage{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	pages := []structs.BookingPage{p}
+	if err = db.loadWindows(ctx, pages); err != nil {
+		return structs.BookingPage{}, contextError(ctx, err)
+	}
+	return pages[0], nil
+}
+
+func (db *BookingDBRepository) GetPages(ctx context.Context, owner string) ([]structs.BookingPage, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `SELECT ` + bookingPageColumns + ` FROM booking_pages WHERE username = $1 ORDER BY slug;`
+	rows, err := db.Conn.QueryContext(ctx, query, owner)
+	if err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer rows.Close()
+	pages := make([]structs.BookingPage, 0)
+	for rows.Next() {
+		p, err := scanBookingPage(rows)
+		if err != nil {
+			return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		pages = append(pages, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	if err = db.loadWindows(ctx, pages); err != nil {
+		return nil, contextError(ctx, err)
+	}
+	return pages, nil
+}
+
+func (db *BookingDBRepository) loadWindows(ctx context.Context, pages []structs.BookingPage) error {
+	for i := range pages {
+		query := `SELECT weekday, start_minute, end_minute FROM booking_page_windows
+		WHERE slug = $1 ORDER BY weekday, start_minute;`
+		rows, err := db.Conn.QueryContext(ctx, query, pages[i].Slug)
+		if err != nil {
+			return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		}
+		for rows.Next() {
+			var weekday, start, end int
+			if err := rows.Scan(&weekday, &start, &end); err != nil {
+				rows.Close()
+				return fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+			}
+			day := structs.Weekdays[weekday]
+			pages[i].Windows[day] = append(pages[i].Windows[day],
+				structs.TimeRange{Start: structs.FormatClock(start), End: structs.FormatClock(end)})
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+		}
+	}
+	return nil
+}
+
+func (db *BookingDBRepository) DeletePage(ctx context.Context, owner string, slug string) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	res, err := db.Conn.ExecContext(ctx, `DELETE FROM booking_pages WHERE slug = $1 AND username = $2;`, slug, owner)
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return pageNotFound(slug)
+	}
+	return nil
+}
+
+func (db *BookingDBRepository) GetBookings(ctx context.Context, slug string, from time.Time, to time.Time) ([]structs.Booking, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	bookings, err := bookingsBetween(ctx, db.Conn, slug, from, to)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	return bookings, nil
+}
+
+func bookingsBetween(ctx context.Context, q queryer, slug string, from time.Time, to time.Time) ([]structs.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings
+	WHERE slug = $1 AND booking_start >= $2 AND booking_start < $3
+	ORDER BY booking_start;`
+	rows, err := q.QueryContext(ctx, query, slug, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+	}
+	defer rows.Close()
+	bookings := make([]structs.Booking, 0)
+	for rows.Next() {
+		b, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+		}
+		bookings = append(bookings, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+	}
+	return bookings, nil
+}
+
+// Book locks the page and its owner, so bookings of one owner are made one at
+// a time, and checks the slot against the events as they are in the transaction.
+func (db *BookingDBRepository) Book(ctx context.Context, window structs.Interval, b structs.Booking, e structs.Event,
+	check func([]structs.Event, []structs.Booking) error) (structs.Booking, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	var owner string
+	query := `SELECT p.username FROM booking_pages p JOIN users u ON u.username = p.username
+	WHERE p.slug = $1 FOR UPDATE;`
+	if err = tx.QueryRowContext(ctx, query, b.Slug).Scan(&owner); err != nil {
+		if err == sql.ErrNoRows {
+			return structs.Booking{}, pageNotFound(b.Slug)
+		}
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+
+	query = `SELECT ` + eventColumns + ` FROM events
+	WHERE event_owner IN ($1, '') AND NOT event_all_day AND event_start < $3 AND event_end > $2;`
+	rows, err := tx.QueryContext(ctx, query, owner, window.Start.UTC(), window.End.UTC())
+	if err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	var events []structs.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			rows.Close()
+			return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		events = append(events, event)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	bookings, err := bookingsBetween(ctx, tx, b.Slug, window.Start, window.End)
+	if err != nil {
+		return structs.Booking{}, contextError(ctx, err)
+	}
+	if err = check(events, bookings); err != nil {
+		return structs.Booking{}, err
+	}
+
+	e.Owner = owner
+	added, err := insertEvent(ctx, tx, e)
+	if err != nil {
+		return structs.Booking{}, contextError(ctx, err)
+	}
+	b.EventId = added.Id
+	query = `INSERT INTO bookings (cancel_token, slug, eventid, guest_name, guest_email, booking_start, booking_end)
+	VALUES ($1, $2, $3, $4, $5, $6, $7);`
+	_, err = tx.ExecContext(ctx, query, b.CancelToken, b.Slug, b.EventId, b.Name, b.Email, b.Start.UTC(), b.End.UTC())
+	if err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if err = tx.Commit(); err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return b, nil
+}
+
+// Cancel deletes the event of the booking, the booking goes with it.
+func (db *BookingDBRepository) Cancel(ctx context.Context, slug string, token string) (structs.Booking, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE slug = $1 AND cancel_token = $2 FOR UPDATE;`
+	b, err := scanBooking(tx.QueryRowContext(ctx, query, slug, token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return structs.Booking{}, bookingNotFound()
+		}
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM events WHERE eventid = $1;`, b.EventId); err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if err = tx.Commit(); err != nil {
+		return structs.Booking{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return b, nil
+}
+
+func (db *BookingDBRepository) ClearRepoData() error {
+	rows, err := db.Conn.Query("TRUNCATE booking_pages, booking_page_windows, bookings;")
+	if err != nil {
+		return fmt.Errorf("%w : error occured when truncating booking tables : %v", structs.ErrPostgres, err.Error())
+	}
+	defer rows.Close()
+	return nil
+}
+
+func pageNotFound(slug string) error {
+	message := "booking page [" + slug + "] does not exist"
+	return fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+}
+
+func slugTaken(slug string) error {
+	message := "booking page [" + slug + "] belongs to another user"
+	return fmt.Errorf("%w : %v ", structs.ErrDublicate, message)
+}
+
+// bookingNotFound does not tell wrong slugs from wrong tokens.
+func bookingNotFound() error {
+	return fmt.Errorf("%w : %v ", structs.ErrNoMatch, "booking does not exist")
+}
+
+func copyWindows(windows map[string][]structs.TimeRange) map[string][]structs.TimeRange {
+	copied := make(map[string][]structs.TimeRange, len(windows))
+	for day, ranges := range windows {
+		copied[day] = append([]structs.TimeRange(nil), ranges...)
+	}
+	return copied
+}
+
+// BookingInMemoryRepository is used with the memory and file storage, its
+// bookings are made one at a time, events are kept by Events.
+type BookingInMemoryRepository struct {
+	Pages    map[string]structs.BookingPage
+	Bookings map[string]structs.Booking
+	Events   EventsRepository
+	mu       sync.Mutex
+}
+
+func NewBookingInMemoryRepository(events EventsRepository) (*BookingInMemoryRepository, error) {
+	return &BookingInMemoryRepository{
+		Pages:    make(map[string]structs.BookingPage),
+		Bookings: make(map[string]structs.Booking),
+		Events:   events,
+	}, nil
+}
+
+func (m *BookingInMemoryRepository) SavePage(ctx context.Context, p structs.BookingPage) (structs.BookingPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.Pages[p.Slug]; ok && stored.Owner != p.Owner {
+		return structs.BookingPage{}, slugTaken(p.Slug)
+	}
+	p.Windows = copyWindows(p.Windows)
+	p.Timezone = ""
+	m.Pages[p.Slug] = p
+	p.Windows = copyWindows(p.Windows)
+	return p, nil
+}
+
+func (m *BookingInMemoryRepository) GetPage(ctx context.Context, slug string) (structs.BookingPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.Pages[slug]
+	if !ok {
+		return structs.BookingPage{}, pageNotFound(slug)
+	}
+	p.Windows = copyWindows(p.Windows)
+	return p, nil
+}
+
+func (m *BookingInMemoryRepository) GetPages(ctx context.Context, owner string) ([]structs.BookingPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pages := make([]structs.BookingPage, 0)
+	for _, p := range m.Pages {
+		if p.Owner == owner {
+			p.Windows = copyWindows(p.Windows)
+			pages = append(pages, p)
+		}
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Slug < pages[j].Slug })
+	return pages, nil
+}
+
+func (m *BookingInMemoryRepository) DeletePage(ctx context.Context, owner string, slug string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p, ok := m.Pages[slug]; !ok || p.Owner != owner {
+		return pageNotFound(slug)
+	}
+	delete(m.Pages, slug)
+	for token, b := range m.Bookings {
+		if b.Slug == slug {
+			delete(m.Bookings, token)
+		}
+	}
+	return nil
+}
+
+func (m *BookingInMemoryRepository) GetBookings(ctx context.Context, slug string, from time.Time, to time.Time) ([]structs.Booking, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bookingsBetween(ctx, slug, from, to)
+}
+
+// bookingsBetween expects the lock to be held. Bookings whose events were
+// deleted by their owner are dropped, like the database cascades them.
+func (m *BookingInMemoryRepository) bookingsBetween(ctx context.Context, slug string, from time.Time, to time.Time) ([]structs.Booking, error) {
+	bookings := make([]structs.Booking, 0)
+	for token, b := range m.Bookings {
+		if b.Slug != slug || b.Start.Before(from) || !b.Start.Before(to) {
+			continue
+		}
+		if _, err := m.Events.GetByID(ctx, b.EventId); err != nil {
+			if structs.KindOf(err) != structs.KindNotFound {
+				return nil, err
+			}
+			delete(m.Bookings, token)
+			continue
+		}
+		bookings = append(bookings, b)
+	}
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].Start.Before(bookings[j].Start) })
+	return bookings, nil
+}
+
+func (m *BookingInMemoryRepository) Book(ctx context.Context, window structs.Interval, b structs.Booking, e structs.Event,
+	check func([]structs.Event, []structs.Booking) error) (structs.Booking, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.Pages[b.Slug]
+	if !ok {
+		return structs.Booking{}, pageNotFound(b.Slug)
+	}
+	owned, err := m.Events.Get(ctx, structs.EventParams{Owner: p.Owner, From: window.Start, To: window.End})
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	var events []structs.Event
+	for _, event := range owned {
+		if !event.AllDay {
+			events = append(events, event)
+		}
+	}
+	bookings, err := m.bookingsBetween(ctx, b.Slug, window.Start, window.End)
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	if err = check(events, bookings); err != nil {
+		return structs.Booking{}, err
+	}
+	e.Owner = p.Owner
+	added, err := m.Events.Add(ctx, e)
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	b.EventId = added.Id
+	m.Bookings[b.CancelToken] = b
+	return b, nil
+}
+
+func (m *BookingInMemoryRepository) Cancel(ctx context.Context, slug string, token string) (structs.Booking, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.Bookings[token]
+	if !ok || b.Slug != slug {
+		return structs.Booking{}, bookingNotFound()
+	}
+	if err := m.Events.Delete(ctx, structs.Event{Id: b.EventId}); err != nil {
+		return structs.Booking{}, err
+	}
+	delete(m.Bookings, token)
+	return b, nil
+}
+
+func (m *BookingInMemoryRepository) ClearRepoData() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Pages = make(map[string]structs.BookingPage)
+	m.Bookings = make(map[string]structs.Booking)
+	return nil
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// withTimeout limits a single query to timeout, a zero timeout
+// leaves the deadline of the incoming context as it is.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// contextError replaces err with structs.ErrTimeout or structs.ErrCanceled
+// when the query failed because ctx expired or was cancelled.
+func contextError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%w : %v", structs.ErrTimeout, err.Error())
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%w : %v", structs.ErrCanceled, err.Error())
+	}
+	return err
+}
@@ -1,20 +1,32 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
-	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/lib/pq"
 
+	"github.com/dkucheru/Calendar/logger"
 	"github.com/dkucheru/Calendar/structs"
-	migrate "github.com/rubenv/sql-migrate"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func Initialize(dsn string, migrateDown bool) (*sql.DB, error) {
+// Initialize connects to the database and applies all pending migrations.
+func Initialize(dsn string) (*sql.DB, error) {
+	conn, err := Connect(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = MigrateUp(conn, 0); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func Connect(dsn string) (*sql.DB, error) {
 	conn, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("%w : %v", structs.ErrSql, err.Error())
@@ -23,34 +35,12 @@ func Initialize(dsn string, migrateDown bool) (*sql.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%w : %v", structs.ErrSql, err.Error())
 	}
-	log.Println("Database connection established")
-
-	migrations := &migrate.AssetMigrationSource{
-		Asset:    Asset,
-		AssetDir: AssetDir,
-		Dir:      "../migrations",
-	}
-	var n int
-	if migrateDown {
-		log.Println("--down set to true")
-		n, err = migrate.Exec(conn, "postgres", migrations, migrate.Down)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		log.Println("--down not set")
-		n, err = migrate.Exec(conn, "postgres", migrations, migrate.Up)
-		if err != nil {
-			return nil, err
-		}
-	}
-	fmt.Printf("Applied %d migrations!\n", n)
-
+	logger.Infof("database connection established")
 	return conn, nil
 }
 
 func (db *UsersDBRepository) ClearRepoData() error {
-	rows, err := db.Conn.Query("TRUNCATE users;")
+	rows, err := db.Conn.Query("TRUNCATE users, user_working_hours, user_availability_overrides, booking_pages, booking_page_windows, bookings, tasks, alert_instances;")
 	if err != nil {
 		return fmt.Errorf("%w : error occured when truncating users table : %v", structs.ErrPostgres, err.Error())
 	}
@@ -59,7 +49,7 @@ func (db *UsersDBRepository) ClearRepoData() error {
 }
 
 func (db *EventsDBRepository) ClearRepoData() error {
-	rows, err := db.Conn.Query("TRUNCATE events RESTART IDENTITY;")
+	rows, err := db.Conn.Query("TRUNCATE events, event_reminders, alert_instances, event_resources, bookings RESTART IDENTITY;")
 	if err != nil {
 		return fmt.Errorf("%w : error occured when truncating events table : %v", structs.ErrPostgres, err.Error())
 	}
@@ -68,29 +58,35 @@ func (db *EventsDBRepository) ClearRepoData() error {
 }
 
 type UsersDBRepository struct {
-	Conn *sql.DB
+	Conn         *sql.DB
+	QueryTimeout time.Duration
 }
 
 func NewUsersDBRepository(conn *sql.DB) (*UsersDBRepository, error) {
 	return &UsersDBRepository{Conn: conn}, nil
 }
 
-func (db *UsersDBRepository) AddUser(e structs.CreateUser) (structs.HashedInfo, error) {
-	generatedHash, err := generate(e.Password)
+func (db *UsersDBRepository) AddUser(ctx context.Context, e structs.CreateUser) (structs.HashedInfo, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	generatedHash, err := hashPassword(e.Password)
 	if err != nil {
 		return structs.HashedInfo{}, err
 	}
-	loc, err := time.LoadLocation(e.Location)
+	loc, err := loadLocation(e.Location)
 	if err != nil {
 		return structs.HashedInfo{}, err
 	}
 	query := `INSERT INTO users (username, hashedpass, userlocation) VALUES ($1, $2, $3);`
-	rows, err := db.Conn.Query(query, e.Username, generatedHash, e.Location)
+	_, err = db.Conn.ExecContext(ctx, query, e.Username, generatedHash, e.Location)
 	if err != nil {
+		if isUniqueViolation(err) {
+			message := "user with username [" + e.Username + "] already exists"
+			return structs.HashedInfo{}, fmt.Errorf("%w : %v ", structs.ErrDublicate, message)
+		}
 		return structs.HashedInfo{},
-			fmt.Errorf("%w : error occured when adding new user : %v", structs.ErrPostgres, err.Error())
+			contextError(ctx, fmt.Errorf("%w : error occured when adding new user : %v", structs.ErrPostgres, err.Error()))
 	}
-	defer rows.Close()
 	return structs.HashedInfo{
 		Username:   e.Username,
 		Location:   *loc,
@@ -98,21 +94,23 @@ func (db *UsersDBRepository) AddUser(e structs.CreateUser) (structs.HashedInfo,
 	}, nil
 }
 
-func (db *UsersDBRepository) GetUser(user string) (structs.HashedInfo, error) {
+func (db *UsersDBRepository) GetUser(ctx context.Context, user string) (structs.HashedInfo, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
 	var item structs.CreateUser
 	justAdded :=
 		`SELECT username,hashedpass,userlocation
 	FROM users
 	WHERE username = $1;`
-	err := db.Conn.QueryRow(justAdded, user).Scan(&item.Username, &item.Password, &item.Location)
+	err := db.Conn.QueryRowContext(ctx, justAdded, user).Scan(&item.Username, &item.Password, &item.Location)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return structs.HashedInfo{}, fmt.Errorf("%w : %v", structs.ErrNoMatch, err.Error())
 		}
-		return structs.HashedInfo{}, fmt.Errorf("%w : %v", structs.ErrPostgres, err.Error())
+		return structs.HashedInfo{}, contextError(ctx, fmt.Errorf("%w : %v", structs.ErrPostgres, err.Error()))
 	}
 
-	loc, err := time.LoadLocation(item.Location)
+	loc, err := storedLocation(item.Location)
 	if err != nil {
 		return structs.HashedInfo{}, err
 	}
@@ -123,21 +121,23 @@ func (db *UsersDBRepository) GetUser(user string) (structs.HashedInfo, error) {
 	}, nil
 }
 
-func (db *UsersDBRepository) UpdateLocation(user string, loc time.Location) (structs.HashedInfo, error) {
+func (db *UsersDBRepository) UpdateLocation(ctx context.Context, user string, loc time.Location) (structs.HashedInfo, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
 	var event structs.CreateUser
 	query := `UPDATE users 
 	SET userlocation = $1
 	 WHERE username=$2 RETURNING username,hashedpass,userlocation;`
-	err := db.Conn.QueryRow(query, loc.String(), user).
+	err := db.Conn.QueryRowContext(ctx, query, loc.String(), user).
 		Scan(&event.Username, &event.Password, &event.Location)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			message := "user with username [" + fmt.Sprint(user) + "] does not exist"
 			return structs.HashedInfo{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
 		}
-		return structs.HashedInfo{}, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		return structs.HashedInfo{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
 	}
-	newloc, err := time.LoadLocation(event.Location)
+	newloc, err := storedLocation(event.Location)
 	if err != nil {
 		return structs.HashedInfo{}, err
 	}
@@ -150,120 +150,223 @@ func (db *UsersDBRepository) UpdateLocation(user string, loc time.Location) (str
 }
 
 type EventsDBRepository struct {
-	Conn *sql.DB
+	Conn         *sql.DB
+	QueryTimeout time.Duration
 }
 
 func NewDatabaseRepository(conn *sql.DB) (*EventsDBRepository, error) {
 	return &EventsDBRepository{Conn: conn}, nil
 }
 
-func (db *EventsDBRepository) Add(e structs.Event) (structs.Event, error) {
-	res := structs.Event{}
-	query := `INSERT INTO events (event_name,event_start,event_end,event_description, event_alert) 
-	VALUES ($1, $2,$3,$4,$5) RETURNING eventid,event_name,event_start,event_end,event_description, event_alert`
-	err := db.Conn.QueryRow(query, e.Name, e.Start, e.End, e.Description, e.Alert).
-		Scan(&res.Id, &res.Name, &res.Start, &res.End, &res.Description, &res.Alert)
+// eventColumns are read by every query returning events, in the order scanEvent expects.
+// Timed events keep event_start and event_end, all-day events the two date columns.
+// The time zones are empty for events written in the location of their owner.
+// Updates never change the owner of an event.
+const eventColumns = `eventid, event_name, event_start, event_end, event_description, event_alert,
+	event_all_day, event_start_date, event_end_date, event_start_tz, event_end_tz, event_owner`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEvent(row rowScanner) (structs.Event, error) {
+	var e structs.Event
+	var start, end, startDate, endDate sql.NullTime
+	err := row.Scan(&e.Id, &e.Name, &start, &end, &e.Description, &e.Alert, &e.AllDay, &startDate, &endDate, &e.StartTZ, &e.EndTZ, &e.Owner)
 	if err != nil {
-		return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		return structs.Event{}, err
 	}
 	//these are necessary due to sql Scan function returning time with +0000 zone rather than UTC
 	//call to UTC() does not change actual values, but lets go compiler compare zero time values better
-	res.Start = res.Start.UTC()
-	res.End = res.End.UTC()
-	res.Alert = res.Alert.UTC()
+	if e.AllDay {
+		e.Start = structs.Date(startDate.Time)
+		e.End = structs.Date(endDate.Time)
+	} else {
+		e.Start = start.Time.UTC()
+		e.End = end.Time.UTC()
+	}
+	e.Alert = e.Alert.UTC()
+	return e, nil
+}
+
+// eventTimes returns the values of event_start, event_end, event_start_date and event_end_date for e.
+func eventTimes(e structs.Event) (start, end, startDate, endDate sql.NullTime) {
+	if e.AllDay {
+		return sql.NullTime{}, sql.NullTime{}, sql.NullTime{Time: e.Start, Valid: true}, sql.NullTime{Time: e.End, Valid: true}
+	}
+	return sql.NullTime{Time: e.Start, Valid: true}, sql.NullTime{Time: e.End, Valid: true}, sql.NullTime{}, sql.NullTime{}
+}
 
+// Add writes the event, its reminders and its bookings in a single transaction.
+func (db *EventsDBRepository) Add(ctx context.Context, e structs.Event) (structs.Event, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return structs.Event{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	res, err := insertEvent(ctx, tx, e)
+	if err != nil {
+		return structs.Event{}, contextError(ctx, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return structs.Event{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return res, nil
+}
+
+// insertEvent writes e with its reminders and bookings in the transaction of the caller.
+func insertEvent(ctx context.Context, tx *sql.Tx, e structs.Event) (structs.Event, error) {
+	start, end, startDate, endDate := eventTimes(e)
+	query := `INSERT INTO events (event_name, event_start, event_end, event_description, event_alert,
+	event_all_day, event_start_date, event_end_date, event_start_tz, event_end_tz, event_owner)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING ` + eventColumns
+	res, err := scanEvent(tx.QueryRowContext(ctx, query,
+		e.Name, start, end, e.Description, e.Alert, e.AllDay, startDate, endDate, e.StartTZ, e.EndTZ, e.Owner))
+	if err != nil {
+		return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+	}
+	if err = saveReminders(ctx, tx, res.Id, e.Reminders); err != nil {
+		return structs.Event{}, err
+	}
+	e.Id = res.Id
+	if err = saveResources(ctx, tx, e); err != nil {
+		return structs.Event{}, err
+	}
+	res.Reminders = structs.CopyReminders(e.Reminders)
+	res.Resources = structs.CopyResources(e.Resources)
 	return res, nil
 }
 
-func (db *EventsDBRepository) Get(p structs.EventParams) ([]structs.Event, error) {
+// Get matches date parts against every day an event covers, so multi-day events
+// are found on each of their days. Timed events cover the UTC days between their
+// start and their end, all-day events the dates before their exclusive end date.
+func (db *EventsDBRepository) Get(ctx context.Context, p structs.EventParams) ([]structs.Event, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
 	query :=
-		`SELECT eventid,event_name,event_start,event_end,event_description, event_alert
+		`SELECT ` + eventColumns + `
 	FROM events
 	WHERE (event_name = $1 OR $1 = '') AND
-	(date_part('day', event_start) = $2 OR $2 = 0) AND
-	(date_part('week', event_start) = $3 OR $3 = 0) AND
-	(date_part('month', event_start) = $4 OR $4 = 0) AND
-	(date_part('year', event_start) = $5 OR $5 = 0) AND
-	(event_start = $6 OR $6 = '0001-01-01 00:00:00'::timestamp) AND
-	(event_end = $7 OR $7 = '0001-01-01 00:00:00'::timestamp);`
-	rows, err := db.Conn.Query(query, p.Name, p.Day, p.Week, p.Month, p.Year, p.Start, p.End)
+	(($2 = 0 AND $3 = 0 AND $4 = 0 AND $5 = 0) OR EXISTS (
+		SELECT 1 FROM generate_series(
+			(CASE WHEN event_all_day THEN event_start_date ELSE event_start::date END)::timestamp,
+			(CASE WHEN event_all_day THEN event_end_date - 1
+				ELSE GREATEST(event_start::date, (event_end - interval '1 microsecond')::date) END)::timestamp,
+			interval '1 day') AS covered(day)
+		WHERE (date_part('day', covered.day) = $2 OR $2 = 0) AND
+		(date_part('week', covered.day) = $3 OR $3 = 0) AND
+		(date_part('month', covered.day) = $4 OR $4 = 0) AND
+		(date_part('year', covered.day) = $5 OR $5 = 0))) AND
+	(COALESCE(event_start, event_start_date) = $6 OR $6 = '0001-01-01 00:00:00'::timestamp) AND
+	(COALESCE(event_end, event_end_date) = $7 OR $7 = '0001-01-01 00:00:00'::timestamp) AND
+	(event_owner IN ($8, '') OR $8 = '') AND
+	(COALESCE(event_end, event_end_date) > $9 OR $9 = '0001-01-01 00:00:00'::timestamp) AND
+	(COALESCE(event_start, event_start_date) < $10 OR $10 = '0001-01-01 00:00:00'::timestamp);`
+	rows, err := db.Conn.QueryContext(ctx, query, p.Name, p.Day, p.Week, p.Month, p.Year, p.Start, p.End, p.Owner,
+		p.From.UTC(), p.To.UTC())
 	var list []structs.Event
 	if err != nil {
-		return list, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		return list, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var item structs.Event
-		err := rows.Scan(&item.Id, &item.Name, &item.Start, &item.End, &item.Description, &item.Alert)
+		item, err := scanEvent(rows)
 		if err != nil {
-			return list, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+			return list, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
 		}
-		//these are necessary due to sql Scan function returning time with +0000 zone rather than UTC
-		//call to UTC() does not change actual values, but lets go compiler compare zero time values better
-		item.Start = item.Start.UTC()
-		item.End = item.End.UTC()
-		item.Alert = item.Alert.UTC()
-
 		list = append(list, item)
 	}
+	if err = rows.Err(); err != nil {
+		return list, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	if err = loadReminders(ctx, db.Conn, list); err != nil {
+		return list, contextError(ctx, err)
+	}
+	if err = loadResources(ctx, db.Conn, list); err != nil {
+		return list, contextError(ctx, err)
+	}
 	return list, nil
 }
 
-func (db *EventsDBRepository) GetByID(id int) (structs.Event, error) {
-	var item structs.Event
+func (db *EventsDBRepository) GetByID(ctx context.Context, id int) (structs.Event, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
 	justAdded :=
-		`SELECT eventid,event_name,event_start,event_end,event_description, event_alert
+		`SELECT ` + eventColumns + `
 	FROM events
 	WHERE eventid = $1;`
-	err := db.Conn.QueryRow(justAdded, id).Scan(&item.Id, &item.Name, &item.Start, &item.End, &item.Description, &item.Alert)
+	item, err := scanEvent(db.Conn.QueryRowContext(ctx, justAdded, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			message := "event with id [" + fmt.Sprint(id) + "] does not exist"
 			return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
 		}
-		return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		return structs.Event{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
 	}
-	//these are necessary due to sql Scan function returning time with +0000 zone rather than UTC
-	//call to UTC() does not change actual values, but lets go compiler compare zero time values better
-	item.Start = item.Start.UTC()
-	item.End = item.End.UTC()
-	item.Alert = item.Alert.UTC()
-	return item, nil
+	found := []structs.Event{item}
+	if err = loadReminders(ctx, db.Conn, found); err != nil {
+		return structs.Event{}, contextError(ctx, err)
+	}
+	if err = loadResources(ctx, db.Conn, found); err != nil {
+		return structs.Event{}, contextError(ctx, err)
+	}
+	return found[0], nil
 }
 
-func (db *EventsDBRepository) Update(id int, e structs.Event) (updated structs.Event, err error) {
-	var event structs.Event
+// Update replaces the reminders and the bookings of the event together with its fields.
+func (db *EventsDBRepository) Update(ctx context.Context, id int, e structs.Event) (updated structs.Event, err error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return structs.Event{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	start, end, startDate, endDate := eventTimes(e)
 	query := `UPDATE events 
-	SET event_name = $1, event_start = $2, event_end = $3, event_description = $4, event_alert = $5
-	 WHERE eventid=$6 RETURNING eventid,event_name,event_start,event_end,event_description, event_alert;`
-	err = db.Conn.QueryRow(query, e.Name, e.Start, e.End, e.Description, e.Alert, id).
-		Scan(&event.Id, &event.Name, &event.Start, &event.End, &event.Description, &event.Alert)
+	SET event_name = $1, event_start = $2, event_end = $3, event_description = $4, event_alert = $5,
+	event_all_day = $6, event_start_date = $7, event_end_date = $8, event_start_tz = $9, event_end_tz = $10
+	 WHERE eventid=$11 RETURNING ` + eventColumns + `;`
+	event, err := scanEvent(tx.QueryRowContext(ctx, query,
+		e.Name, start, end, e.Description, e.Alert, e.AllDay, startDate, endDate, e.StartTZ, e.EndTZ, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			message := "event with id [" + fmt.Sprint(id) + "] does not exist"
 			return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
 		}
-		return event, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		return event, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
 
 	}
-	//these are necessary due to sql Scan function returning time with +0000 zone rather than UTC
-	//call to UTC() does not change actual values, but lets go compiler compare zero time values better
-	event.Start = event.Start.UTC()
-	event.End = event.End.UTC()
-	event.Alert = event.Alert.UTC()
+	if err = saveReminders(ctx, tx, id, e.Reminders); err != nil {
+		return structs.Event{}, contextError(ctx, err)
+	}
+	e.Id = id
+	if err = saveResources(ctx, tx, e); err != nil {
+		return structs.Event{}, contextError(ctx, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return structs.Event{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	event.Reminders = structs.CopyReminders(e.Reminders)
+	event.Resources = structs.CopyResources(e.Resources)
 	return event, nil
 }
-
-func (db *EventsDBRepository) Delete(e structs.Event) error {
+func (db *EventsDBRepository) Delete(ctx context.Context, e structs.Event) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
 	query := `DELETE FROM events WHERE eventid = $1;`
-	_, err := db.Conn.Exec(query, e.Id)
+	_, err := db.Conn.ExecContext(ctx, query, e.Id)
 	switch err {
 	case sql.ErrNoRows:
 		message := "event with id [" + fmt.Sprint(e.Id) + "] does not exist"
 		return fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
 	default:
 		if err != nil {
-			return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+			return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
 		}
 		return nil
 	}
@@ -318,30 +421,17 @@ func (m *ArrayRepository) ClearRepoData() error {
 	return nil
 }
 
-func (a *ArrayRepository) Get(p structs.EventParams) ([]structs.Event, error) {
+func (a *ArrayRepository) Get(ctx context.Context, p structs.EventParams) ([]structs.Event, error) {
 	var matchedEvents []structs.Event
 	for _, event := range a.ArrayRepo {
-		_, weekI := event.Start.ISOWeek()
-		if event.Start.Day() == p.Day || p.Day == 0 {
-			matchedEvents = append(matchedEvents, *event)
-		} else if p.Month == 0 || event.Start.Month() == time.Month(p.Month) {
-			matchedEvents = append(matchedEvents, *event)
-		} else if p.Year == 0 || event.Start.Year() == p.Year {
-			matchedEvents = append(matchedEvents, *event)
-		} else if p.Week == 0 || weekI == p.Week {
-			matchedEvents = append(matchedEvents, *event)
-		} else if p.Name == "" || strings.ToLower(event.Name) == strings.ToLower(p.Name) {
-			matchedEvents = append(matchedEvents, *event)
-		} else if p.Start == (time.Time{}) || event.Start == p.Start {
-			matchedEvents = append(matchedEvents, *event)
-		} else if p.End == (time.Time{}) || event.End == p.End {
+		if structs.SuitsParams(p, *event) {
 			matchedEvents = append(matchedEvents, *event)
 		}
 	}
 	return matchedEvents, nil
 }
 
-func (a *ArrayRepository) Update(id int, newEvent structs.Event) (updated structs.Event, err error) {
+func (a *ArrayRepository) Update(ctx context.Context, id int, newEvent structs.Event) (updated structs.Event, err error) {
 	var foundEvent *structs.Event
 	for _, event := range a.ArrayRepo {
 		if event.Id == id {
@@ -352,15 +442,25 @@ func (a *ArrayRepository) Update(id int, newEvent structs.Event) (updated struct
 		message := "event with id [" + fmt.Sprint(id) + "] does not exist"
 		return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
 	}
+	booking := newEvent
+	booking.Id = id
+	if err := checkBookings(booking, a.events()); err != nil {
+		return structs.Event{}, err
+	}
 	foundEvent.Name = newEvent.Name
 	foundEvent.Start = newEvent.Start
 	foundEvent.End = newEvent.End
 	foundEvent.Alert = newEvent.Alert
 	foundEvent.Description = newEvent.Description
+	foundEvent.AllDay = newEvent.AllDay
+	foundEvent.StartTZ = newEvent.StartTZ
+	foundEvent.EndTZ = newEvent.EndTZ
+	foundEvent.Reminders = structs.CopyReminders(newEvent.Reminders)
+	foundEvent.Resources = structs.CopyResources(newEvent.Resources)
 	return *foundEvent, nil
 }
 
-func (a *ArrayRepository) GetByID(id int) (structs.Event, error) {
+func (a *ArrayRepository) GetByID(ctx context.Context, id int) (structs.Event, error) {
 	for _, event := range a.ArrayRepo {
 		if event.Id == id {
 			return *event, nil
@@ -370,14 +470,19 @@ func (a *ArrayRepository) GetByID(id int) (structs.Event, error) {
 	return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
 }
 
-func (a *ArrayRepository) Add(e structs.Event) (structs.Event, error) {
+func (a *ArrayRepository) Add(ctx context.Context, e structs.Event) (structs.Event, error) {
+	if err := checkBookings(e, a.events()); err != nil {
+		return structs.Event{}, err
+	}
 	e.Id = a.ArrayId
 	a.ArrayId++
+	e.Reminders = structs.CopyReminders(e.Reminders)
+	e.Resources = structs.CopyResources(e.Resources)
 	a.ArrayRepo = append(a.ArrayRepo, &e)
 	return e, nil
 }
 
-func (a *ArrayRepository) Delete(e structs.Event) error {
+func (a *ArrayRepository) Delete(ctx context.Context, e structs.Event) error {
 	for i, event := range a.ArrayRepo {
 		if event.Id == e.Id {
 			a.ArrayRepo = append(a.ArrayRepo[:i], a.ArrayRepo[i+1:]...)
@@ -392,10 +497,19 @@ func (a *ArrayRepository) GetLastUsedId() int {
 	return a.ArrayId - 1
 }
 
+func (a *ArrayRepository) events() []structs.Event {
+	events := make([]structs.Event, 0, len(a.ArrayRepo))
+	for _, event := range a.ArrayRepo {
+		events = append(events, *event)
+	}
+	return events
+}
+
 // Implementation of Repository based on map.
 type MapRepository struct {
 	MapRepo map[int]structs.Event
 	MapId   int
+	mu      sync.RWMutex
 }
 
 func NewMapRepository() (*MapRepository, error) {
@@ -408,39 +522,30 @@ func NewMapRepository() (*MapRepository, error) {
 }
 
 func (m *MapRepository) ClearRepoData() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	for k := range m.MapRepo {
 		delete(m.MapRepo, k)
 	}
 	return nil
 }
 
-func (m *MapRepository) Get(p structs.EventParams) ([]structs.Event, error) {
+func (m *MapRepository) Get(ctx context.Context, p structs.EventParams) ([]structs.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	var matchedEvents []structs.Event
 
 	for _, event := range m.MapRepo {
-		_, weekI := event.Start.ISOWeek()
-
-		if event.Start.Day() == p.Day || p.Day == 0 {
-			matchedEvents = append(matchedEvents, event)
-		} else if p.Month == 0 || event.Start.Month() == time.Month(p.Month) {
-			matchedEvents = append(matchedEvents, event)
-		} else if p.Year == 0 || event.Start.Year() == p.Year {
-			matchedEvents = append(matchedEvents, event)
-		} else if p.Week == 0 || weekI == p.Week {
-			matchedEvents = append(matchedEvents, event)
-		} else if p.Name == "" || strings.ToLower(event.Name) == strings.ToLower(p.Name) {
-			matchedEvents = append(matchedEvents, event)
-		} else if p.Start == (time.Time{}) || event.Start == p.Start {
-			matchedEvents = append(matchedEvents, event)
-		} else if p.End == (time.Time{}) || event.End == p.End {
+		if structs.SuitsParams(p, event) {
 			matchedEvents = append(matchedEvents, event)
 		}
-
 	}
 	return matchedEvents, nil
 }
 
-func (m *MapRepository) GetByID(id int) (structs.Event, error) {
+func (m *MapRepository) GetByID(ctx context.Context, id int) (structs.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	foundEvent, ok := m.MapRepo[id]
 	if !ok {
 		message := "event with id [" + fmt.Sprint(id) + "] does not exist"
@@ -449,7 +554,9 @@ func (m *MapRepository) GetByID(id int) (structs.Event, error) {
 	return foundEvent, nil
 }
 
-func (m *MapRepository) Update(id int, newEvent structs.Event) (updated structs.Event, err error) {
+func (m *MapRepository) Update(ctx context.Context, id int, newEvent structs.Event) (updated structs.Event, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	foundEvent, ok := m.MapRepo[id]
 	if !ok {
 		message := "event with id [" + fmt.Sprint(id) + "] does not exist"
@@ -461,59 +568,95 @@ func (m *MapRepository) Update(id int, newEvent structs.Event) (updated structs.
 	foundEvent.End = newEvent.End
 	foundEvent.Alert = newEvent.Alert
 	foundEvent.Description = newEvent.Description
+	foundEvent.AllDay = newEvent.AllDay
+	foundEvent.StartTZ = newEvent.StartTZ
+	foundEvent.EndTZ = newEvent.EndTZ
+	foundEvent.Reminders = structs.CopyReminders(newEvent.Reminders)
+	foundEvent.Resources = structs.CopyResources(newEvent.Resources)
+	if err := checkBookings(foundEvent, m.events()); err != nil {
+		return structs.Event{}, err
+	}
 
 	m.MapRepo[id] = foundEvent
 
 	return foundEvent, nil
 }
 
-func (m *MapRepository) Add(e structs.Event) (structs.Event, error) {
+func (m *MapRepository) Add(ctx context.Context, e structs.Event) (structs.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := checkBookings(e, m.events()); err != nil {
+		return structs.Event{}, err
+	}
 	e.Id = m.MapId
 	m.MapId++
+	e.Reminders = structs.CopyReminders(e.Reminders)
+	e.Resources = structs.CopyResources(e.Resources)
 
 	m.MapRepo[e.Id] = e
 	return e, nil
 }
 
-func (m *MapRepository) Delete(e structs.Event) error {
+func (m *MapRepository) Delete(ctx context.Context, e structs.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	delete(m.MapRepo, e.Id)
 	return nil
 }
 
 func (m *MapRepository) GetLastUsedId() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.MapId - 1
 }
 
+// events returns the stored events, the caller holds the lock.
+func (m *MapRepository) events() []structs.Event {
+	events := make([]structs.Event, 0, len(m.MapRepo))
+	for _, event := range m.MapRepo {
+		events = append(events, event)
+	}
+	return events
+}
+
 type UsersRepository struct {
-	Users map[string]structs.HashedInfo
+	Users        map[string]structs.HashedInfo
+	Availability map[string]structs.Availability
+	mu           sync.RWMutex
 }
 
 func (u *UsersRepository) ClearRepoData() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
 	for k := range u.Users {
 		delete(u.Users, k)
 	}
+	u.Availability = make(map[string]structs.Availability)
 	return nil
 }
 
 func NewUsersInMemoryRepository() (*UsersRepository, error) {
 	users := make(map[string]structs.HashedInfo)
 	repo := &UsersRepository{
-		Users: users,
+		Users:        users,
+		Availability: make(map[string]structs.Availability),
 	}
 	return repo, nil
 }
 
-func (u *UsersRepository) AddUser(e structs.CreateUser) (structs.HashedInfo, error) {
+func (u *UsersRepository) AddUser(ctx context.Context, e structs.CreateUser) (structs.HashedInfo, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
 	_, ok := u.Users[e.Username]
 	if ok {
 		message := "user with username [" + e.Username + "] already exists"
 		return structs.HashedInfo{}, fmt.Errorf("%w : %v ", structs.ErrDublicate, message)
 	}
-	generatedHash, err := generate(e.Password)
+	generatedHash, err := hashPassword(e.Password)
 	if err != nil {
 		return structs.HashedInfo{}, err
 	}
-	loc, err := time.LoadLocation(e.Location)
+	loc, err := loadLocation(e.Location)
 	if err != nil {
 		return structs.HashedInfo{}, err
 	}
@@ -528,7 +671,9 @@ func (u *UsersRepository) AddUser(e structs.CreateUser) (structs.HashedInfo, err
 	return hashedData, nil
 }
 
-func (u *UsersRepository) GetUser(username string) (structs.HashedInfo, error) {
+func (u *UsersRepository) GetUser(ctx context.Context, username string) (structs.HashedInfo, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
 	_, ok := u.Users[username]
 	if !ok {
 		message := "user with username [" + username + "] does not exist"
@@ -537,7 +682,9 @@ func (u *UsersRepository) GetUser(username string) (structs.HashedInfo, error) {
 	return u.Users[username], nil
 }
 
-func (u *UsersRepository) UpdateLocation(user string, loc time.Location) (structs.HashedInfo, error) {
+func (u *UsersRepository) UpdateLocation(ctx context.Context, user string, loc time.Location) (structs.HashedInfo, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
 	found, ok := u.Users[user]
 	if !ok {
 		message := "user with username [" + user + "] does not exist"
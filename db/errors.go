@@ -0,0 +1,65 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/lib/pq"
+)
+
+// Postgres error codes the repositories translate, the full list is in
+// the "PostgreSQL Error Codes" appendix of the documentation.
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+	pqExclusionViolation  = "23P01"
+	pqUndefinedTable      = "42P01"
+)
+
+func isUniqueViolation(err error) bool {
+	return hasCode(err, pqUniqueViolation)
+}
+
+func isForeignKeyViolation(err error) bool {
+	return hasCode(err, pqForeignKeyViolation)
+}
+
+func isExclusionViolation(err error) bool {
+	return hasCode(err, pqExclusionViolation)
+}
+
+func hasCode(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
+
+// loadLocation resolves a location given by the client, unknown names are validation errors.
+func loadLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, structs.NewValidationError("unknown location ["+name+"]",
+			structs.FieldError{Field: "location", Message: err.Error()})
+	}
+	return loc, nil
+}
+
+// storedLocation resolves a location read back from storage, failing to do so is an internal error.
+func storedLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w : stored location [%v] : %v", structs.ErrSql, name, err.Error())
+	}
+	return loc, nil
+}
+
+// hashPassword hashes the password of a new user, bcrypt refuses passwords longer than 72 bytes.
+func hashPassword(password string) (string, error) {
+	hash, err := generate(password)
+	if err != nil {
+		return "", structs.NewValidationError("password can not be used",
+			structs.FieldError{Field: "password", Message: err.Error()})
+	}
+	return hash, nil
+}
@@ -0,0 +1,897 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// Implementation of Repository that keeps events in memory
+// and rewrites a JSON file after every change.
+type EventsFileRepository struct {
+	*MapRepository
+	path string
+	mu   sync.Mutex
+}
+
+type eventsFile struct {
+	NextId int             `json:"next_id"`
+	Events []structs.Event `json:"events"`
+	// owners are kept apart, events do not send them in their JSON form
+	Owners map[int]string `json:"owners,omitempty"`
+}
+
+func NewEventsFileRepository(path string) (*EventsFileRepository, error) {
+	mapRepo, err := NewMapRepository()
+	if err != nil {
+		return nil, err
+	}
+	repo := &EventsFileRepository{MapRepository: mapRepo, path: path}
+
+	var stored eventsFile
+	found, err := readJSONFile(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, e := range stored.Events {
+			e.Owner = stored.Owners[e.Id]
+			mapRepo.MapRepo[e.Id] = e
+		}
+		if stored.NextId > mapRepo.MapId {
+			mapRepo.MapId = stored.NextId
+		}
+	}
+	return repo, nil
+}
+
+// snapshot copies the events in memory and returns a func putting them back.
+func (f *EventsFileRepository) snapshot() func() {
+	m := f.MapRepository
+	m.mu.RLock()
+	events, id := make(map[int]structs.Event, len(m.MapRepo)), m.MapId
+	for k, e := range m.MapRepo {
+		events[k] = e
+	}
+	m.mu.RUnlock()
+	return func() {
+		m.mu.Lock()
+		m.MapRepo, m.MapId = events, id
+		m.mu.Unlock()
+	}
+}
+
+func (f *EventsFileRepository) save() error {
+	f.MapRepository.mu.RLock()
+	stored := eventsFile{NextId: f.MapRepository.MapId, Owners: map[int]string{}}
+	for _, e := range f.MapRepository.MapRepo {
+		stored.Events = append(stored.Events, e)
+		if e.Owner != "" {
+			stored.Owners[e.Id] = e.Owner
+		}
+	}
+	f.MapRepository.mu.RUnlock()
+	return writeJSONFile(f.path, stored)
+}
+
+func (f *EventsFileRepository) Add(ctx context.Context, e structs.Event) (structs.Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	added, err := f.MapRepository.Add(ctx, e)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.Event{}, err
+	}
+	return added, nil
+}
+
+func (f *EventsFileRepository) Update(ctx context.Context, id int, newEvent structs.Event) (updated structs.Event, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	updated, err = f.MapRepository.Update(ctx, id, newEvent)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.Event{}, err
+	}
+	return updated, nil
+}
+
+func (f *EventsFileRepository) Delete(ctx context.Context, e structs.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.MapRepository.Delete(ctx, e); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+func (f *EventsFileRepository) ClearRepoData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.MapRepository.ClearRepoData(); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+// Implementation of UserRepository that keeps users in memory
+// and rewrites a JSON file after every change.
+type UsersFileRepository struct {
+	*UsersRepository
+	path string
+	mu   sync.Mutex
+}
+
+// fileUser is the stored form of structs.HashedInfo, which can not be
+// encoded directly because of its time.Location field.
+type fileUser struct {
+	Username     string                `json:"username"`
+	HashedPass   string                `json:"hashed_pass"`
+	Location     string                `json:"location"`
+	Availability *structs.Availability `json:"availability,omitempty"`
+}
+
+func NewUsersFileRepository(path string) (*UsersFileRepository, error) {
+	usersRepo, err := NewUsersInMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+	repo := &UsersFileRepository{UsersRepository: usersRepo, path: path}
+
+	var stored []fileUser
+	found, err := readJSONFile(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, u := range stored {
+			loc, err := time.LoadLocation(u.Location)
+			if err != nil {
+				return nil, fmt.Errorf("%w : user [%v] has invalid location : %v", structs.ErrSql, u.Username, err.Error())
+			}
+			usersRepo.Users[u.Username] = structs.HashedInfo{
+				Username:   u.Username,
+				Location:   *loc,
+				HashedPass: u.HashedPass,
+			}
+			if u.Availability != nil {
+				usersRepo.Availability[u.Username] = *u.Availability
+			}
+		}
+	}
+	return repo, nil
+}
+
+// snapshot copies the users in memory and returns a func putting them back.
+func (f *UsersFileRepository) snapshot() func() {
+	u := f.UsersRepository
+	u.mu.RLock()
+	users, availability := make(map[string]structs.HashedInfo, len(u.Users)), make(map[string]structs.Availability, len(u.Availability))
+	for k, user := range u.Users {
+		users[k] = user
+	}
+	for k, a := range u.Availability {
+		availability[k] = a
+	}
+	u.mu.RUnlock()
+	return func() {
+		u.mu.Lock()
+		u.Users, u.Availability = users, availability
+		u.mu.Unlock()
+	}
+}
+
+func (f *UsersFileRepository) save() error {
+	f.UsersRepository.mu.RLock()
+	stored := make([]fileUser, 0, len(f.UsersRepository.Users))
+	for _, u := range f.UsersRepository.Users {
+		user := fileUser{
+			Username:   u.Username,
+			HashedPass: u.HashedPass,
+			Location:   u.Location.String(),
+		}
+		if a, ok := f.UsersRepository.Availability[u.Username]; ok {
+			user.Availability = &a
+		}
+		stored = append(stored, user)
+	}
+	f.UsersRepository.mu.RUnlock()
+	return writeJSONFile(f.path, stored)
+}
+
+func (f *UsersFileRepository) AddUser(ctx context.Context, e structs.CreateUser) (structs.HashedInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	added, err := f.UsersRepository.AddUser(ctx, e)
+	if err != nil {
+		return structs.HashedInfo{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.HashedInfo{}, err
+	}
+	return added, nil
+}
+
+func (f *UsersFileRepository) UpdateLocation(ctx context.Context, user string, loc time.Location) (structs.HashedInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	updated, err := f.UsersRepository.UpdateLocation(ctx, user, loc)
+	if err != nil {
+		return structs.HashedInfo{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.HashedInfo{}, err
+	}
+	return updated, nil
+}
+
+func (f *UsersFileRepository) UpdateAvailability(ctx context.Context, user string, a structs.Availability) (structs.Availability, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	updated, err := f.UsersRepository.UpdateAvailability(ctx, user, a)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.Availability{}, err
+	}
+	return updated, nil
+}
+
+func (f *UsersFileRepository) ClearRepoData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.UsersRepository.ClearRepoData(); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+// Implementation of AlertsRepository that keeps alerts in memory
+// and rewrites a JSON file after every change.
+type AlertsFileRepository struct {
+	*AlertsInMemoryRepository
+	path string
+	mu   sync.Mutex
+}
+
+type alertsFile struct {
+	NextId int                     `json:"next_id"`
+	Alerts []structs.AlertInstance `json:"alerts"`
+	Owners map[int]string          `json:"owners,omitempty"`
+}
+
+func NewAlertsFileRepository(path string) (*AlertsFileRepository, error) {
+	memoryRepo, err := NewAlertsInMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+	repo := &AlertsFileRepository{AlertsInMemoryRepository: memoryRepo, path: path}
+
+	var stored alertsFile
+	found, err := readJSONFile(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, a := range stored.Alerts {
+			a.Owner = stored.Owners[a.Id]
+			memoryRepo.Alerts[a.Id] = a
+		}
+		if stored.NextId > memoryRepo.NextId {
+			memoryRepo.NextId = stored.NextId
+		}
+	}
+	return repo, nil
+}
+
+// snapshot copies the alerts in memory and returns a func putting them back.
+func (f *AlertsFileRepository) snapshot() func() {
+	m := f.AlertsInMemoryRepository
+	m.mu.RLock()
+	alerts, id := make(map[int]structs.AlertInstance, len(m.Alerts)), m.NextId
+	for k, a := range m.Alerts {
+		alerts[k] = a
+	}
+	m.mu.RUnlock()
+	return func() {
+		m.mu.Lock()
+		m.Alerts, m.NextId = alerts, id
+		m.mu.Unlock()
+	}
+}
+
+func (f *AlertsFileRepository) save() error {
+	f.AlertsInMemoryRepository.mu.RLock()
+	stored := alertsFile{NextId: f.AlertsInMemoryRepository.NextId, Owners: map[int]string{}}
+	for _, a := range f.AlertsInMemoryRepository.Alerts {
+		stored.Alerts = append(stored.Alerts, a)
+		if a.Owner != "" {
+			stored.Owners[a.Id] = a.Owner
+		}
+	}
+	f.AlertsInMemoryRepository.mu.RUnlock()
+	return writeJSONFile(f.path, stored)
+}
+
+func (f *AlertsFileRepository) Schedule(ctx context.Context, a structs.AlertInstance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.AlertsInMemoryRepository.Schedule(ctx, a); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+func (f *AlertsFileRepository) Cancel(ctx context.Context, eventId int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.AlertsInMemoryRepository.Cancel(ctx, eventId); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+func (f *AlertsFileRepository) CancelTask(ctx context.Context, taskId int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.AlertsInMemoryRepository.CancelTask(ctx, taskId); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+func (f *AlertsFileRepository) Deliver(ctx context.Context, now time.Time) ([]structs.AlertInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	delivered, err := f.AlertsInMemoryRepository.Deliver(ctx, now)
+	if err != nil || len(delivered) == 0 {
+		return delivered, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return nil, err
+	}
+	return delivered, nil
+}
+
+func (f *AlertsFileRepository) Update(ctx context.Context, a structs.AlertInstance, previous structs.AlertState) (structs.AlertInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	updated, err := f.AlertsInMemoryRepository.Update(ctx, a, previous)
+	if err != nil {
+		return structs.AlertInstance{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.AlertInstance{}, err
+	}
+	return updated, nil
+}
+
+func (f *AlertsFileRepository) ClearRepoData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.AlertsInMemoryRepository.ClearRepoData(); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+// Implementation of ResourcesRepository that keeps resources in memory
+// and rewrites a JSON file after every change.
+type ResourcesFileRepository struct {
+	*ResourcesInMemoryRepository
+	path string
+	mu   sync.Mutex
+}
+
+type resourcesFile struct {
+	NextId    int                `json:"next_id"`
+	Resources []structs.Resource `json:"resources"`
+}
+
+func NewResourcesFileRepository(path string) (*ResourcesFileRepository, error) {
+	memoryRepo, err := NewResourcesInMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+	repo := &ResourcesFileRepository{ResourcesInMemoryRepository: memoryRepo, path: path}
+
+	var stored resourcesFile
+	found, err := readJSONFile(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, r := range stored.Resources {
+			memoryRepo.Resources[r.Id] = r
+		}
+		if stored.NextId > memoryRepo.NextId {
+			memoryRepo.NextId = stored.NextId
+		}
+	}
+	return repo, nil
+}
+
+// snapshot copies the resources in memory and returns a func putting them back.
+func (f *ResourcesFileRepository) snapshot() func() {
+	m := f.ResourcesInMemoryRepository
+	m.mu.RLock()
+	resources, id := make(map[int]structs.Resource, len(m.Resources)), m.NextId
+	for k, r := range m.Resources {
+		resources[k] = r
+	}
+	m.mu.RUnlock()
+	return func() {
+		m.mu.Lock()
+		m.Resources, m.NextId = resources, id
+		m.mu.Unlock()
+	}
+}
+
+func (f *ResourcesFileRepository) save() error {
+	resources, _ := f.ResourcesInMemoryRepository.Get(context.Background())
+	f.ResourcesInMemoryRepository.mu.RLock()
+	stored := resourcesFile{NextId: f.ResourcesInMemoryRepository.NextId, Resources: resources}
+	f.ResourcesInMemoryRepository.mu.RUnlock()
+	return writeJSONFile(f.path, stored)
+}
+
+func (f *ResourcesFileRepository) Add(ctx context.Context, r structs.Resource) (structs.Resource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	added, err := f.ResourcesInMemoryRepository.Add(ctx, r)
+	if err != nil {
+		return structs.Resource{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.Resource{}, err
+	}
+	return added, nil
+}
+
+func (f *ResourcesFileRepository) ClearRepoData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.ResourcesInMemoryRepository.ClearRepoData(); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+// Implementation of BookingRepository that keeps pages and bookings in memory
+// and rewrites a JSON file after every change, events are kept by the events repository.
+type BookingFileRepository struct {
+	*BookingInMemoryRepository
+	path string
+	mu   sync.Mutex
+}
+
+type bookingFile struct {
+	Pages    []structs.BookingPage `json:"pages"`
+	Owners   map[string]string     `json:"owners"`
+	Bookings []structs.Booking     `json:"bookings"`
+}
+
+func NewBookingFileRepository(path string, events EventsRepository) (*BookingFileRepository, error) {
+	memoryRepo, err := NewBookingInMemoryRepository(events)
+	if err != nil {
+		return nil, err
+	}
+	repo := &BookingFileRepository{BookingInMemoryRepository: memoryRepo, path: path}
+
+	var stored bookingFile
+	found, err := readJSONFile(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, p := range stored.Pages {
+			p.Owner = stored.Owners[p.Slug]
+			memoryRepo.Pages[p.Slug] = p
+		}
+		for _, b := range stored.Bookings {
+			memoryRepo.Bookings[b.CancelToken] = b
+		}
+	}
+	return repo, nil
+}
+
+// snapshot copies the pages and bookings in memory and returns a func putting them back.
+func (f *BookingFileRepository) snapshot() func() {
+	m := f.BookingInMemoryRepository
+	m.mu.Lock()
+	pages, bookings := make(map[string]structs.BookingPage, len(m.Pages)), make(map[string]structs.Booking, len(m.Bookings))
+	for k, p := range m.Pages {
+		pages[k] = p
+	}
+	for k, b := range m.Bookings {
+		bookings[k] = b
+	}
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		m.Pages, m.Bookings = pages, bookings
+		m.mu.Unlock()
+	}
+}
+
+func (f *BookingFileRepository) save() error {
+	f.BookingInMemoryRepository.mu.Lock()
+	stored := bookingFile{Owners: map[string]string{}}
+	for _, p := range f.BookingInMemoryRepository.Pages {
+		stored.Pages = append(stored.Pages, p)
+		stored.Owners[p.Slug] = p.Owner
+	}
+	for _, b := range f.BookingInMemoryRepository.Bookings {
+		stored.Bookings = append(stored.Bookings, b)
+	}
+	f.BookingInMemoryRepository.mu.Unlock()
+	return writeJSONFile(f.path, stored)
+}
+
+func (f *BookingFileRepository) SavePage(ctx context.Context, p structs.BookingPage) (structs.BookingPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	saved, err := f.BookingInMemoryRepository.SavePage(ctx, p)
+	if err != nil {
+		return structs.BookingPage{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.BookingPage{}, err
+	}
+	return saved, nil
+}
+
+func (f *BookingFileRepository) DeletePage(ctx context.Context, owner string, slug string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.BookingInMemoryRepository.DeletePage(ctx, owner, slug); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+func (f *BookingFileRepository) Book(ctx context.Context, window structs.Interval, b structs.Booking, e structs.Event,
+	check func([]structs.Event, []structs.Booking) error) (structs.Booking, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	booked, err := f.BookingInMemoryRepository.Book(ctx, window, b, e, check)
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		// the event of the booking is kept by the events repository, which wrote it already
+		f.Events.Delete(ctx, structs.Event{Id: booked.EventId})
+		return structs.Booking{}, err
+	}
+	return booked, nil
+}
+
+func (f *BookingFileRepository) Cancel(ctx context.Context, slug string, token string) (structs.Booking, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// the booking leaves the file before its event is deleted, so that a booking
+	// never points at an event that is gone
+	rollback := f.snapshot()
+	m := f.BookingInMemoryRepository
+	m.mu.Lock()
+	cancelled, ok := m.Bookings[token]
+	if ok && cancelled.Slug == slug {
+		delete(m.Bookings, token)
+	}
+	m.mu.Unlock()
+	if !ok || cancelled.Slug != slug {
+		return structs.Booking{}, bookingNotFound()
+	}
+	if err := commit(f.save, rollback); err != nil {
+		return structs.Booking{}, err
+	}
+	if err := m.Events.Delete(ctx, structs.Event{Id: cancelled.EventId}); err != nil {
+		return structs.Booking{}, err
+	}
+	return cancelled, nil
+}
+
+func (f *BookingFileRepository) ClearRepoData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.BookingInMemoryRepository.ClearRepoData(); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+// commit writes a change to its file with save, or undoes it in memory with rollback
+// when the file can not be written, so that memory never keeps what the file lost.
+func commit(save func() error, rollback func()) error {
+	if err := save(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
+
+func readJSONFile(path string, dest interface{}) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w : reading %v : %v", structs.ErrSql, path, err.Error())
+	}
+	if err = json.Unmarshal(data, dest); err != nil {
+		return false, fmt.Errorf("%w : decoding %v : %v", structs.ErrSql, path, err.Error())
+	}
+	return true, nil
+}
+
+// writeJSONFile replaces the file in one rename so that a crash
+// never leaves a half written file behind.
+func writeJSONFile(path string, data interface{}) error {
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w : encoding %v : %v", structs.ErrSql, path, err.Error())
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("%w : %v", structs.ErrSql, err.Error())
+	}
+	tmp := path + ".tmp"
+	if err = ioutil.WriteFile(tmp, encoded, 0644); err != nil {
+		return fmt.Errorf("%w : writing %v : %v", structs.ErrSql, path, err.Error())
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("%w : writing %v : %v", structs.ErrSql, path, err.Error())
+	}
+	return nil
+}
+
+// Implementation of TasksRepository that keeps tasks in memory
+// and rewrites a JSON file after every change.
+type TasksFileRepository struct {
+	*TasksInMemoryRepository
+	path string
+	mu   sync.Mutex
+}
+
+type tasksFile struct {
+	NextId int            `json:"next_id"`
+	Tasks  []structs.Task `json:"tasks"`
+	Owners map[int]string `json:"owners,omitempty"`
+}
+
+func NewTasksFileRepository(path string) (*TasksFileRepository, error) {
+	memoryRepo, err := NewTasksInMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+	repo := &TasksFileRepository{TasksInMemoryRepository: memoryRepo, path: path}
+
+	var stored tasksFile
+	found, err := readJSONFile(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, t := range stored.Tasks {
+			t.Owner = stored.Owners[t.Id]
+			memoryRepo.Tasks[t.Id] = t
+		}
+		if stored.NextId > memoryRepo.NextId {
+			memoryRepo.NextId = stored.NextId
+		}
+	}
+	return repo, nil
+}
+
+// snapshot copies the tasks in memory and returns a func putting them back.
+func (f *TasksFileRepository) snapshot() func() {
+	m := f.TasksInMemoryRepository
+	m.mu.RLock()
+	tasks, id := make(map[int]structs.Task, len(m.Tasks)), m.NextId
+	for k, t := range m.Tasks {
+		tasks[k] = t
+	}
+	m.mu.RUnlock()
+	return func() {
+		m.mu.Lock()
+		m.Tasks, m.NextId = tasks, id
+		m.mu.Unlock()
+	}
+}
+
+func (f *TasksFileRepository) save() error {
+	tasks, _ := f.TasksInMemoryRepository.Get(context.Background(), structs.TaskParams{})
+	f.TasksInMemoryRepository.mu.RLock()
+	stored := tasksFile{NextId: f.TasksInMemoryRepository.NextId, Tasks: tasks, Owners: map[int]string{}}
+	f.TasksInMemoryRepository.mu.RUnlock()
+	for _, t := range tasks {
+		stored.Owners[t.Id] = t.Owner
+	}
+	return writeJSONFile(f.path, stored)
+}
+
+func (f *TasksFileRepository) Add(ctx context.Context, t structs.Task) (structs.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	added, err := f.TasksInMemoryRepository.Add(ctx, t)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.Task{}, err
+	}
+	return added, nil
+}
+
+func (f *TasksFileRepository) Update(ctx context.Context, t structs.Task) (structs.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	updated, err := f.TasksInMemoryRepository.Update(ctx, t)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return structs.Task{}, err
+	}
+	return updated, nil
+}
+
+func (f *TasksFileRepository) Delete(ctx context.Context, id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.TasksInMemoryRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+func (f *TasksFileRepository) ClearRepoData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.TasksInMemoryRepository.ClearRepoData(); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+// Implementation of IdempotencyRepository that keeps keys in memory and rewrites
+// a JSON file whenever a response is stored or keys expire. Reservations are not
+// written, the requests holding them do not outlive the process.
+type IdempotencyFileRepository struct {
+	*IdempotencyInMemoryRepository
+	path string
+	mu   sync.Mutex
+}
+
+type idempotencyFile struct {
+	Records []structs.IdempotencyRecord `json:"records"`
+}
+
+func NewIdempotencyFileRepository(path string) (*IdempotencyFileRepository, error) {
+	memoryRepo, err := NewIdempotencyInMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+	repo := &IdempotencyFileRepository{IdempotencyInMemoryRepository: memoryRepo, path: path}
+
+	var stored idempotencyFile
+	found, err := readJSONFile(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, r := range stored.Records {
+			if r.Completed() {
+				memoryRepo.Records[r.Key] = r
+			}
+		}
+	}
+	return repo, nil
+}
+
+// snapshot copies the stored responses in memory and returns a func putting them back.
+// Reservations are left as they are, they are made and released without the file.
+func (f *IdempotencyFileRepository) snapshot() func() {
+	m := f.IdempotencyInMemoryRepository
+	m.mu.Lock()
+	completed := make(map[string]structs.IdempotencyRecord)
+	for k, r := range m.Records {
+		if r.Completed() {
+			completed[k] = r
+		}
+	}
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		for k, r := range m.Records {
+			if r.Completed() {
+				delete(m.Records, k)
+			}
+		}
+		for k, r := range completed {
+			m.Records[k] = r
+		}
+		m.mu.Unlock()
+	}
+}
+
+func (f *IdempotencyFileRepository) save() error {
+	f.IdempotencyInMemoryRepository.mu.Lock()
+	stored := idempotencyFile{Records: make([]structs.IdempotencyRecord, 0, len(f.IdempotencyInMemoryRepository.Records))}
+	for _, r := range f.IdempotencyInMemoryRepository.Records {
+		if r.Completed() {
+			stored.Records = append(stored.Records, r)
+		}
+	}
+	f.IdempotencyInMemoryRepository.mu.Unlock()
+	return writeJSONFile(f.path, stored)
+}
+
+func (f *IdempotencyFileRepository) Complete(ctx context.Context, record structs.IdempotencyRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.IdempotencyInMemoryRepository.Complete(ctx, record); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
+
+func (f *IdempotencyFileRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	deleted, err := f.IdempotencyInMemoryRepository.DeleteExpired(ctx, now)
+	if err != nil || deleted == 0 {
+		return deleted, err
+	}
+	if err = commit(f.save, rollback); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (f *IdempotencyFileRepository) ClearRepoData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rollback := f.snapshot()
+	if err := f.IdempotencyInMemoryRepository.ClearRepoData(); err != nil {
+		return err
+	}
+	return commit(f.save, rollback)
+}
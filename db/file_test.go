@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestFileRepositoryRollsBackUnsavedChanges(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data", "events.json")
+	repo, err := NewEventsFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := repo.Add(ctx, structs.Event{Name: "Kept", Start: time.Unix(0, 0), End: time.Unix(3600, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a file in place of the data directory makes every write fail
+	if err = os.RemoveAll(filepath.Dir(path)); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Dir(path), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.Add(ctx, structs.Event{Name: "Lost", Start: time.Unix(0, 0), End: time.Unix(3600, 0)}); err == nil {
+		t.Fatal("expected the add to fail when the file can not be written")
+	}
+	if _, err = repo.Update(ctx, kept.Id, structs.Event{Name: "Renamed", Start: kept.Start, End: kept.End}); err == nil {
+		t.Fatal("expected the update to fail when the file can not be written")
+	}
+	if err = repo.Delete(ctx, kept); err == nil {
+		t.Fatal("expected the delete to fail when the file can not be written")
+	}
+	events, _ := repo.Get(ctx, structs.EventParams{})
+	if len(events) != 1 || events[0].Name != "Kept" {
+		t.Fatalf("expected only the saved event in memory, got %v", events)
+	}
+
+	// once the file can be written again, the failed changes do not reach it
+	if err = os.Remove(filepath.Dir(path)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.Add(ctx, structs.Event{Name: "Later", Start: time.Unix(0, 0), End: time.Unix(3600, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewEventsFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _ = reopened.Get(ctx, structs.EventParams{})
+	if len(events) != 2 {
+		t.Errorf("expected the kept and the later event in the file, got %v", events)
+	}
+	for _, e := range events {
+		if e.Name != "Kept" && e.Name != "Later" {
+			t.Errorf("expected only the kept and the later event in the file, got %v", e.Name)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+type IdempotencyDBRepository struct {
+	Conn         *sql.DB
+	QueryTimeout time.Duration
+}
+
+func NewIdempotencyDBRepository(conn *sql.DB) (*IdempotencyDBRepository, error) {
+	return &IdempotencyDBRepository{Conn: conn}, nil
+}
+
+func (db *IdempotencyDBRepository) Reserve(ctx context.Context, record structs.IdempotencyRecord) (structs.IdempotencyRecord, bool, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	// an expired key is taken over as if it was never used
+	query := `INSERT INTO idempotency_keys (idempotency_key, request_hash, status, header, body, created_at, expires_at)
+	VALUES ($1, $2, 0, '{}', NULL, $3, $4)
+	ON CONFLICT (idempotency_key) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash, status = 0, header = '{}', body = NULL,
+		created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+	RETURNING idempotency_key;`
+	var key string
+	err := db.Conn.QueryRowContext(ctx, query, record.Key, record.RequestHash, record.CreatedAt.UTC(), record.ExpiresAt.UTC()).Scan(&key)
+	if err == nil {
+		return structs.IdempotencyRecord{}, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return structs.IdempotencyRecord{}, false, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	existing, err := db.Get(ctx, record.Key)
+	return existing, false, err
+}
+
+func (db *IdempotencyDBRepository) Get(ctx context.Context, key string) (structs.IdempotencyRecord, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	var record structs.IdempotencyRecord
+	var header []byte
+	query := `SELECT idempotency_key, request_hash, status, header, body, created_at, expires_at
+	FROM idempotency_keys
+	WHERE idempotency_key = $1;`
+	err := db.Conn.QueryRowContext(ctx, query, key).Scan(&record.Key, &record.RequestHash, &record.Status,
+		&header, &record.Body, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return structs.IdempotencyRecord{}, fmt.Errorf("%w : idempotency key does not exist ", structs.ErrNoMatch)
+		}
+		return structs.IdempotencyRecord{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if err = json.Unmarshal(header, &record.Header); err != nil {
+		return structs.IdempotencyRecord{}, fmt.Errorf("%w : decoding the headers of idempotency key : %v ", structs.ErrPostgres, err.Error())
+	}
+	record.CreatedAt = record.CreatedAt.UTC()
+	record.ExpiresAt = record.ExpiresAt.UTC()
+	return record, nil
+}
+
+func (db *IdempotencyDBRepository) Complete(ctx context.Context, record structs.IdempotencyRecord) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	encoded, err := json.Marshal(record.Header)
+	if err != nil {
+		return fmt.Errorf("%w : encoding the headers of idempotency key : %v ", structs.ErrPostgres, err.Error())
+	}
+	query := `UPDATE idempotency_keys SET status = $3, header = $4, body = $5, expires_at = $6
+	WHERE idempotency_key = $1 AND created_at = $2 AND status = 0;`
+	res, err := db.Conn.ExecContext(ctx, query, record.Key, record.CreatedAt.UTC(), record.Status, string(encoded),
+		record.Body, record.ExpiresAt.UTC())
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return reservationNotFound()
+	}
+	return nil
+}
+
+func (db *IdempotencyDBRepository) Release(ctx context.Context, reservation structs.IdempotencyRecord) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	_, err := db.Conn.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND created_at = $2 AND status = 0;`,
+		reservation.Key, reservation.CreatedAt.UTC())
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return nil
+}
+
+func (db *IdempotencyDBRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	res, err := db.Conn.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1;`, now.UTC())
+	if err != nil {
+		return 0, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return res.RowsAffected()
+}
+
+func (db *IdempotencyDBRepository) ClearRepoData() error {
+	_, err := db.Conn.Exec("TRUNCATE idempotency_keys;")
+	if err != nil {
+		return fmt.Errorf("%w : error occured when truncating idempotency_keys table : %v", structs.ErrPostgres, err.Error())
+	}
+	return nil
+}
+
+// IdempotencyInMemoryRepository is used with the memory storage.
+type IdempotencyInMemoryRepository struct {
+	mu      sync.Mutex
+	Records map[string]structs.IdempotencyRecord
+}
+
+func NewIdempotencyInMemoryRepository() (*IdempotencyInMemoryRepository, error) {
+	return &IdempotencyInMemoryRepository{Records: make(map[string]structs.IdempotencyRecord)}, nil
+}
+
+func (m *IdempotencyInMemoryRepository) Reserve(ctx context.Context, record structs.IdempotencyRecord) (structs.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.Records[record.Key]
+	if ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return existing, false, nil
+	}
+	record.Status, record.Header, record.Body = 0, nil, nil
+	m.Records[record.Key] = record
+	return structs.IdempotencyRecord{}, true, nil
+}
+
+func (m *IdempotencyInMemoryRepository) Get(ctx context.Context, key string) (structs.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.Records[key]
+	if !ok {
+		return structs.IdempotencyRecord{}, fmt.Errorf("%w : idempotency key does not exist ", structs.ErrNoMatch)
+	}
+	return record, nil
+}
+
+func (m *IdempotencyInMemoryRepository) Complete(ctx context.Context, completed structs.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.Records[completed.Key]
+	if !ok || record.Completed() || !record.CreatedAt.Equal(completed.CreatedAt) {
+		return reservationNotFound()
+	}
+	record.Status = completed.Status
+	record.Header = make(map[string][]string, len(completed.Header))
+	for name, values := range completed.Header {
+		record.Header[name] = append([]string(nil), values...)
+	}
+	record.Body = append([]byte(nil), completed.Body...)
+	record.ExpiresAt = completed.ExpiresAt
+	m.Records[completed.Key] = record
+	return nil
+}
+
+func (m *IdempotencyInMemoryRepository) Release(ctx context.Context, reservation structs.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.Records[reservation.Key]; ok && !record.Completed() && record.CreatedAt.Equal(reservation.CreatedAt) {
+		delete(m.Records, reservation.Key)
+	}
+	return nil
+}
+
+func (m *IdempotencyInMemoryRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for key, record := range m.Records {
+		if !record.ExpiresAt.After(now) {
+			delete(m.Records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *IdempotencyInMemoryRepository) ClearRepoData() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Records = make(map[string]structs.IdempotencyRecord)
+	return nil
+}
+
+func reservationNotFound() error {
+	return fmt.Errorf("%w : the reservation of the idempotency key expired and was taken over ", structs.ErrNoMatch)
+}
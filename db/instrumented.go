@@ -0,0 +1,327 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/metrics"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// RepositoryMetrics holds the metrics shared by all instrumented repositories.
+type RepositoryMetrics struct {
+	calls    *metrics.CounterVec
+	errors   *metrics.CounterVec
+	duration *metrics.HistogramVec
+}
+
+func NewRepositoryMetrics(registry *metrics.Registry) *RepositoryMetrics {
+	return &RepositoryMetrics{
+		calls: registry.NewCounterVec("repository_calls_total",
+			"Number of repository calls.", "repository", "method"),
+		errors: registry.NewCounterVec("repository_errors_total",
+			"Number of repository calls that failed, missing records, duplicates and invalid input are not counted.", "repository", "method"),
+		duration: registry.NewHistogramVec("repository_call_duration_seconds",
+			"Time spent in repository calls.", metrics.DefaultBuckets, "repository", "method"),
+	}
+}
+
+// observe records a single call, it is meant to be deferred right before the call is made.
+// Failed calls are also logged with the fields of the request context.
+func (m *RepositoryMetrics) observe(ctx context.Context, repository string, method string, started time.Time, err *error) {
+	elapsed := time.Since(started)
+	m.calls.Inc(repository, method)
+	m.duration.Observe(elapsed.Seconds(), repository, method)
+	if *err == nil {
+		return
+	}
+	entry := logger.FromContext(ctx).WithFields(logger.Fields{
+		"repository": repository,
+		"method":     method,
+		"latency_ms": float64(elapsed.Microseconds()) / 1000,
+	})
+	switch structs.KindOf(*err) {
+	case structs.KindNotFound, structs.KindConflict, structs.KindValidation:
+		entry.Debugf("repository call failed : %v", *err)
+		return
+	}
+	m.errors.Inc(repository, method)
+	entry.Errorf("repository call failed : %v", *err)
+}
+
+// InstrumentedEventsRepository decorates an EventsRepository with call timings and error counts.
+type InstrumentedEventsRepository struct {
+	EventsRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedEventsRepository(repo EventsRepository, m *RepositoryMetrics) *InstrumentedEventsRepository {
+	return &InstrumentedEventsRepository{EventsRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedEventsRepository) Add(ctx context.Context, e structs.Event) (added structs.Event, err error) {
+	defer i.metrics.observe(ctx, "events", "Add", time.Now(), &err)
+	return i.EventsRepository.Add(ctx, e)
+}
+
+func (i *InstrumentedEventsRepository) Get(ctx context.Context, p structs.EventParams) (events []structs.Event, err error) {
+	defer i.metrics.observe(ctx, "events", "Get", time.Now(), &err)
+	return i.EventsRepository.Get(ctx, p)
+}
+
+func (i *InstrumentedEventsRepository) GetByID(ctx context.Context, id int) (event structs.Event, err error) {
+	defer i.metrics.observe(ctx, "events", "GetByID", time.Now(), &err)
+	return i.EventsRepository.GetByID(ctx, id)
+}
+
+func (i *InstrumentedEventsRepository) Update(ctx context.Context, id int, newEvent structs.Event) (updated structs.Event, err error) {
+	defer i.metrics.observe(ctx, "events", "Update", time.Now(), &err)
+	return i.EventsRepository.Update(ctx, id, newEvent)
+}
+
+func (i *InstrumentedEventsRepository) Delete(ctx context.Context, e structs.Event) (err error) {
+	defer i.metrics.observe(ctx, "events", "Delete", time.Now(), &err)
+	return i.EventsRepository.Delete(ctx, e)
+}
+
+func (i *InstrumentedEventsRepository) TimeReport(ctx context.Context, p structs.ReportParams) (report structs.TimeReport, err error) {
+	defer i.metrics.observe(ctx, "events", "TimeReport", time.Now(), &err)
+	return i.EventsRepository.TimeReport(ctx, p)
+}
+
+// InstrumentedUserRepository decorates a UserRepository with call timings and error counts.
+type InstrumentedUserRepository struct {
+	UserRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedUserRepository(repo UserRepository, m *RepositoryMetrics) *InstrumentedUserRepository {
+	return &InstrumentedUserRepository{UserRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedUserRepository) AddUser(ctx context.Context, u structs.CreateUser) (added structs.HashedInfo, err error) {
+	defer i.metrics.observe(ctx, "users", "AddUser", time.Now(), &err)
+	return i.UserRepository.AddUser(ctx, u)
+}
+
+func (i *InstrumentedUserRepository) GetUser(ctx context.Context, username string) (user structs.HashedInfo, err error) {
+	defer i.metrics.observe(ctx, "users", "GetUser", time.Now(), &err)
+	return i.UserRepository.GetUser(ctx, username)
+}
+
+func (i *InstrumentedUserRepository) UpdateLocation(ctx context.Context, user string, loc time.Location) (updated structs.HashedInfo, err error) {
+	defer i.metrics.observe(ctx, "users", "UpdateLocation", time.Now(), &err)
+	return i.UserRepository.UpdateLocation(ctx, user, loc)
+}
+
+func (i *InstrumentedUserRepository) GetAvailability(ctx context.Context, user string) (a structs.Availability, err error) {
+	defer i.metrics.observe(ctx, "users", "GetAvailability", time.Now(), &err)
+	return i.UserRepository.GetAvailability(ctx, user)
+}
+
+func (i *InstrumentedUserRepository) UpdateAvailability(ctx context.Context, user string, a structs.Availability) (updated structs.Availability, err error) {
+	defer i.metrics.observe(ctx, "users", "UpdateAvailability", time.Now(), &err)
+	return i.UserRepository.UpdateAvailability(ctx, user, a)
+}
+
+// InstrumentedIdempotencyRepository decorates an IdempotencyRepository with call timings and error counts.
+type InstrumentedIdempotencyRepository struct {
+	IdempotencyRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedIdempotencyRepository(repo IdempotencyRepository, m *RepositoryMetrics) *InstrumentedIdempotencyRepository {
+	return &InstrumentedIdempotencyRepository{IdempotencyRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedIdempotencyRepository) Reserve(ctx context.Context, record structs.IdempotencyRecord) (existing structs.IdempotencyRecord, reserved bool, err error) {
+	defer i.metrics.observe(ctx, "idempotency", "Reserve", time.Now(), &err)
+	return i.IdempotencyRepository.Reserve(ctx, record)
+}
+
+func (i *InstrumentedIdempotencyRepository) Get(ctx context.Context, key string) (record structs.IdempotencyRecord, err error) {
+	defer i.metrics.observe(ctx, "idempotency", "Get", time.Now(), &err)
+	return i.IdempotencyRepository.Get(ctx, key)
+}
+
+func (i *InstrumentedIdempotencyRepository) Complete(ctx context.Context, record structs.IdempotencyRecord) (err error) {
+	defer i.metrics.observe(ctx, "idempotency", "Complete", time.Now(), &err)
+	return i.IdempotencyRepository.Complete(ctx, record)
+}
+
+func (i *InstrumentedIdempotencyRepository) Release(ctx context.Context, reservation structs.IdempotencyRecord) (err error) {
+	defer i.metrics.observe(ctx, "idempotency", "Release", time.Now(), &err)
+	return i.IdempotencyRepository.Release(ctx, reservation)
+}
+
+func (i *InstrumentedIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (deleted int64, err error) {
+	defer i.metrics.observe(ctx, "idempotency", "DeleteExpired", time.Now(), &err)
+	return i.IdempotencyRepository.DeleteExpired(ctx, now)
+}
+
+// InstrumentedRelocationRepository decorates a RelocationRepository with call timings and error counts.
+type InstrumentedRelocationRepository struct {
+	RelocationRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedRelocationRepository(repo RelocationRepository, m *RepositoryMetrics) *InstrumentedRelocationRepository {
+	return &InstrumentedRelocationRepository{RelocationRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedRelocationRepository) Relocate(ctx context.Context, username string, loc time.Location, events []structs.Event) (user structs.HashedInfo, err error) {
+	defer i.metrics.observe(ctx, "relocation", "Relocate", time.Now(), &err)
+	return i.RelocationRepository.Relocate(ctx, username, loc, events)
+}
+
+// InstrumentedAlertsRepository decorates an AlertsRepository with call timings and error counts.
+type InstrumentedAlertsRepository struct {
+	AlertsRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedAlertsRepository(repo AlertsRepository, m *RepositoryMetrics) *InstrumentedAlertsRepository {
+	return &InstrumentedAlertsRepository{AlertsRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedAlertsRepository) Schedule(ctx context.Context, a structs.AlertInstance) (err error) {
+	defer i.metrics.observe(ctx, "alerts", "Schedule", time.Now(), &err)
+	return i.AlertsRepository.Schedule(ctx, a)
+}
+
+func (i *InstrumentedAlertsRepository) Cancel(ctx context.Context, eventId int) (err error) {
+	defer i.metrics.observe(ctx, "alerts", "Cancel", time.Now(), &err)
+	return i.AlertsRepository.Cancel(ctx, eventId)
+}
+
+func (i *InstrumentedAlertsRepository) CancelTask(ctx context.Context, taskId int) (err error) {
+	defer i.metrics.observe(ctx, "alerts", "CancelTask", time.Now(), &err)
+	return i.AlertsRepository.CancelTask(ctx, taskId)
+}
+
+func (i *InstrumentedAlertsRepository) Deliver(ctx context.Context, now time.Time) (delivered []structs.AlertInstance, err error) {
+	defer i.metrics.observe(ctx, "alerts", "Deliver", time.Now(), &err)
+	return i.AlertsRepository.Deliver(ctx, now)
+}
+
+func (i *InstrumentedAlertsRepository) Get(ctx context.Context, p structs.AlertParams) (alerts []structs.AlertInstance, err error) {
+	defer i.metrics.observe(ctx, "alerts", "Get", time.Now(), &err)
+	return i.AlertsRepository.Get(ctx, p)
+}
+
+func (i *InstrumentedAlertsRepository) GetByID(ctx context.Context, id int) (a structs.AlertInstance, err error) {
+	defer i.metrics.observe(ctx, "alerts", "GetByID", time.Now(), &err)
+	return i.AlertsRepository.GetByID(ctx, id)
+}
+
+func (i *InstrumentedAlertsRepository) Update(ctx context.Context, a structs.AlertInstance, previous structs.AlertState) (updated structs.AlertInstance, err error) {
+	defer i.metrics.observe(ctx, "alerts", "Update", time.Now(), &err)
+	return i.AlertsRepository.Update(ctx, a, previous)
+}
+
+// InstrumentedResourcesRepository decorates a ResourcesRepository with call timings and error counts.
+type InstrumentedResourcesRepository struct {
+	ResourcesRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedResourcesRepository(repo ResourcesRepository, m *RepositoryMetrics) *InstrumentedResourcesRepository {
+	return &InstrumentedResourcesRepository{ResourcesRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedResourcesRepository) Add(ctx context.Context, r structs.Resource) (added structs.Resource, err error) {
+	defer i.metrics.observe(ctx, "resources", "Add", time.Now(), &err)
+	return i.ResourcesRepository.Add(ctx, r)
+}
+
+func (i *InstrumentedResourcesRepository) Get(ctx context.Context) (resources []structs.Resource, err error) {
+	defer i.metrics.observe(ctx, "resources", "Get", time.Now(), &err)
+	return i.ResourcesRepository.Get(ctx)
+}
+
+func (i *InstrumentedResourcesRepository) GetByID(ctx context.Context, id int) (r structs.Resource, err error) {
+	defer i.metrics.observe(ctx, "resources", "GetByID", time.Now(), &err)
+	return i.ResourcesRepository.GetByID(ctx, id)
+}
+
+// InstrumentedBookingRepository decorates a BookingRepository with call timings and error counts.
+type InstrumentedBookingRepository struct {
+	BookingRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedBookingRepository(repo BookingRepository, m *RepositoryMetrics) *InstrumentedBookingRepository {
+	return &InstrumentedBookingRepository{BookingRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedBookingRepository) SavePage(ctx context.Context, p structs.BookingPage) (saved structs.BookingPage, err error) {
+	defer i.metrics.observe(ctx, "booking", "SavePage", time.Now(), &err)
+	return i.BookingRepository.SavePage(ctx, p)
+}
+
+func (i *InstrumentedBookingRepository) GetPage(ctx context.Context, slug string) (p structs.BookingPage, err error) {
+	defer i.metrics.observe(ctx, "booking", "GetPage", time.Now(), &err)
+	return i.BookingRepository.GetPage(ctx, slug)
+}
+
+func (i *InstrumentedBookingRepository) GetPages(ctx context.Context, owner string) (pages []structs.BookingPage, err error) {
+	defer i.metrics.observe(ctx, "booking", "GetPages", time.Now(), &err)
+	return i.BookingRepository.GetPages(ctx, owner)
+}
+
+func (i *InstrumentedBookingRepository) DeletePage(ctx context.Context, owner string, slug string) (err error) {
+	defer i.metrics.observe(ctx, "booking", "DeletePage", time.Now(), &err)
+	return i.BookingRepository.DeletePage(ctx, owner, slug)
+}
+
+func (i *InstrumentedBookingRepository) GetBookings(ctx context.Context, slug string, from time.Time, to time.Time) (bookings []structs.Booking, err error) {
+	defer i.metrics.observe(ctx, "booking", "GetBookings", time.Now(), &err)
+	return i.BookingRepository.GetBookings(ctx, slug, from, to)
+}
+
+func (i *InstrumentedBookingRepository) Book(ctx context.Context, window structs.Interval, b structs.Booking, e structs.Event,
+	check func([]structs.Event, []structs.Booking) error) (booked structs.Booking, err error) {
+	defer i.metrics.observe(ctx, "booking", "Book", time.Now(), &err)
+	return i.BookingRepository.Book(ctx, window, b, e, check)
+}
+
+func (i *InstrumentedBookingRepository) Cancel(ctx context.Context, slug string, token string) (cancelled structs.Booking, err error) {
+	defer i.metrics.observe(ctx, "booking", "Cancel", time.Now(), &err)
+	return i.BookingRepository.Cancel(ctx, slug, token)
+}
+
+// InstrumentedTasksRepository decorates a TasksRepository with call timings and error counts.
+type InstrumentedTasksRepository struct {
+	TasksRepository
+	metrics *RepositoryMetrics
+}
+
+func NewInstrumentedTasksRepository(repo TasksRepository, m *RepositoryMetrics) *InstrumentedTasksRepository {
+	return &InstrumentedTasksRepository{TasksRepository: repo, metrics: m}
+}
+
+func (i *InstrumentedTasksRepository) Add(ctx context.Context, t structs.Task) (added structs.Task, err error) {
+	defer i.metrics.observe(ctx, "tasks", "Add", time.Now(), &err)
+	return i.TasksRepository.Add(ctx, t)
+}
+
+func (i *InstrumentedTasksRepository) Get(ctx context.Context, p structs.TaskParams) (tasks []structs.Task, err error) {
+	defer i.metrics.observe(ctx, "tasks", "Get", time.Now(), &err)
+	return i.TasksRepository.Get(ctx, p)
+}
+
+func (i *InstrumentedTasksRepository) GetByID(ctx context.Context, id int) (t structs.Task, err error) {
+	defer i.metrics.observe(ctx, "tasks", "GetByID", time.Now(), &err)
+	return i.TasksRepository.GetByID(ctx, id)
+}
+
+func (i *InstrumentedTasksRepository) Update(ctx context.Context, t structs.Task) (updated structs.Task, err error) {
+	defer i.metrics.observe(ctx, "tasks", "Update", time.Now(), &err)
+	return i.TasksRepository.Update(ctx, t)
+}
+
+func (i *InstrumentedTasksRepository) Delete(ctx context.Context, id int) (err error) {
+	defer i.metrics.observe(ctx, "tasks", "Delete", time.Now(), &err)
+	return i.TasksRepository.Delete(ctx, id)
+}
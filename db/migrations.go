@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/structs"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+const dialect = "postgres"
+
+// migrationsTable is where sql-migrate records the applied migrations.
+const migrationsTable = "gorp_migrations"
+
+// migrations are embedded into the binary by go-bindata, see bindata.go
+var migrations = &migrate.AssetMigrationSource{
+	Asset:    Asset,
+	AssetDir: AssetDir,
+	Dir:      "../migrations",
+}
+
+type MigrationStatus struct {
+	Id        string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// MigrateUp applies at most max pending migrations, all of them when max is 0.
+func MigrateUp(conn *sql.DB, max int) (int, error) {
+	return execMigrations(conn, migrate.Up, max)
+}
+
+// MigrateDown rolls back the max most recently applied migrations.
+// Unlike MigrateUp a zero max is rejected, rolling back everything
+// has to be asked for explicitly.
+func MigrateDown(conn *sql.DB, max int) (int, error) {
+	if max <= 0 {
+		return 0, fmt.Errorf("number of migrations to roll back must be positive, got %d", max)
+	}
+	return execMigrations(conn, migrate.Down, max)
+}
+
+// MigrateRedo rolls back the last applied migration and applies it again.
+func MigrateRedo(conn *sql.DB) error {
+	n, err := execMigrations(conn, migrate.Down, 1)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w : there are no applied migrations to redo", structs.ErrNoMatch)
+	}
+	_, err = execMigrations(conn, migrate.Up, 1)
+	return err
+}
+
+func execMigrations(conn *sql.DB, direction migrate.MigrationDirection, max int) (int, error) {
+	n, err := migrate.ExecMax(conn, dialect, migrations, direction, max)
+	if err != nil {
+		return n, fmt.Errorf("%w : migration failed after %d applied : %v", structs.ErrPostgres, n, err.Error())
+	}
+	logger.Infof("applied %d migrations", n)
+	return n, nil
+}
+
+// GetMigrationStatus lists every known migration in order, together with
+// the time it was applied if it was.
+func GetMigrationStatus(ctx context.Context, conn *sql.DB) ([]MigrationStatus, error) {
+	known, err := migrations.FindMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v", structs.ErrPostgres, err.Error()))
+	}
+
+	result := make([]MigrationStatus, 0, len(known))
+	for _, m := range known {
+		appliedAt, ok := applied[m.Id]
+		result = append(result, MigrationStatus{Id: m.Id, Applied: ok, AppliedAt: appliedAt})
+	}
+	return result, nil
+}
+
+// appliedMigrations reads the records of sql-migrate itself, which takes no context.
+// A database sql-migrate never ran on has no records yet.
+func appliedMigrations(ctx context.Context, conn *sql.DB) (map[string]time.Time, error) {
+	applied := make(map[string]time.Time)
+	rows, err := conn.QueryContext(ctx, "SELECT id, applied_at FROM "+migrationsTable)
+	if hasCode(err, pqUndefinedTable) {
+		return applied, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var appliedAt time.Time
+		if err = rows.Scan(&id, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[id] = appliedAt
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+type RelocationDBRepository struct {
+	Conn         *sql.DB
+	QueryTimeout time.Duration
+}
+
+func NewRelocationDBRepository(conn *sql.DB) (*RelocationDBRepository, error) {
+	return &RelocationDBRepository{Conn: conn}, nil
+}
+
+// Relocate runs in a single transaction, events are only changed when they belong to username.
+func (db *RelocationDBRepository) Relocate(ctx context.Context, username string, loc time.Location, events []structs.Event) (structs.HashedInfo, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return structs.HashedInfo{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer tx.Rollback()
+
+	var user structs.CreateUser
+	query := `UPDATE users SET userlocation = $1 WHERE username = $2 RETURNING username, hashedpass, userlocation;`
+	err = tx.QueryRowContext(ctx, query, loc.String(), username).Scan(&user.Username, &user.Password, &user.Location)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			message := "user with username [" + username + "] does not exist"
+			return structs.HashedInfo{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+		}
+		return structs.HashedInfo{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+
+	query = `UPDATE events SET event_start = $1, event_end = $2, event_alert = $3
+	WHERE eventid = $4 AND event_owner IN ($5, '');`
+	for _, e := range events {
+		res, err := tx.ExecContext(ctx, query, e.Start, e.End, e.Alert, e.Id, username)
+		if err != nil {
+			return structs.HashedInfo{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			message := "event with id [" + fmt.Sprint(e.Id) + "] does not exist"
+			return structs.HashedInfo{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+		}
+		if err = saveReminders(ctx, tx, e.Id, e.Reminders); err != nil {
+			return structs.HashedInfo{}, contextError(ctx, err)
+		}
+		if err = saveResources(ctx, tx, e); err != nil {
+			return structs.HashedInfo{}, contextError(ctx, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return structs.HashedInfo{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	newLoc, err := storedLocation(user.Location)
+	if err != nil {
+		return structs.HashedInfo{}, err
+	}
+	return structs.HashedInfo{
+		Username:   user.Username,
+		Location:   *newLoc,
+		HashedPass: user.Password,
+	}, nil
+}
+
+// RelocationInMemoryRepository is used with the memory and file storage. Its
+// repositories have no transactions, so changes made before a failure are undone.
+type RelocationInMemoryRepository struct {
+	Users  UserRepository
+	Events EventsRepository
+	mu     sync.Mutex
+}
+
+func NewRelocationInMemoryRepository(users UserRepository, events EventsRepository) (*RelocationInMemoryRepository, error) {
+	return &RelocationInMemoryRepository{Users: users, Events: events}, nil
+}
+
+func (m *RelocationInMemoryRepository) Relocate(ctx context.Context, username string, loc time.Location, events []structs.Event) (structs.HashedInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.Users.GetUser(ctx, username); err != nil {
+		return structs.HashedInfo{}, err
+	}
+	previous := make([]structs.Event, 0, len(events))
+	for _, e := range events {
+		stored, err := m.Events.GetByID(ctx, e.Id)
+		if err != nil {
+			return structs.HashedInfo{}, err
+		}
+		if !stored.BelongsTo(username) {
+			message := "event with id [" + fmt.Sprint(e.Id) + "] does not exist"
+			return structs.HashedInfo{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+		}
+		previous = append(previous, stored)
+	}
+
+	for i, e := range events {
+		if _, err := m.Events.Update(ctx, e.Id, e); err != nil {
+			m.restore(previous[:i])
+			return structs.HashedInfo{}, err
+		}
+	}
+	updated, err := m.Users.UpdateLocation(ctx, username, loc)
+	if err != nil {
+		m.restore(previous)
+		return structs.HashedInfo{}, err
+	}
+	return updated, nil
+}
+
+// restore puts back events changed by a failed relocation, the request may
+// already be cancelled, so it does not use its context.
+func (m *RelocationInMemoryRepository) restore(events []structs.Event) {
+	for _, e := range events {
+		m.Events.Update(context.Background(), e.Id, e)
+	}
+}
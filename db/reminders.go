@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/lib/pq"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// saveReminders replaces the reminders of the event with id, the caller runs it
+// in the transaction that writes the event.
+func saveReminders(ctx context.Context, tx execer, id int, reminders []structs.Reminder) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM event_reminders WHERE eventid = $1;`, id)
+	if err != nil {
+		return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+	}
+	query := `INSERT INTO event_reminders (eventid, position, relative_to, offset_spec, at_time, channel, remind_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7);`
+	for i, r := range reminders {
+		_, err = tx.ExecContext(ctx, query, id, i, r.RelativeTo, r.Offset, r.At, r.Channel, r.RemindAt)
+		if err != nil {
+			return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		}
+	}
+	return nil
+}
+
+// loadReminders reads the reminders of all events with a single query.
+func loadReminders(ctx context.Context, q queryer, events []structs.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(events))
+	byId := make(map[int]int, len(events))
+	for i, e := range events {
+		ids[i] = int64(e.Id)
+		byId[e.Id] = i
+	}
+	query := `SELECT eventid, relative_to, offset_spec, at_time, channel, remind_at
+	FROM event_reminders
+	WHERE eventid = ANY($1)
+	ORDER BY eventid, position;`
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var r structs.Reminder
+		if err := rows.Scan(&id, &r.RelativeTo, &r.Offset, &r.At, &r.Channel, &r.RemindAt); err != nil {
+			return fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+		}
+		r.RemindAt = r.RemindAt.UTC()
+		e := &events[byId[id]]
+		e.Reminders = append(e.Reminders, r)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// averageLength is the kind of the row of the time report query holding the average
+// length of the events instead of a total.
+const averageLength = "average"
+
+// timeReportQuery splits the timed events in the window at the local midnights of
+// the zone of the report, keys every piece by the whole report, its day and its group,
+// and merges the overlapping pieces of every key into islands, so that time taken by
+// several events is counted once.
+const timeReportQuery = `
+WITH windowed AS (
+	SELECT eventid, event_name, COALESCE(event_description, '') AS event_description, event_start, event_end,
+		GREATEST(event_start, $2::timestamp) AS piece_start, LEAST(event_end, $3::timestamp) AS piece_end
+	FROM events
+	WHERE event_owner IN ($1, '') AND NOT event_all_day AND event_start < $3::timestamp AND event_end > $2::timestamp AND
+	($4::text = '' OR event_name ILIKE $4 ESCAPE '\')
+),
+pieces AS (
+	SELECT w.eventid, w.event_name, w.event_description, covered.day::date AS day,
+		GREATEST(w.piece_start, (covered.day AT TIME ZONE $5) AT TIME ZONE 'UTC') AS piece_start,
+		LEAST(w.piece_end, ((covered.day + interval '1 day') AT TIME ZONE $5) AT TIME ZONE 'UTC') AS piece_end
+	FROM windowed w, generate_series(
+		((w.piece_start AT TIME ZONE 'UTC') AT TIME ZONE $5)::date::timestamp,
+		GREATEST(((w.piece_start AT TIME ZONE 'UTC') AT TIME ZONE $5)::date,
+			(((w.piece_end - interval '1 microsecond') AT TIME ZONE 'UTC') AT TIME ZONE $5)::date)::timestamp,
+		interval '1 day') AS covered(day)
+),
+keyed AS (
+	SELECT '` + structs.TotalOfAll + `' AS kind, '' AS key, eventid, piece_start, piece_end FROM pieces
+	UNION ALL
+	SELECT '` + structs.TotalOfDay + `', to_char(day, 'YYYY-MM-DD'), eventid, piece_start, piece_end FROM pieces
+	UNION ALL
+	SELECT '` + structs.TotalOfGroup + `', CASE $6::text
+		WHEN '` + structs.ReportByWeek + `' THEN to_char(day - (extract(dow FROM day)::int - $7 + 7) % 7, 'YYYY-MM-DD')
+		WHEN '` + structs.ReportByName + `' THEN event_name
+		WHEN '` + structs.ReportByTag + `' THEN COALESCE(tags.tag, $8)
+		ELSE to_char(day, 'YYYY-MM-DD') END,
+		eventid, piece_start, piece_end
+	FROM pieces LEFT JOIN LATERAL (
+		SELECT DISTINCT lower(found.groups[1]) AS tag
+		FROM regexp_matches(event_name || ' ' || event_description, '` + structs.TagPattern + `', 'g') AS found(groups)
+		WHERE $6::text = '` + structs.ReportByTag + `') AS tags ON true
+),
+ordered AS (
+	SELECT kind, key, piece_start, piece_end,
+		MAX(piece_end) OVER (PARTITION BY kind, key ORDER BY piece_start, piece_end
+			ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) AS reach
+	FROM keyed
+),
+islands AS (
+	SELECT kind, key, piece_start, piece_end,
+		SUM(CASE WHEN reach IS NULL OR piece_start > reach THEN 1 ELSE 0 END) OVER (PARTITION BY kind, key
+			ORDER BY piece_start, piece_end ROWS UNBOUNDED PRECEDING) AS island
+	FROM ordered
+),
+merged AS (
+	SELECT kind, key, MIN(piece_start) AS piece_start, MAX(piece_end) AS piece_end
+	FROM islands
+	GROUP BY kind, key, island
+),
+counted AS (
+	SELECT kind, key, COUNT(DISTINCT eventid) AS events
+	FROM keyed
+	GROUP BY kind, key
+)
+SELECT m.kind, m.key, SUM(EXTRACT(EPOCH FROM m.piece_end - m.piece_start))::float8, c.events
+FROM merged m JOIN counted c ON c.kind = m.kind AND c.key = m.key
+GROUP BY m.kind, m.key, c.events
+UNION ALL
+SELECT '` + averageLength + `', '', COALESCE(AVG(EXTRACT(EPOCH FROM event_end - event_start)), 0)::float8, COUNT(*)
+FROM windowed;`
+
+// TimeReport aggregates the scheduled time of the events selected by p in a single query.
+func (db *EventsDBRepository) TimeReport(ctx context.Context, p structs.ReportParams) (structs.TimeReport, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	rows, err := db.Conn.QueryContext(ctx, timeReportQuery, p.Owner, p.From.UTC(), p.To.UTC(), p.NamePattern(),
+		p.Location.String(), p.GroupBy, int(p.WeekStart), structs.Untagged)
+	if err != nil {
+		return structs.TimeReport{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer rows.Close()
+	var totals []structs.ReportTotal
+	var average float64
+	for rows.Next() {
+		var total structs.ReportTotal
+		if err = rows.Scan(&total.Kind, &total.Key, &total.Seconds, &total.Events); err != nil {
+			return structs.TimeReport{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		if total.Kind == averageLength {
+			average = total.Seconds
+			continue
+		}
+		totals = append(totals, total)
+	}
+	if err = rows.Err(); err != nil {
+		return structs.TimeReport{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	return structs.NewTimeReport(p, totals, average), nil
+}
+
+func (a *ArrayRepository) TimeReport(ctx context.Context, p structs.ReportParams) (structs.TimeReport, error) {
+	totals, average := structs.SummarizeTime(p, a.events())
+	return structs.NewTimeReport(p, totals, average), nil
+}
+
+func (m *MapRepository) TimeReport(ctx context.Context, p structs.ReportParams) (structs.TimeReport, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	totals, average := structs.SummarizeTime(p, m.events())
+	return structs.NewTimeReport(p, totals, average), nil
+}
@@ -1,24 +1,109 @@
 package db
 
 import (
+	"context"
 	"time"
 
 	"github.com/dkucheru/Calendar/structs"
 )
 
 type EventsRepository interface {
-	Add(structs.Event) (structs.Event, error)
-	Get(structs.EventParams) ([]structs.Event, error)
-	GetByID(id int) (structs.Event, error)
-	Update(id int, newEvent structs.Event) (updated structs.Event, err error)
-	Delete(structs.Event) error
+	Add(ctx context.Context, e structs.Event) (structs.Event, error)
+	Get(ctx context.Context, p structs.EventParams) ([]structs.Event, error)
+	GetByID(ctx context.Context, id int) (structs.Event, error)
+	Update(ctx context.Context, id int, newEvent structs.Event) (updated structs.Event, err error)
+	Delete(ctx context.Context, e structs.Event) error
+	// TimeReport sums up the time the timed events selected by p take, overlaps counted once.
+	TimeReport(ctx context.Context, p structs.ReportParams) (structs.TimeReport, error)
 	GetLastUsedId() int //this function currently is used only for testing purpuses
 	ClearRepoData() error
 }
 
 type UserRepository interface {
-	AddUser(structs.CreateUser) (structs.HashedInfo, error)
-	GetUser(string) (structs.HashedInfo, error)
-	UpdateLocation(user string, loc time.Location) (structs.HashedInfo, error)
+	AddUser(ctx context.Context, u structs.CreateUser) (structs.HashedInfo, error)
+	GetUser(ctx context.Context, username string) (structs.HashedInfo, error)
+	UpdateLocation(ctx context.Context, user string, loc time.Location) (structs.HashedInfo, error)
+	// GetAvailability returns the working hours of user, an empty profile when none were set.
+	GetAvailability(ctx context.Context, user string) (structs.Availability, error)
+	// UpdateAvailability replaces the working hours of user with a normalized profile.
+	UpdateAvailability(ctx context.Context, user string, a structs.Availability) (structs.Availability, error)
+	ClearRepoData() error
+}
+
+// IdempotencyRepository keeps the responses to requests sent with an Idempotency-Key.
+type IdempotencyRepository interface {
+	// Reserve stores record unless an unexpired record with the same key exists,
+	// then that record is returned and reserved is false.
+	Reserve(ctx context.Context, record structs.IdempotencyRecord) (existing structs.IdempotencyRecord, reserved bool, err error)
+	Get(ctx context.Context, key string) (structs.IdempotencyRecord, error)
+	// Complete stores the response and the expiry of record in the reservation with its key
+	// and CreatedAt, it fails when the reservation expired and was taken over.
+	Complete(ctx context.Context, record structs.IdempotencyRecord) error
+	// Release forgets the reservation with the key and CreatedAt of reservation,
+	// so that the request can be retried.
+	Release(ctx context.Context, reservation structs.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// RelocationRepository changes the location of a user together with the times of
+// some of their events, either all of the changes are stored or none of them.
+type RelocationRepository interface {
+	Relocate(ctx context.Context, username string, loc time.Location, events []structs.Event) (structs.HashedInfo, error)
+}
+
+// AlertsRepository tracks the instances of event alerts and task reminders and what their owners did with them.
+type AlertsRepository interface {
+	// Schedule replaces the pending alert of the event a belongs to with a,
+	// a zero AlertAt only drops the pending alert.
+	Schedule(ctx context.Context, a structs.AlertInstance) error
+	// Cancel forgets all alerts of a deleted event.
+	Cancel(ctx context.Context, eventId int) error
+	// CancelTask forgets all alerts of a deleted task.
+	CancelTask(ctx context.Context, taskId int) error
+	// Deliver marks pending and snoozed alerts that are due at now as delivered and returns them.
+	Deliver(ctx context.Context, now time.Time) ([]structs.AlertInstance, error)
+	Get(ctx context.Context, p structs.AlertParams) ([]structs.AlertInstance, error)
+	GetByID(ctx context.Context, id int) (structs.AlertInstance, error)
+	// Update stores the state of a, unless it left the state previous in the meantime.
+	Update(ctx context.Context, a structs.AlertInstance, previous structs.AlertState) (structs.AlertInstance, error)
+	ClearRepoData() error
+}
+
+// ResourcesRepository keeps the rooms and equipment events book, bookings are kept with the events.
+type ResourcesRepository interface {
+	Add(ctx context.Context, r structs.Resource) (structs.Resource, error)
+	Get(ctx context.Context) ([]structs.Resource, error)
+	GetByID(ctx context.Context, id int) (structs.Resource, error)
+	ClearRepoData() error
+}
+
+// BookingRepository keeps booking pages and the bookings made through them.
+type BookingRepository interface {
+	// SavePage creates or replaces the page with the slug of p, slugs of other users are conflicts.
+	SavePage(ctx context.Context, p structs.BookingPage) (structs.BookingPage, error)
+	GetPage(ctx context.Context, slug string) (structs.BookingPage, error)
+	GetPages(ctx context.Context, owner string) ([]structs.BookingPage, error)
+	DeletePage(ctx context.Context, owner string, slug string) error
+	// GetBookings returns the bookings of the page starting between from and to.
+	GetBookings(ctx context.Context, slug string, from time.Time, to time.Time) ([]structs.Booking, error)
+	// Book stores e in the calendar of the owner of the page of b together with b, when check
+	// accepts the timed events of the owner and the bookings of the page within window as
+	// they are at that moment. Bookings of one owner are made one at a time.
+	Book(ctx context.Context, window structs.Interval, b structs.Booking, e structs.Event,
+		check func([]structs.Event, []structs.Booking) error) (structs.Booking, error)
+	// Cancel deletes the booking with token together with its event.
+	Cancel(ctx context.Context, slug string, token string) (structs.Booking, error)
+	ClearRepoData() error
+}
+
+// TasksRepository keeps the to-dos of users, their reminders are kept by the AlertsRepository.
+type TasksRepository interface {
+	Add(ctx context.Context, t structs.Task) (structs.Task, error)
+	// Get returns the tasks selected by p ordered by their due times.
+	Get(ctx context.Context, p structs.TaskParams) ([]structs.Task, error)
+	GetByID(ctx context.Context, id int) (structs.Task, error)
+	// Update replaces the task with the id of t, its owner does not change.
+	Update(ctx context.Context, t structs.Task) (structs.Task, error)
+	Delete(ctx context.Context, id int) error
 	ClearRepoData() error
 }
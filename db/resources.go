@@ -0,0 +1,223 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+	"github.com/lib/pq"
+)
+
+type ResourcesDBRepository struct {
+	Conn         *sql.DB
+	QueryTimeout time.Duration
+}
+
+func NewResourcesDBRepository(conn *sql.DB) (*ResourcesDBRepository, error) {
+	return &ResourcesDBRepository{Conn: conn}, nil
+}
+
+const resourceColumns = `resource_id, resource_name, resource_kind, capacity, resource_location`
+
+func scanResource(row rowScanner) (structs.Resource, error) {
+	var r structs.Resource
+	err := row.Scan(&r.Id, &r.Name, &r.Kind, &r.Capacity, &r.Location)
+	return r, err
+}
+
+func (db *ResourcesDBRepository) Add(ctx context.Context, r structs.Resource) (structs.Resource, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `INSERT INTO resources (resource_name, resource_kind, capacity, resource_location)
+	VALUES ($1, $2, $3, $4) RETURNING ` + resourceColumns + `;`
+	added, err := scanResource(db.Conn.QueryRowContext(ctx, query, r.Name, r.Kind, r.Capacity, r.Location))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return structs.Resource{}, resourceNameError(r.Name)
+		}
+		return structs.Resource{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return added, nil
+}
+
+func (db *ResourcesDBRepository) Get(ctx context.Context) ([]structs.Resource, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	rows, err := db.Conn.QueryContext(ctx, `SELECT `+resourceColumns+` FROM resources ORDER BY resource_id;`)
+	if err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer rows.Close()
+	list := make([]structs.Resource, 0)
+	for rows.Next() {
+		r, err := scanResource(rows)
+		if err != nil {
+			return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		list = append(list, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	return list, nil
+}
+
+func (db *ResourcesDBRepository) GetByID(ctx context.Context, id int) (structs.Resource, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `SELECT ` + resourceColumns + ` FROM resources WHERE resource_id = $1;`
+	r, err := scanResource(db.Conn.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return structs.Resource{}, resourceNotFound(id)
+		}
+		return structs.Resource{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return r, nil
+}
+
+func (db *ResourcesDBRepository) ClearRepoData() error {
+	rows, err := db.Conn.Query("TRUNCATE resources, event_resources RESTART IDENTITY;")
+	if err != nil {
+		return fmt.Errorf("%w : error occured when truncating resources table : %v", structs.ErrPostgres, err.Error())
+	}
+	defer rows.Close()
+	return nil
+}
+
+// saveResources replaces the bookings of e, the caller runs it in the transaction
+// that writes the event. The exclusion constraint of event_resources rejects
+// bookings that overlap the ones of other events.
+func saveResources(ctx context.Context, tx execer, e structs.Event) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM event_resources WHERE eventid = $1;`, e.Id)
+	if err != nil {
+		return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+	}
+	query := `INSERT INTO event_resources (eventid, resource_id, booked) VALUES ($1, $2, tsrange($3, $4, '[)'));`
+	for _, id := range e.Resources {
+		_, err = tx.ExecContext(ctx, query, e.Id, id, e.Start.UTC(), e.End.UTC())
+		switch {
+		case err == nil:
+		case isExclusionViolation(err):
+			return structs.BookingError(id)
+		case isForeignKeyViolation(err):
+			return resourceNotFound(id)
+		default:
+			return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+		}
+	}
+	return nil
+}
+
+// loadResources reads the bookings of all events with a single query.
+func loadResources(ctx context.Context, q queryer, events []structs.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(events))
+	byId := make(map[int]int, len(events))
+	for i, e := range events {
+		ids[i] = int64(e.Id)
+		byId[e.Id] = i
+	}
+	query := `SELECT eventid, resource_id
+	FROM event_resources
+	WHERE eventid = ANY($1)
+	ORDER BY eventid, resource_id;`
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error())
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var eventId, resourceId int
+		if err := rows.Scan(&eventId, &resourceId); err != nil {
+			return fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+		}
+		e := &events[byId[eventId]]
+		e.Resources = append(e.Resources, resourceId)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%w : %v ", structs.ErrSql, err.Error())
+	}
+	return nil
+}
+
+// checkBookings emulates the exclusion constraint of event_resources for the
+// repositories in memory, their callers hold the lock of the stored events.
+func checkBookings(e structs.Event, stored []structs.Event) error {
+	for _, other := range stored {
+		if id, booked := structs.BookingConflict(e, other); booked {
+			return structs.BookingError(id)
+		}
+	}
+	return nil
+}
+
+func resourceNotFound(id int) error {
+	message := "resource with id [" + fmt.Sprint(id) + "] does not exist"
+	return fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+}
+
+func resourceNameError(name string) error {
+	message := "resource with name [" + name + "] already exists"
+	return fmt.Errorf("%w : %v ", structs.ErrDublicate, message)
+}
+
+// Implementation of ResourcesRepository based on map.
+type ResourcesInMemoryRepository struct {
+	Resources map[int]structs.Resource
+	NextId    int
+	mu        sync.RWMutex
+}
+
+func NewResourcesInMemoryRepository() (*ResourcesInMemoryRepository, error) {
+	return &ResourcesInMemoryRepository{Resources: make(map[int]structs.Resource), NextId: 1}, nil
+}
+
+func (m *ResourcesInMemoryRepository) Add(ctx context.Context, r structs.Resource) (structs.Resource, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.Resources {
+		if stored.Name == r.Name {
+			return structs.Resource{}, resourceNameError(r.Name)
+		}
+	}
+	r.Id = m.NextId
+	m.NextId++
+	m.Resources[r.Id] = r
+	return r, nil
+}
+
+func (m *ResourcesInMemoryRepository) Get(ctx context.Context) ([]structs.Resource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]structs.Resource, 0, len(m.Resources))
+	for _, r := range m.Resources {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	return list, nil
+}
+
+func (m *ResourcesInMemoryRepository) GetByID(ctx context.Context, id int) (structs.Resource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.Resources[id]
+	if !ok {
+		return structs.Resource{}, resourceNotFound(id)
+	}
+	return r, nil
+}
+
+func (m *ResourcesInMemoryRepository) ClearRepoData() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Resources = make(map[int]structs.Resource)
+	m.NextId = 1
+	return nil
+}
@@ -0,0 +1,238 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+type TasksDBRepository struct {
+	Conn         *sql.DB
+	QueryTimeout time.Duration
+}
+
+func NewTasksDBRepository(conn *sql.DB) (*TasksDBRepository, error) {
+	return &TasksDBRepository{Conn: conn}, nil
+}
+
+const taskColumns = `taskid, username, title, notes, due, priority, status, completed_at, remind_before_minutes`
+
+func scanTask(row rowScanner) (structs.Task, error) {
+	var t structs.Task
+	var completedAt sql.NullTime
+	var remindBefore sql.NullInt64
+	err := row.Scan(&t.Id, &t.Owner, &t.Title, &t.Notes, &t.Due, &t.Priority, &t.Status, &completedAt, &remindBefore)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	t.Due = t.Due.UTC()
+	if completedAt.Valid {
+		t.CompletedAt = completedAt.Time.UTC()
+	}
+	if remindBefore.Valid {
+		t.RemindBefore = structs.ShortDuration(time.Duration(remindBefore.Int64) * time.Minute)
+	}
+	return t, nil
+}
+
+// taskValues are the values of the columns of t, following its id and owner.
+func taskValues(t structs.Task) []interface{} {
+	var completedAt, remindBefore interface{}
+	if !t.CompletedAt.IsZero() {
+		completedAt = t.CompletedAt.UTC()
+	}
+	if before, err := time.ParseDuration(t.RemindBefore); err == nil && before > 0 {
+		remindBefore = int(before / time.Minute)
+	}
+	return []interface{}{t.Title, t.Notes, t.Due.UTC(), t.Priority, t.Status, completedAt, remindBefore}
+}
+
+func (db *TasksDBRepository) Add(ctx context.Context, t structs.Task) (structs.Task, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `INSERT INTO tasks (username, title, notes, due, priority, status, completed_at, remind_before_minutes)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + taskColumns + `;`
+	added, err := scanTask(db.Conn.QueryRowContext(ctx, query, append([]interface{}{t.Owner}, taskValues(t)...)...))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			message := "user with username [" + t.Owner + "] does not exist"
+			return structs.Task{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+		}
+		return structs.Task{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return added, nil
+}
+
+func (db *TasksDBRepository) Get(ctx context.Context, p structs.TaskParams) ([]structs.Task, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	var dueFrom, dueBefore interface{}
+	if !p.DueFrom.IsZero() {
+		dueFrom = p.DueFrom.UTC()
+	}
+	if !p.DueBefore.IsZero() {
+		dueBefore = p.DueBefore.UTC()
+	}
+	query := `SELECT ` + taskColumns + ` FROM tasks
+	WHERE (username = $1 OR $1 = '') AND (status = $2 OR $2 = '')
+	AND ($3::TIMESTAMP IS NULL OR due >= $3) AND ($4::TIMESTAMP IS NULL OR due < $4)
+	AND (NOT $5 OR status NOT IN ('done', 'cancelled'))
+	ORDER BY due, taskid;`
+	rows, err := db.Conn.QueryContext(ctx, query, p.Owner, p.Status, dueFrom, dueBefore, p.Unfinished)
+	if err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	defer rows.Close()
+	list := make([]structs.Task, 0)
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+		}
+		list = append(list, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrSql, err.Error()))
+	}
+	return list, nil
+}
+
+func (db *TasksDBRepository) GetByID(ctx context.Context, id int) (structs.Task, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE taskid = $1;`
+	t, err := scanTask(db.Conn.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return structs.Task{}, taskNotFound(id)
+		}
+		return structs.Task{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return t, nil
+}
+
+func (db *TasksDBRepository) Update(ctx context.Context, t structs.Task) (structs.Task, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	query := `UPDATE tasks SET title = $2, notes = $3, due = $4, priority = $5, status = $6, completed_at = $7,
+	remind_before_minutes = $8
+	WHERE taskid = $1 RETURNING ` + taskColumns + `;`
+	updated, err := scanTask(db.Conn.QueryRowContext(ctx, query, append([]interface{}{t.Id}, taskValues(t)...)...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return structs.Task{}, taskNotFound(t.Id)
+		}
+		return structs.Task{}, contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	return updated, nil
+}
+
+func (db *TasksDBRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	res, err := db.Conn.ExecContext(ctx, `DELETE FROM tasks WHERE taskid = $1;`, id)
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("%w : %v ", structs.ErrPostgres, err.Error()))
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return taskNotFound(id)
+	}
+	return nil
+}
+
+func (db *TasksDBRepository) ClearRepoData() error {
+	_, err := db.Conn.Exec("TRUNCATE tasks, alert_instances RESTART IDENTITY;")
+	if err != nil {
+		return fmt.Errorf("%w : error occured when truncating tasks table : %v", structs.ErrPostgres, err.Error())
+	}
+	return nil
+}
+
+func taskNotFound(id int) error {
+	message := "task with id [" + fmt.Sprint(id) + "] does not exist"
+	return fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+}
+
+// TasksInMemoryRepository is used with the memory storage and kept on disk by TasksFileRepository.
+type TasksInMemoryRepository struct {
+	Tasks  map[int]structs.Task
+	NextId int
+	mu     sync.RWMutex
+}
+
+func NewTasksInMemoryRepository() (*TasksInMemoryRepository, error) {
+	return &TasksInMemoryRepository{Tasks: make(map[int]structs.Task), NextId: 1}, nil
+}
+
+func (m *TasksInMemoryRepository) Add(ctx context.Context, t structs.Task) (structs.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t.Id = m.NextId
+	m.NextId++
+	m.Tasks[t.Id] = t
+	return t, nil
+}
+
+func (m *TasksInMemoryRepository) Get(ctx context.Context, p structs.TaskParams) ([]structs.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]structs.Task, 0)
+	for _, t := range m.Tasks {
+		if structs.SuitsTaskParams(p, t) {
+			list = append(list, t)
+		}
+	}
+	// in the order of the database
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Due.Equal(list[j].Due) {
+			return list[i].Due.Before(list[j].Due)
+		}
+		return list[i].Id < list[j].Id
+	})
+	return list, nil
+}
+
+func (m *TasksInMemoryRepository) GetByID(ctx context.Context, id int) (structs.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.Tasks[id]
+	if !ok {
+		return structs.Task{}, taskNotFound(id)
+	}
+	return t, nil
+}
+
+func (m *TasksInMemoryRepository) Update(ctx context.Context, t structs.Task) (structs.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.Tasks[t.Id]
+	if !ok {
+		return structs.Task{}, taskNotFound(t.Id)
+	}
+	t.Owner = stored.Owner
+	m.Tasks[t.Id] = t
+	return t, nil
+}
+
+func (m *TasksInMemoryRepository) Delete(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.Tasks[id]; !ok {
+		return taskNotFound(id)
+	}
+	delete(m.Tasks, id)
+	return nil
+}
+
+func (m *TasksInMemoryRepository) ClearRepoData() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Tasks = make(map[int]structs.Task)
+	m.NextId = 1
+	return nil
+}
@@ -0,0 +1,234 @@
+// Package logger writes leveled, structured log lines in JSON or logfmt format.
+// Fields attached to an Entry, such as a request id, are repeated on every
+// line logged through it, and an Entry can travel with a request in its context.
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Level int32
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+func ParseLevel(s string) (Level, error) {
+	for level, name := range levelNames {
+		if strings.ToLower(s) == name {
+			return level, nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q", s)
+}
+
+const (
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+)
+
+func ValidFormat(format string) error {
+	if format != FormatJSON && format != FormatLogfmt {
+		return fmt.Errorf("unknown log format %q", format)
+	}
+	return nil
+}
+
+var (
+	currentLevel = int32(InfoLevel)
+
+	outputMu sync.Mutex
+	output   io.Writer = os.Stderr
+	format             = FormatLogfmt
+)
+
+func SetLevel(l Level) {
+	atomic.StoreInt32(&currentLevel, int32(l))
+}
+
+func Enabled(l Level) bool {
+	return int32(l) >= atomic.LoadInt32(&currentLevel)
+}
+
+func SetOutput(w io.Writer) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	output = w
+}
+
+func SetFormat(f string) error {
+	if err := ValidFormat(f); err != nil {
+		return err
+	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	format = f
+	return nil
+}
+
+type Fields map[string]interface{}
+
+// Entry is a logger with a fixed set of fields. Entries are immutable,
+// WithField and WithFields return new ones.
+type Entry struct {
+	fields Fields
+}
+
+var root = &Entry{}
+
+func WithField(key string, value interface{}) *Entry {
+	return root.WithField(key, value)
+}
+
+func WithFields(fields Fields) *Entry {
+	return root.WithFields(fields)
+}
+
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return e.WithFields(Fields{key: value})
+}
+
+func (e *Entry) WithFields(fields Fields) *Entry {
+	merged := make(Fields, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		merged[k] = v
+	}
+	return &Entry{fields: merged}
+}
+
+func (e *Entry) Debugf(f string, args ...interface{}) { e.logf(DebugLevel, f, args...) }
+func (e *Entry) Infof(f string, args ...interface{})  { e.logf(InfoLevel, f, args...) }
+func (e *Entry) Warnf(f string, args ...interface{})  { e.logf(WarnLevel, f, args...) }
+func (e *Entry) Errorf(f string, args ...interface{}) { e.logf(ErrorLevel, f, args...) }
+
+func (e *Entry) Log(l Level, msg string) {
+	if !Enabled(l) {
+		return
+	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	var line []byte
+	if format == FormatJSON {
+		line = e.json(l, msg)
+	} else {
+		line = e.logfmt(l, msg)
+	}
+	output.Write(line)
+}
+
+func (e *Entry) logf(l Level, f string, args ...interface{}) {
+	if !Enabled(l) {
+		return
+	}
+	e.Log(l, fmt.Sprintf(f, args...))
+}
+
+func (e *Entry) sortedKeys() []string {
+	keys := make([]string, 0, len(e.fields))
+	for k := range e.fields {
+		if k == "time" || k == "level" || k == "msg" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (e *Entry) json(l Level, msg string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSONValue(&buf, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSONValue(&buf, l.String())
+	buf.WriteString(`,"msg":`)
+	writeJSONValue(&buf, msg)
+	for _, k := range e.sortedKeys() {
+		buf.WriteByte(',')
+		writeJSONValue(&buf, k)
+		buf.WriteByte(':')
+		writeJSONValue(&buf, e.fields[k])
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		encoded, _ = json.Marshal(fmt.Sprint(v))
+	}
+	buf.Write(encoded)
+}
+
+func (e *Entry) logfmt(l Level, msg string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("time=" + time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(" level=" + l.String())
+	buf.WriteString(" msg=" + logfmtValue(msg))
+	for _, k := range e.sortedKeys() {
+		buf.WriteString(" " + k + "=" + logfmtValue(fmt.Sprint(e.fields[k])))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func Debugf(format string, args ...interface{}) { root.logf(DebugLevel, format, args...) }
+func Infof(format string, args ...interface{})  { root.logf(InfoLevel, format, args...) }
+func Warnf(format string, args ...interface{})  { root.logf(WarnLevel, format, args...) }
+func Errorf(format string, args ...interface{}) { root.logf(ErrorLevel, format, args...) }
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying e, retrieved later with FromContext.
+func NewContext(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext returns the entry stored in ctx, or an entry without fields.
+func FromContext(ctx context.Context) *Entry {
+	if ctx != nil {
+		if e, ok := ctx.Value(contextKey{}).(*Entry); ok {
+			return e
+		}
+	}
+	return root
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestOutputFormats(t *testing.T) {
+	defer SetOutput(os.Stderr)
+	defer SetFormat(FormatLogfmt)
+	defer SetLevel(InfoLevel)
+
+	testCases := map[string]struct {
+		format   string
+		level    Level
+		log      func()
+		contains []string
+	}{
+		"Logfmt with fields": {
+			FormatLogfmt, InfoLevel,
+			func() { WithField("request_id", "abc").WithField("route", "/events/{id}").Infof("handled %d", 200) },
+			[]string{"level=info", `msg="handled 200"`, "request_id=abc", "route=/events/{id}"},
+		},
+		"JSON with fields": {
+			FormatJSON, InfoLevel,
+			func() { WithFields(Fields{"status": 404, "user": "test"}).Warnf("not found") },
+			[]string{`"level":"warn"`, `"msg":"not found"`, `"status":404`, `"user":"test"`},
+		},
+		"Debug filtered out": {
+			FormatLogfmt, InfoLevel,
+			func() { Debugf("hidden") },
+			nil,
+		},
+		"Entry from context": {
+			FormatLogfmt, DebugLevel,
+			func() {
+				ctx := NewContext(context.Background(), WithField("request_id", "xyz"))
+				FromContext(ctx).Debugf("query")
+			},
+			[]string{"level=debug", "request_id=xyz"},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			SetOutput(&out)
+			SetFormat(test.format)
+			SetLevel(test.level)
+			test.log()
+
+			if test.contains == nil && out.Len() != 0 {
+				t.Errorf("expected no output, got %q", out.String())
+			}
+			for _, part := range test.contains {
+				if !strings.Contains(out.String(), part) {
+					t.Errorf("output %q does not contain %q", out.String(), part)
+				}
+			}
+			if test.format == FormatJSON {
+				var decoded map[string]interface{}
+				if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+					t.Errorf("output is not valid json : %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Package metrics implements counters and histograms exposed in the
+// Prometheus text exposition format, without depending on a client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are upper bounds in seconds suitable for request and query latencies.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type collector interface {
+	write(w io.Writer)
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	names      map[string]bool
+	collectors []collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: metric " + name + " registered twice")
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText writes every registered metric in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// metricVec keeps one value per distinct combination of label values.
+type metricVec struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.Mutex
+	keys       map[string][]string
+}
+
+func (v *metricVec) key(labelValues []string) string {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %v expects %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	if _, ok := v.keys[key]; !ok {
+		v.keys[key] = append([]string(nil), labelValues...)
+	}
+	return key
+}
+
+func (v *metricVec) sortedKeys() []string {
+	keys := make([]string, 0, len(v.keys))
+	for k := range v.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v *metricVec) header(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", v.name, escapeHelp(v.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.name, kind)
+}
+
+// labels renders label pairs, extra is appended as is, e.g. le="0.5".
+func (v *metricVec) labels(values []string, extra string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, name := range v.labelNames {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+type CounterVec struct {
+	metricVec
+	values map[string]float64
+}
+
+func (r *Registry) NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		metricVec: metricVec{name: name, help: help, labelNames: labelNames, keys: make(map[string][]string)},
+		values:    make(map[string]float64),
+	}
+	r.register(name, c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counter " + c.name + " can not decrease")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[c.key(labelValues)] += delta
+}
+
+// Value returns the current value for the given labels, it is mostly useful in tests.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[strings.Join(labelValues, "\xff")]
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, k := range c.sortedKeys() {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labels(c.keys[k], ""), formatFloat(c.values[k]))
+	}
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type HistogramVec struct {
+	metricVec
+	buckets []float64
+	values  map[string]*histogram
+}
+
+func (r *Registry) NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	h := &HistogramVec{
+		metricVec: metricVec{name: name, help: help, labelNames: labelNames, keys: make(map[string][]string)},
+		buckets:   sorted,
+		values:    make(map[string]*histogram),
+	}
+	r.register(name, h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := h.key(labelValues)
+	hist, ok := h.values[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+	for i, upper := range h.buckets {
+		if value <= upper {
+			hist.counts[i]++
+		}
+	}
+	hist.sum += value
+	hist.count++
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, k := range h.sortedKeys() {
+		values, hist := h.keys[k], h.values[k]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(values, `le="`+formatFloat(upper)+`"`), hist.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(values, `le="+Inf"`), hist.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labels(values, ""), formatFloat(hist.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labels(values, ""), hist.count)
+	}
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
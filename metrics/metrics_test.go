@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("http_requests_total", "Number of requests.", "method", "route")
+	latency := registry.NewHistogramVec("http_request_duration_seconds", "Request latency.", []float64{0.1, 1}, "route")
+
+	requests.Inc("GET", "/events")
+	requests.Inc("GET", "/events")
+	requests.Add(3, "POST", `/say "hi"`)
+	latency.Observe(0.05, "/events")
+	latency.Observe(0.5, "/events")
+	latency.Observe(2, "/events")
+
+	var out bytes.Buffer
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"# HELP http_requests_total Number of requests.",
+		"# TYPE http_requests_total counter",
+		`http_requests_total{method="GET",route="/events"} 2`,
+		`http_requests_total{method="POST",route="/say \"hi\""} 3`,
+		"# TYPE http_request_duration_seconds histogram",
+		`http_request_duration_seconds_bucket{route="/events",le="0.1"} 1`,
+		`http_request_duration_seconds_bucket{route="/events",le="1"} 2`,
+		`http_request_duration_seconds_bucket{route="/events",le="+Inf"} 3`,
+		`http_request_duration_seconds_sum{route="/events"} 2.55`,
+		`http_request_duration_seconds_count{route="/events"} 3`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("output is missing line %q\n got :\n%v", line, out.String())
+		}
+	}
+	if requests.Value("GET", "/events") != 2 {
+		t.Errorf("wrong counter value %v", requests.Value("GET", "/events"))
+	}
+}
+
+func TestDuplicateRegistration(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("duplicate_total", "first")
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering the same name twice did not panic")
+		}
+	}()
+	registry.NewCounterVec("duplicate_total", "second")
+}
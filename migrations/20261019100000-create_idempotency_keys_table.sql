@@ -0,0 +1,17 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(255)                  NOT NULL,
+    request_hash    VARCHAR(64)                   NOT NULL,
+    status          INTEGER                       NOT NULL DEFAULT 0,
+    content_type    VARCHAR(255)                  NOT NULL DEFAULT '',
+    body            BYTEA,
+    created_at      TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    expires_at      TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    PRIMARY KEY (idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS idempotency_keys;
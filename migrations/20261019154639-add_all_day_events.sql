@@ -0,0 +1,23 @@
+-- +migrate Up
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_all_day BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_start_date DATE;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_end_date DATE;
+ALTER TABLE events ALTER COLUMN event_start DROP NOT NULL;
+ALTER TABLE events ALTER COLUMN event_end DROP NOT NULL;
+
+-- all-day events keep only dates, their end date is exclusive
+ALTER TABLE events ADD CONSTRAINT events_all_day_dates CHECK (
+    (event_all_day AND event_start IS NULL AND event_end IS NULL
+        AND event_start_date IS NOT NULL AND event_end_date > event_start_date)
+    OR (NOT event_all_day AND event_start IS NOT NULL AND event_end IS NOT NULL
+        AND event_start_date IS NULL AND event_end_date IS NULL)
+);
+
+-- +migrate Down
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_all_day_dates;
+UPDATE events SET event_start = event_start_date, event_end = event_end_date WHERE event_all_day;
+ALTER TABLE events ALTER COLUMN event_start SET NOT NULL;
+ALTER TABLE events ALTER COLUMN event_end SET NOT NULL;
+ALTER TABLE events DROP COLUMN IF EXISTS event_end_date;
+ALTER TABLE events DROP COLUMN IF EXISTS event_start_date;
+ALTER TABLE events DROP COLUMN IF EXISTS event_all_day;
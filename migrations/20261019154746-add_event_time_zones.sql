@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_start_tz VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_end_tz VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE events DROP COLUMN IF EXISTS event_end_tz;
+ALTER TABLE events DROP COLUMN IF EXISTS event_start_tz;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- events created before owners were recorded keep an empty owner
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_owner VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS events_owner ON events (event_owner);
+
+-- +migrate Down
+DROP INDEX IF EXISTS events_owner;
+ALTER TABLE events DROP COLUMN IF EXISTS event_owner;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- reminders are kept in the order they were given, remind_at is computed from the event times
+CREATE TABLE IF NOT EXISTS event_reminders (
+    reminder_id  BIGSERIAL                     NOT NULL,
+    eventid      BIGINT                        NOT NULL REFERENCES events (eventid) ON DELETE CASCADE,
+    position     INTEGER                       NOT NULL,
+    relative_to  VARCHAR(16)                   NOT NULL,
+    offset_spec  VARCHAR(64)                   NOT NULL,
+    at_time      VARCHAR(16)                   NOT NULL DEFAULT '',
+    channel      VARCHAR(16)                   NOT NULL,
+    remind_at    TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    PRIMARY KEY (reminder_id),
+    UNIQUE (eventid, position)
+);
+
+CREATE INDEX IF NOT EXISTS event_reminders_remind_at ON event_reminders (remind_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS event_reminders;
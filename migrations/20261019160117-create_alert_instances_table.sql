@@ -0,0 +1,19 @@
+-- +migrate Up
+-- one row for every alert time of an event, names and owners are read from the event
+CREATE TABLE IF NOT EXISTS alert_instances (
+    alert_id     BIGSERIAL                     NOT NULL,
+    eventid      BIGINT                        NOT NULL REFERENCES events (eventid) ON DELETE CASCADE,
+    state        VARCHAR(16)                   NOT NULL,
+    alert_at     TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    due_at       TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    deliveries   INTEGER                       NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    PRIMARY KEY (alert_id),
+    UNIQUE (eventid, alert_at)
+);
+
+-- the dispatcher looks for pending and snoozed alerts that are due
+CREATE INDEX IF NOT EXISTS alert_instances_due ON alert_instances (due_at) WHERE state IN ('pending', 'snoozed');
+
+-- +migrate Down
+DROP TABLE IF EXISTS alert_instances;
@@ -0,0 +1,26 @@
+-- +migrate Up
+-- times of day are minutes since midnight in the location of the user, weekday 0 is sunday
+CREATE TABLE IF NOT EXISTS user_working_hours (
+    username      VARCHAR(255)   NOT NULL REFERENCES users (username) ON DELETE CASCADE,
+    weekday       SMALLINT       NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_minute  SMALLINT       NOT NULL,
+    end_minute    SMALLINT       NOT NULL,
+    CHECK (start_minute >= 0 AND start_minute < end_minute AND end_minute <= 1440),
+    PRIMARY KEY (username, weekday, start_minute)
+);
+
+-- an override without minutes makes the whole date unavailable
+CREATE TABLE IF NOT EXISTS user_availability_overrides (
+    username       VARCHAR(255)   NOT NULL REFERENCES users (username) ON DELETE CASCADE,
+    override_date  DATE           NOT NULL,
+    start_minute   SMALLINT,
+    end_minute     SMALLINT,
+    CHECK ((start_minute IS NULL AND end_minute IS NULL) OR
+        (start_minute >= 0 AND start_minute < end_minute AND end_minute <= 1440))
+);
+
+CREATE INDEX IF NOT EXISTS user_availability_overrides_date ON user_availability_overrides (username, override_date);
+
+-- +migrate Down
+DROP TABLE IF EXISTS user_availability_overrides;
+DROP TABLE IF EXISTS user_working_hours;
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- btree_gist lets the exclusion constraint compare resource ids with =, creating it needs a superuser
+-- or a trusted extension on PostgreSQL 13 and later
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS resources (
+    resource_id        BIGSERIAL      NOT NULL,
+    resource_name      VARCHAR(255)   NOT NULL,
+    resource_kind      VARCHAR(16)    NOT NULL,
+    capacity           INTEGER        NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    resource_location  VARCHAR(255)   NOT NULL DEFAULT '',
+    PRIMARY KEY (resource_id),
+    UNIQUE (resource_name)
+);
+
+-- booked copies the times of the event, all-day events book whole UTC days.
+-- A resource can not be booked by two events at overlapping times.
+CREATE TABLE IF NOT EXISTS event_resources (
+    eventid      BIGINT    NOT NULL REFERENCES events (eventid) ON DELETE CASCADE,
+    resource_id  BIGINT    NOT NULL REFERENCES resources (resource_id),
+    booked       TSRANGE   NOT NULL,
+    PRIMARY KEY (eventid, resource_id),
+    CONSTRAINT event_resources_no_double_booking EXCLUDE USING gist (resource_id WITH =, booked WITH &&)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS event_resources;
+DROP TABLE IF EXISTS resources;
@@ -0,0 +1,45 @@
+-- +migrate Up
+-- durations are whole minutes, pages without windows offer the working hours of their owner
+CREATE TABLE IF NOT EXISTS booking_pages (
+    slug                 VARCHAR(64)     NOT NULL,
+    username             VARCHAR(255)    NOT NULL REFERENCES users (username) ON DELETE CASCADE,
+    title                VARCHAR(100)    NOT NULL,
+    description          VARCHAR(1024)   NOT NULL DEFAULT '',
+    duration_minutes     INTEGER         NOT NULL CHECK (duration_minutes > 0),
+    granularity_minutes  INTEGER         NOT NULL CHECK (granularity_minutes > 0),
+    buffer_minutes       INTEGER         NOT NULL DEFAULT 0 CHECK (buffer_minutes >= 0),
+    max_per_day          INTEGER         NOT NULL DEFAULT 0 CHECK (max_per_day >= 0),
+    PRIMARY KEY (slug)
+);
+
+CREATE INDEX IF NOT EXISTS booking_pages_username ON booking_pages (username);
+
+-- times of day are minutes since midnight in the location of the owner, weekday 0 is sunday
+CREATE TABLE IF NOT EXISTS booking_page_windows (
+    slug          VARCHAR(64)   NOT NULL REFERENCES booking_pages (slug) ON DELETE CASCADE,
+    weekday       SMALLINT      NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_minute  SMALLINT      NOT NULL,
+    end_minute    SMALLINT      NOT NULL,
+    CHECK (start_minute >= 0 AND start_minute < end_minute AND end_minute <= 1440),
+    PRIMARY KEY (slug, weekday, start_minute)
+);
+
+-- a booking lives as long as its event, deleting a page keeps the events it created
+CREATE TABLE IF NOT EXISTS bookings (
+    cancel_token   VARCHAR(64)                   NOT NULL,
+    slug           VARCHAR(64)                   NOT NULL REFERENCES booking_pages (slug) ON DELETE CASCADE,
+    eventid        BIGINT                        NOT NULL REFERENCES events (eventid) ON DELETE CASCADE,
+    guest_name     VARCHAR(100)                  NOT NULL,
+    guest_email    VARCHAR(100)                  NOT NULL,
+    booking_start  TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    booking_end    TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    PRIMARY KEY (cancel_token),
+    UNIQUE (eventid)
+);
+
+CREATE INDEX IF NOT EXISTS bookings_slug_start ON bookings (slug, booking_start);
+
+-- +migrate Down
+DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS booking_page_windows;
+DROP TABLE IF EXISTS booking_pages;
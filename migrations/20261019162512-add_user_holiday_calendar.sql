@@ -0,0 +1,6 @@
+-- +migrate Up
+-- the country whose public holidays the user has off, empty for none
+ALTER TABLE users ADD COLUMN IF NOT EXISTS holiday_calendar VARCHAR(8) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN IF EXISTS holiday_calendar;
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS tasks (
+    taskid                 BIGSERIAL                     NOT NULL,
+    username               VARCHAR(255)                  NOT NULL REFERENCES users (username) ON DELETE CASCADE,
+    title                  VARCHAR(255)                  NOT NULL,
+    notes                  VARCHAR(1024)                 NOT NULL DEFAULT '',
+    due                    TIMESTAMP WITHOUT TIME ZONE   NOT NULL,
+    priority               VARCHAR(8)                    NOT NULL,
+    status                 VARCHAR(16)                   NOT NULL,
+    completed_at           TIMESTAMP WITHOUT TIME ZONE,
+    remind_before_minutes  INTEGER                       CHECK (remind_before_minutes > 0),
+    PRIMARY KEY (taskid)
+);
+
+CREATE INDEX IF NOT EXISTS tasks_username_due ON tasks (username, due);
+
+-- alerts remind of either an event or a task
+ALTER TABLE alert_instances ALTER COLUMN eventid DROP NOT NULL;
+ALTER TABLE alert_instances ADD COLUMN IF NOT EXISTS taskid BIGINT REFERENCES tasks (taskid) ON DELETE CASCADE;
+ALTER TABLE alert_instances ADD CONSTRAINT alert_instances_subject CHECK ((eventid IS NULL) <> (taskid IS NULL));
+ALTER TABLE alert_instances ADD CONSTRAINT alert_instances_taskid_alert_at_key UNIQUE (taskid, alert_at);
+
+-- +migrate Down
+DELETE FROM alert_instances WHERE taskid IS NOT NULL;
+ALTER TABLE alert_instances DROP CONSTRAINT IF EXISTS alert_instances_taskid_alert_at_key;
+ALTER TABLE alert_instances DROP CONSTRAINT IF EXISTS alert_instances_subject;
+ALTER TABLE alert_instances DROP COLUMN IF EXISTS taskid;
+ALTER TABLE alert_instances ALTER COLUMN eventid SET NOT NULL;
+DROP TABLE IF EXISTS tasks;
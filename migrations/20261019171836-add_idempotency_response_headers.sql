@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS header JSONB NOT NULL DEFAULT '{}';
+UPDATE idempotency_keys SET header = jsonb_build_object('Content-Type', jsonb_build_array(content_type))
+WHERE content_type <> '';
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS content_type;
+
+-- +migrate Down
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS content_type VARCHAR(255) NOT NULL DEFAULT '';
+UPDATE idempotency_keys SET content_type = COALESCE(header -> 'Content-Type' ->> 0, '');
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS header;
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// alertService tracks the alerts of events and the reminders of tasks from the
+// moment they are set until their owner acknowledges or dismisses them.
+type alertService struct {
+	repository db.AlertsRepository
+}
+
+func newAlertsService(repository db.AlertsRepository) *alertService {
+	return &alertService{repository: repository}
+}
+
+// schedule tracks the alert of an event that was just stored. The event stays
+// stored when this fails, so the failure is only logged. Services built without
+// alerts, like the ones of some tests, have a nil alertService.
+func (s *alertService) schedule(ctx context.Context, e structs.Event) {
+	if s == nil {
+		return
+	}
+	if err := s.track(ctx, e); err != nil {
+		logger.FromContext(ctx).Errorf("scheduling alert of event %d : %v", e.Id, err)
+	}
+}
+
+// track schedules the alert of e, replacing the pending one of its previous version.
+// Events without a future alert only lose their pending alert.
+func (s *alertService) track(ctx context.Context, e structs.Event) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	alertAt := e.Alert
+	if first, ok := structs.FirstReminder(e); ok && alertAt.IsZero() {
+		alertAt = first
+	}
+	if alertAt.Before(now) {
+		alertAt = time.Time{}
+	}
+	return s.repository.Schedule(ctx, structs.AlertInstance{
+		EventId:   e.Id,
+		EventName: e.Name,
+		Owner:     e.Owner,
+		State:     structs.AlertPending,
+		AlertAt:   alertAt.UTC(),
+		DueAt:     alertAt.UTC(),
+		UpdatedAt: now,
+	})
+}
+
+// scheduleTask tracks the reminder of a task that was just stored, like schedule.
+// Finished tasks and tasks without a future reminder only lose their pending alert.
+func (s *alertService) scheduleTask(ctx context.Context, t structs.Task) {
+	if s == nil {
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	alertAt := t.AlertAt()
+	if alertAt.Before(now) {
+		alertAt = time.Time{}
+	}
+	err := s.repository.Schedule(ctx, structs.AlertInstance{
+		TaskId:    t.Id,
+		EventName: t.Title,
+		Owner:     t.Owner,
+		State:     structs.AlertPending,
+		AlertAt:   alertAt.UTC(),
+		DueAt:     alertAt.UTC(),
+		UpdatedAt: now,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("scheduling reminder of task %d : %v", t.Id, err)
+	}
+}
+
+// forgetTask drops the alerts of a deleted task.
+func (s *alertService) forgetTask(ctx context.Context, taskId int) {
+	if s == nil {
+		return
+	}
+	if err := s.repository.CancelTask(ctx, taskId); err != nil {
+		logger.FromContext(ctx).Errorf("forgetting alerts of task %d : %v", taskId, err)
+	}
+}
+
+// forget drops the alerts of a deleted event.
+func (s *alertService) forget(ctx context.Context, eventId int) {
+	if s == nil {
+		return
+	}
+	if err := s.repository.Cancel(ctx, eventId); err != nil {
+		logger.FromContext(ctx).Errorf("forgetting alerts of event %d : %v", eventId, err)
+	}
+}
+
+// GetAlerts lists the alerts of user, all of them when state is empty.
+func (s *alertService) GetAlerts(ctx context.Context, user string, state structs.AlertState, loc time.Location) ([]structs.AlertInstance, error) {
+	alerts, err := s.repository.Get(ctx, structs.AlertParams{Owner: user, State: state})
+	if err != nil {
+		return nil, err
+	}
+	for i := range alerts {
+		alerts[i] = alertInLocation(alerts[i], loc)
+	}
+	return alerts, nil
+}
+
+func (s *alertService) Snooze(ctx context.Context, user string, id int, d time.Duration, loc time.Location) (structs.AlertInstance, error) {
+	return s.act(ctx, user, id, loc, func(a structs.AlertInstance, now time.Time) (structs.AlertInstance, error) {
+		return a.Snooze(now, d)
+	})
+}
+
+func (s *alertService) Acknowledge(ctx context.Context, user string, id int, loc time.Location) (structs.AlertInstance, error) {
+	return s.act(ctx, user, id, loc, structs.AlertInstance.Acknowledge)
+}
+
+func (s *alertService) Dismiss(ctx context.Context, user string, id int, loc time.Location) (structs.AlertInstance, error) {
+	return s.act(ctx, user, id, loc, structs.AlertInstance.Dismiss)
+}
+
+// act applies the reaction of user to their alert with id.
+func (s *alertService) act(ctx context.Context, user string, id int, loc time.Location,
+	reaction func(structs.AlertInstance, time.Time) (structs.AlertInstance, error)) (structs.AlertInstance, error) {
+	a, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return structs.AlertInstance{}, err
+	}
+	// alerts of other users are reported as missing, like their events
+	if a.Owner != user {
+		message := "alert with id [" + fmt.Sprint(id) + "] does not exist"
+		return structs.AlertInstance{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+	}
+	changed, err := reaction(a, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return structs.AlertInstance{}, err
+	}
+	updated, err := s.repository.Update(ctx, changed, a.State)
+	if err != nil {
+		return structs.AlertInstance{}, err
+	}
+	return alertInLocation(updated, loc), nil
+}
+
+// Deliver delivers the alerts due at now. Until there are channels to send them
+// through, delivering an alert means logging it, clients see it in their list of alerts.
+func (s *alertService) Deliver(ctx context.Context, now time.Time) ([]structs.AlertInstance, error) {
+	delivered, err := s.repository.Deliver(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range delivered {
+		fields := logger.Fields{"alert": a.Id, "event": a.EventId, "user": a.Owner}
+		if a.TaskId != 0 {
+			fields = logger.Fields{"alert": a.Id, "task": a.TaskId, "user": a.Owner}
+		}
+		logger.WithFields(fields).Infof("delivered alert of %q", a.EventName)
+	}
+	return delivered, nil
+}
+
+// RunDispatcher delivers due alerts every interval of heartbeat until ctx is done.
+func (s *alertService) RunDispatcher(ctx context.Context, heartbeat *Heartbeat) {
+	ticker := time.NewTicker(heartbeat.Interval())
+	defer ticker.Stop()
+	heartbeat.Beat(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			heartbeat.Beat(now)
+			if _, err := s.Deliver(ctx, time.Now().UTC().Truncate(time.Second)); err != nil {
+				logger.Errorf("delivering alerts : %v", err)
+			}
+		}
+	}
+}
+
+func alertInLocation(a structs.AlertInstance, loc time.Location) structs.AlertInstance {
+	a.AlertAt = a.AlertAt.In(&loc)
+	a.DueAt = a.DueAt.In(&loc)
+	a.UpdatedAt = a.UpdatedAt.In(&loc)
+	return a
+}
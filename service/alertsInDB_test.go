@@ -0,0 +1,8 @@
+package service
+
+import "testing"
+
+func TestAlertsInDB(t *testing.T) {
+	s, loc := newDBService(t, connectDB(t), "UTC")
+	testAlerts(t, s, loc)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestAlertsOnMap(t *testing.T) {
+	s, loc := newMapService(t, "UTC")
+	testAlerts(t, s, loc)
+}
+
+// testAlerts follows the alerts of two events through their states, s has to be empty.
+func testAlerts(t *testing.T, s *Service, loc time.Location) {
+	ctx := context.Background()
+	start := time.Now().UTC().Add(2 * time.Hour).Truncate(time.Minute)
+	event, err := s.Events.AddEvent(ctx, loc, structs.Event{
+		Name: "Dentist", Start: start, End: start.Add(time.Hour), Alert: start.Add(-time.Hour), Owner: "ann",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := s.Events.AddEvent(ctx, loc, structs.Event{
+		Name: "Gym", Start: start, End: start.Add(time.Hour), Alert: start.Add(-time.Hour), Owner: "bob",
+	})
+
+	pending, err := s.Alerts.GetAlerts(ctx, "ann", structs.AlertPending, loc)
+	if err != nil || len(pending) != 1 || pending[0].EventId != event.Id || !pending[0].DueAt.Equal(event.Alert) {
+		t.Fatalf("expected one pending alert of the event, got %v %v", pending, err)
+	}
+	id := pending[0].Id
+
+	if _, err = s.Alerts.Acknowledge(ctx, "ann", id, loc); structs.KindOf(err) != structs.KindConflict {
+		t.Errorf("a pending alert can not be acknowledged, got %v", err)
+	}
+
+	// moving the alert moves the pending instance
+	event.Alert = start.Add(-30 * time.Minute)
+	if _, err = s.Events.UpdateEvent(ctx, event.Id, "ann", event, loc); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ = s.Alerts.GetAlerts(ctx, "ann", structs.AlertPending, loc)
+	if len(pending) != 1 || !pending[0].AlertAt.Equal(event.Alert) {
+		t.Fatalf("expected the pending alert to follow the event, got %v", pending)
+	}
+	id = pending[0].Id
+
+	delivered, err := s.Alerts.Deliver(ctx, event.Alert.Add(time.Second))
+	if err != nil || len(delivered) != 2 {
+		t.Fatalf("expected both alerts to be delivered, got %v %v", delivered, err)
+	}
+	if delivered, _ = s.Alerts.Deliver(ctx, event.Alert.Add(time.Minute)); len(delivered) != 0 {
+		t.Errorf("alerts were delivered twice : %v", delivered)
+	}
+
+	snoozed, err := s.Alerts.Snooze(ctx, "ann", id, 10*time.Minute, loc)
+	if err != nil || snoozed.State != structs.AlertSnoozed {
+		t.Fatalf("expected the alert to be snoozed, got %v %v", snoozed, err)
+	}
+	if _, err = s.Alerts.Snooze(ctx, "ann", id, -time.Minute, loc); structs.KindOf(err) != structs.KindValidation {
+		t.Errorf("expected a negative snooze to be rejected, got %v", err)
+	}
+	if delivered, _ = s.Alerts.Deliver(ctx, snoozed.DueAt.Add(-time.Second)); len(delivered) != 0 {
+		t.Errorf("snoozed alert was delivered early : %v", delivered)
+	}
+	if delivered, _ = s.Alerts.Deliver(ctx, snoozed.DueAt); len(delivered) != 1 || delivered[0].Deliveries != 2 {
+		t.Errorf("expected the snoozed alert to be delivered again, got %v", delivered)
+	}
+
+	if _, err = s.Alerts.Acknowledge(ctx, "bob", id, loc); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("alerts of other users should not be found, got %v", err)
+	}
+	if acked, err := s.Alerts.Acknowledge(ctx, "ann", id, loc); err != nil || acked.State != structs.AlertAcknowledged {
+		t.Errorf("expected the alert to be acknowledged, got %v %v", acked, err)
+	}
+	if _, err = s.Alerts.Dismiss(ctx, "ann", id, loc); structs.KindOf(err) != structs.KindConflict {
+		t.Errorf("an acknowledged alert can not be dismissed, got %v", err)
+	}
+
+	// a delivered alert is not scheduled again when its event is saved unchanged
+	if _, err = s.Events.UpdateEvent(ctx, event.Id, "ann", event, loc); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := s.Alerts.GetAlerts(ctx, "ann", "", loc); len(all) != 1 || all[0].State != structs.AlertAcknowledged {
+		t.Errorf("expected only the acknowledged alert, got %v", all)
+	}
+
+	if err = s.Events.DeleteEvent(ctx, other.Id, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := s.Alerts.GetAlerts(ctx, "bob", "", loc); len(all) != 0 {
+		t.Errorf("alerts of a deleted event were kept : %v", all)
+	}
+}
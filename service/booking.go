@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// bookingService publishes booking pages and books their slots for guests without an account.
+type bookingService struct {
+	repository db.BookingRepository
+	users      *usersService
+	events     *eventService
+	alerts     *alertService
+}
+
+func newBookingService(repository db.BookingRepository) *bookingService {
+	return &bookingService{repository: repository}
+}
+
+// SavePage publishes the page of owner under slug, replacing the one they published before.
+func (s *bookingService) SavePage(ctx context.Context, owner string, slug string, p structs.BookingPage) (structs.BookingPage, error) {
+	normalized, err := structs.NormalizeBookingPage(slug, p)
+	if err != nil {
+		return structs.BookingPage{}, err
+	}
+	loc, err := s.users.GetUserLocation(ctx, owner)
+	if err != nil {
+		return structs.BookingPage{}, err
+	}
+	normalized.Owner = owner
+	saved, err := s.repository.SavePage(ctx, normalized)
+	if err != nil {
+		return structs.BookingPage{}, err
+	}
+	saved.Timezone = loc.String()
+	return saved, nil
+}
+
+func (s *bookingService) GetPages(ctx context.Context, owner string) ([]structs.BookingPage, error) {
+	loc, err := s.users.GetUserLocation(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := s.repository.GetPages(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pages {
+		pages[i].Timezone = loc.String()
+	}
+	return pages, nil
+}
+
+func (s *bookingService) DeletePage(ctx context.Context, owner string, slug string) error {
+	return s.repository.DeletePage(ctx, owner, slug)
+}
+
+// OpenSlots lists the slots of the page with slug that can be booked in window,
+// with times in the location of the visitor, or of the owner when it is nil.
+func (s *bookingService) OpenSlots(ctx context.Context, slug string, window structs.Interval, visitor *time.Location) (structs.OpenSlots, error) {
+	page, err := s.repository.GetPage(ctx, slug)
+	if err != nil {
+		return structs.OpenSlots{}, err
+	}
+	loc, hours, err := s.hours(ctx, page)
+	if err != nil {
+		return structs.OpenSlots{}, err
+	}
+	// events closer to window than the buffer still take the slots next to them
+	_, _, buffer := page.Durations()
+	events, err := s.events.GetEventsOfTheDay(ctx, structs.EventParams{
+		Owner: page.Owner,
+		From:  window.Start.Add(-buffer),
+		To:    window.End.Add(buffer),
+	}, loc)
+	if err != nil {
+		return structs.OpenSlots{}, err
+	}
+	// bookings of the days window starts and ends in count against their limit
+	bookings, err := s.repository.GetBookings(ctx, slug, window.Start.Add(-24*time.Hour), window.End.Add(24*time.Hour))
+	if err != nil {
+		return structs.OpenSlots{}, err
+	}
+
+	if visitor == nil {
+		visitor = &loc
+	}
+	slots := structs.FindOpenSlots(page, hours, &loc, events, bookings, window.Start, window.End, time.Now().UTC())
+	for i := range slots {
+		slots[i].Start, slots[i].End = slots[i].Start.In(visitor), slots[i].End.In(visitor)
+	}
+	return structs.OpenSlots{
+		Title:       page.Title,
+		Description: page.Description,
+		Duration:    page.Duration,
+		Timezone:    visitor.String(),
+		Slots:       slots,
+	}, nil
+}
+
+// Book creates the event of a guest in the calendar of the owner of the page. The
+// slot is checked again while the booking is stored, so two guests never get it both.
+func (s *bookingService) Book(ctx context.Context, slug string, r structs.BookingRequest) (structs.Booking, error) {
+	if err := structs.Validate(r, "validator : invalid data format"); err != nil {
+		return structs.Booking{}, err
+	}
+	page, err := s.repository.GetPage(ctx, slug)
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	loc, hours, err := s.hours(ctx, page)
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	duration, _, buffer := page.Durations()
+	start := r.Start.UTC()
+	local := start.In(&loc)
+	day := structs.Interval{Start: time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, &loc)}
+	day.End = day.Start.AddDate(0, 0, 1)
+
+	token, err := newCancelToken()
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	booking := structs.Booking{
+		Slug:        slug,
+		Name:        r.Name,
+		Email:       r.Email,
+		Start:       start,
+		End:         start.Add(duration),
+		CancelToken: token,
+	}
+	check := func(events []structs.Event, bookings []structs.Booking) error {
+		for _, slot := range structs.FindOpenSlots(page, hours, &loc, events, bookings, day.Start, day.End, time.Now().UTC()) {
+			if slot.Start.Equal(start) {
+				return nil
+			}
+		}
+		return structs.SlotTaken(start)
+	}
+	window := structs.Interval{Start: day.Start.Add(-buffer), End: day.End.Add(buffer)}
+	return s.repository.Book(ctx, window, booking, structs.BookedEvent(page, r, booking.End), check)
+}
+
+// Cancel drops the booking with token and its event.
+func (s *bookingService) Cancel(ctx context.Context, slug string, token string) (structs.Booking, error) {
+	b, err := s.repository.Cancel(ctx, slug, token)
+	if err != nil {
+		return structs.Booking{}, err
+	}
+	s.alerts.forget(ctx, b.EventId)
+	b.CancelToken = ""
+	return b, nil
+}
+
+// hours returns the location of the owner of p and the hours p offers in it, the
+// working hours of the owner for pages without windows. Either way the public
+// holidays the owner has off are not offered.
+func (s *bookingService) hours(ctx context.Context, p structs.BookingPage) (time.Location, structs.Availability, error) {
+	loc, err := s.users.GetUserLocation(ctx, p.Owner)
+	if err != nil {
+		return time.Location{}, structs.Availability{}, err
+	}
+	hours, err := s.users.GetAvailability(ctx, p.Owner)
+	if err != nil {
+		return time.Location{}, structs.Availability{}, err
+	}
+	if len(p.Windows) > 0 {
+		return loc, structs.Availability{Weekly: p.Windows, Holidays: hours.Holidays}, nil
+	}
+	return loc, hours, nil
+}
+
+// newCancelToken returns a random token that is only known to the guest.
+func newCancelToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", structs.WrapError(structs.KindInternal, err)
+	}
+	return hex.EncodeToString(token), nil
+}
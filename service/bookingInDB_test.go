@@ -0,0 +1,10 @@
+package service
+
+import "testing"
+
+// Bookings of Postgres lock the page and its owner with FOR UPDATE in one transaction.
+func TestBookingInDB(t *testing.T) {
+	s, _ := newDBService(t, connectDB(t), "UTC")
+	testBooking(t, s)
+	testConcurrentBookings(t, s)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestBookingOnMap(t *testing.T) {
+	s, _ := newMapService(t, "UTC")
+	testBooking(t, s)
+	testConcurrentBookings(t, s)
+}
+
+// testBooking books the slots of a page of ann, who has to live in UTC.
+func testBooking(t *testing.T, s *Service) {
+	ctx := context.Background()
+	page, err := s.Booking.SavePage(ctx, "ann", "intro-call", structs.BookingPage{
+		Title:       "Intro call",
+		Duration:    "30m",
+		Granularity: "15m",
+		Buffer:      "15m",
+		MaxPerDay:   2,
+		Windows:     map[string][]structs.TimeRange{"Monday": {{Start: "14:00", End: "15:00"}, {Start: "09:00", End: "11:00"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Slug != "intro-call" || page.Timezone != "UTC" {
+		t.Errorf("unexpected page %+v", page)
+	}
+	if _, err = s.Booking.SavePage(ctx, "ann", "No Spaces", page); structs.KindOf(err) != structs.KindValidation {
+		t.Errorf("expected an invalid slug to be rejected, got %v", err)
+	}
+
+	// a monday, the event and the buffer around it leave 10:15 and 10:30 in the morning
+	monday := time.Date(2030, time.January, 7, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return monday.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	if _, err = s.Events.AddEvent(ctx, *time.UTC, structs.Event{Name: "Standup", Start: at(9, 30), End: at(10, 0), Owner: "ann"}); err != nil {
+		t.Fatal(err)
+	}
+	day := structs.Interval{Start: monday, End: monday.AddDate(0, 0, 1)}
+	openAt := func() []time.Time {
+		t.Helper()
+		open, err := s.Booking.OpenSlots(ctx, "intro-call", day, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		starts := make([]time.Time, 0, len(open.Slots))
+		for _, slot := range open.Slots {
+			starts = append(starts, slot.Start)
+		}
+		return starts
+	}
+	if starts := openAt(); !sameTimes(starts, []time.Time{at(10, 15), at(10, 30), at(14, 0), at(14, 15), at(14, 30)}) {
+		t.Errorf("unexpected open slots %v", starts)
+	}
+	// the event ends where the window starts, its buffer still takes the first slot
+	after, err := s.Booking.OpenSlots(ctx, "intro-call", structs.Interval{Start: at(10, 0), End: at(11, 0)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after.Slots) != 2 || !after.Slots[0].Start.Equal(at(10, 15)) {
+		t.Errorf("expected the slots of 10:15 and 10:30, got %v", after.Slots)
+	}
+
+	guest := structs.BookingRequest{Name: "Bob", Email: "bob@example.com", Notes: "about the offer"}
+	book := func(start time.Time) (structs.Booking, error) {
+		r := guest
+		r.Start = start
+		return s.Booking.Book(ctx, "intro-call", r)
+	}
+	morning, err := book(at(10, 15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if morning.CancelToken == "" || !morning.End.Equal(at(10, 45)) {
+		t.Errorf("unexpected booking %+v", morning)
+	}
+	event, err := s.Events.GetById(ctx, morning.EventId, "ann", *time.UTC)
+	if err != nil || event.Name != "Intro call with Bob" || event.Owner != "ann" {
+		t.Errorf("expected the booking to create an event of ann, got %+v, %v", event, err)
+	}
+
+	// the bookings build on each other, so they run in order
+	steps := []struct {
+		name  string
+		start time.Time
+		kind  structs.ErrorKind
+	}{
+		{name: "booked slot", start: at(10, 15), kind: structs.KindConflict},
+		{name: "overlapping slot", start: at(10, 30), kind: structs.KindConflict},
+		{name: "start between slots", start: at(14, 5), kind: structs.KindConflict},
+		{name: "outside of the windows", start: at(12, 0), kind: structs.KindConflict},
+		{name: "afternoon slot", start: at(14, 0)},
+		{name: "over the limit of a day", start: at(14, 30), kind: structs.KindConflict},
+	}
+	for _, tc := range steps {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := book(tc.start)
+			if tc.kind == "" && err != nil {
+				t.Errorf("expected the slot to be booked, got %v", err)
+			}
+			if tc.kind != "" && structs.KindOf(err) != tc.kind {
+				t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+			}
+		})
+	}
+	if starts := openAt(); len(starts) != 0 {
+		t.Errorf("expected no open slots on a full day, got %v", starts)
+	}
+
+	if _, err = s.Booking.Cancel(ctx, "intro-call", "unknown"); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("expected an unknown token to be rejected, got %v", err)
+	}
+	if _, err = s.Booking.Cancel(ctx, "intro-call", morning.CancelToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Events.GetById(ctx, morning.EventId, "ann", *time.UTC); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("expected the event of a cancelled booking to be deleted, got %v", err)
+	}
+	if _, err = book(at(10, 30)); err != nil {
+		t.Errorf("expected the slot to be open after the cancellation, got %v", err)
+	}
+}
+
+// testConcurrentBookings books the same slot from several guests at once, only one of them gets it.
+func testConcurrentBookings(t *testing.T, s *Service) {
+	ctx := context.Background()
+	_, err := s.Booking.SavePage(ctx, "ann", "office-hours", structs.BookingPage{
+		Title:    "Office hours",
+		Duration: "30m",
+		Windows:  map[string][]structs.TimeRange{"monday": {{Start: "16:00", End: "17:00"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2030, time.January, 14, 16, 0, 0, 0, time.UTC)
+
+	const guests = 8
+	errs := make(chan error, guests)
+	for i := 0; i < guests; i++ {
+		go func(i int) {
+			_, err := s.Booking.Book(ctx, "office-hours", structs.BookingRequest{
+				Start: start, Name: fmt.Sprintf("Guest %d", i), Email: fmt.Sprintf("guest%d@example.com", i),
+			})
+			errs <- err
+		}(i)
+	}
+	booked := 0
+	for i := 0; i < guests; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			booked++
+		case structs.KindOf(err) != structs.KindConflict:
+			t.Errorf("expected the slot to be taken, got %v", err)
+		}
+	}
+	if booked != 1 {
+		t.Errorf("expected exactly one guest to book the slot, %d did", booked)
+	}
+}
+
+func sameTimes(a []time.Time, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,7 +1,8 @@
 package service
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"sort"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 
 type eventService struct {
 	repository db.EventsRepository
+	alerts     *alertService
+	resources  *resourceService
 }
 
 func newEventsService(repository db.EventsRepository) *eventService {
@@ -20,81 +23,184 @@ func newEventsService(repository db.EventsRepository) *eventService {
 	return &s
 }
 
-func (s *eventService) AddEvent(loc time.Location, newEvent structs.Event) (structs.Event, error) {
+func (s *eventService) AddEvent(ctx context.Context, loc time.Location, newEvent structs.Event) (structs.Event, error) {
 	approved, err := s.checkData(newEvent)
 	if !approved {
 		return structs.Event{}, err
 	}
+	newEvent, err = structs.ScheduleReminders(newEvent, &loc)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	if newEvent, err = s.resources.book(ctx, newEvent); err != nil {
+		return structs.Event{}, err
+	}
 	// log.Println("UTC ??? " + newEvent.Start.String())
-	returnedEvent, err := s.repository.Add(newEvent)
+	returnedEvent, err := s.repository.Add(ctx, newEvent)
 	if err != nil {
 		return structs.Event{}, err
 	}
+	s.alerts.schedule(ctx, returnedEvent)
 
-	returnedEvent.Start = newEvent.Start.In(&loc)
-	returnedEvent.End = newEvent.End.In(&loc)
-	if returnedEvent.Alert != (time.Time{}) {
-		returnedEvent.Alert = newEvent.Alert.In(&loc)
-	}
+	return present(returnedEvent, loc), nil
+}
 
-	return returnedEvent, nil
+// QuickAdd adds the event described by text for user, read relative to now in loc.
+// A dry run only interprets the text. Repeating events can only be dry run, as adding
+// their first occurrence alone would not be what the text asks for.
+func (s *eventService) QuickAdd(ctx context.Context, user string, loc time.Location, text string, dryRun bool) (structs.QuickAddResult, error) {
+	interpretation, err := structs.ParseQuickEvent(text, &loc, time.Now())
+	if err != nil {
+		return structs.QuickAddResult{}, err
+	}
+	event, err := structs.CreateEvent(loc, interpretation.Event)
+	if err != nil {
+		return structs.QuickAddResult{}, err
+	}
+	result := structs.QuickAddResult{QuickInterpretation: interpretation, DryRun: dryRun}
+	if dryRun {
+		return result, nil
+	}
+	if len(interpretation.RepeatsOn) > 0 {
+		return structs.QuickAddResult{}, structs.NewError(structs.KindUnprocessable,
+			"repeating events can not be added, dry run the text or describe a single occurrence")
+	}
+	event.Owner = user
+	added, err := s.AddEvent(ctx, loc, event)
+	if err != nil {
+		return structs.QuickAddResult{}, err
+	}
+	result.Event = &added
+	return result, nil
 }
 
-func (s *eventService) DeleteEvent(id int, user string) error {
-	foundEvent, err := s.repository.GetByID(id)
+func (s *eventService) DeleteEvent(ctx context.Context, id int, user string) error {
+	foundEvent, err := s.eventOf(ctx, id, user)
 	if err != nil {
 		return err
 	}
-	s.repository.Delete(foundEvent)
+	if err = s.repository.Delete(ctx, foundEvent); err != nil {
+		return err
+	}
+	s.alerts.forget(ctx, id)
 	return nil
 }
 
-func (s *eventService) GetById(id int, loc time.Location) (structs.Event, error) {
+func (s *eventService) GetById(ctx context.Context, id int, user string, loc time.Location) (structs.Event, error) {
 
-	returnedEvent, err := s.repository.GetByID(id)
+	returnedEvent, err := s.eventOf(ctx, id, user)
 	if err != nil {
 		return structs.Event{}, err
 	}
 	// fmt.Println("returned start time before appliyng location : " + returnedEvent.Start.String())
-	returnedEvent.Start = returnedEvent.Start.In(&loc)
-	returnedEvent.End = returnedEvent.End.In(&loc)
-	if returnedEvent.Alert != (time.Time{}) {
-		returnedEvent.Alert = returnedEvent.Alert.In(&loc)
-	}
-	return returnedEvent, nil
+	return present(returnedEvent, loc), nil
 }
 
-func (s *eventService) UpdateEvent(id int, newEvent structs.Event, loc time.Location) (updated structs.Event, err error) {
+func (s *eventService) UpdateEvent(ctx context.Context, id int, user string, newEvent structs.Event, loc time.Location) (updated structs.Event, err error) {
 	approved, err := s.checkData(newEvent)
 	if !approved {
 		return structs.Event{}, err
 	}
-	returnedEvent, err := s.repository.Update(id, newEvent)
+	if _, err = s.eventOf(ctx, id, user); err != nil {
+		return structs.Event{}, err
+	}
+	// reminders are relative, so they are recalculated for the new times
+	newEvent, err = structs.ScheduleReminders(newEvent, &loc)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	if newEvent, err = s.resources.book(ctx, newEvent); err != nil {
+		return structs.Event{}, err
+	}
+	returnedEvent, err := s.repository.Update(ctx, id, newEvent)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	s.alerts.schedule(ctx, returnedEvent)
+	return present(returnedEvent, loc), nil
+}
+
+// PatchEvent lets patch change the event as it is seen from loc, times of events
+// with their own time zones are written in those zones. The patched event is
+// interpreted like a new one, so it has to pass the same checks.
+func (s *eventService) PatchEvent(ctx context.Context, id int, user string, loc time.Location,
+	patch func(structs.EventCreation) (structs.EventCreation, error)) (structs.Event, error) {
+	// read without the alert derived from reminders, patching must not make it explicit
+	stored, err := s.eventOf(ctx, id, user)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	original := structs.CreationOf(inLocation(stored, loc), loc)
+	patched, err := patch(original)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	event, err := structs.CreateEvent(loc, patched)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	return s.UpdateEvent(ctx, id, user, keepUnpatchedTimes(event, stored, original, patched), loc)
+}
 
-	returnedEvent.Start = newEvent.Start.In(&loc)
-	returnedEvent.End = newEvent.End.In(&loc)
-	if returnedEvent.Alert != (time.Time{}) {
-		returnedEvent.Alert = returnedEvent.Alert.In(time.Local)
+// eventOf reads the event with id, events of other users are reported as missing
+// so that their ids tell nothing.
+func (s *eventService) eventOf(ctx context.Context, id int, user string) (structs.Event, error) {
+	e, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return structs.Event{}, err
 	}
-	return returnedEvent, err
+	if !e.BelongsTo(user) {
+		message := "event with id [" + fmt.Sprint(id) + "] does not exist"
+		return structs.Event{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+	}
+	return e, nil
 }
 
-func (s *eventService) GetEventsOfTheDay(p structs.EventParams, loc time.Location) ([]structs.Event, error) {
+// keepUnpatchedTimes gives the times patched left as they were in original their stored
+// instants back. Reading their wall clocks again would move a time repeated by a DST
+// overlap to its first occurrence.
+func keepUnpatchedTimes(event structs.Event, stored structs.Event, original structs.EventCreation,
+	patched structs.EventCreation) structs.Event {
+	if original.AllDay || patched.AllDay {
+		return event
+	}
+	// the end is in the zone of the start, unless it has a zone of its own
+	endZone := func(c structs.EventCreation) string {
+		if c.EndTZ == "" {
+			return c.StartTZ
+		}
+		return c.EndTZ
+	}
+	if patched.Start.Equal(original.Start) && patched.StartTZ == original.StartTZ {
+		event.Start = stored.Start
+	}
+	if patched.End.Equal(original.End) && endZone(patched) == endZone(original) {
+		event.End = stored.End
+	}
+	if patched.Alert.Equal(original.Alert) && patched.StartTZ == original.StartTZ {
+		event.Alert = stored.Alert
+	}
+	return event
+}
+
+func (s *eventService) GetEventsOfTheDay(ctx context.Context, p structs.EventParams, loc time.Location) ([]structs.Event, error) {
 	result := make([]structs.Event, 0)
 	if p.Day < 0 || p.Week < 0 || p.Month < 0 || p.Year < 0 {
-		return result, errors.New("bad date parameters")
+		return result, structs.NewValidationError("bad date parameters")
 	}
-	receivedEvents, err := s.repository.Get(p)
+	receivedEvents, err := s.repository.Get(ctx, p)
 	if err != nil {
 		return result, err
 	}
 	for _, event := range receivedEvents {
-		event.Start = event.Start.In(&loc)
-		event.End = event.End.In(&loc)
-		if event.Alert != (time.Time{}) {
-			event.Alert = event.Alert.In(&loc)
+		result = append(result, present(event, loc))
+	}
+	if p.Holidays != "" {
+		holidays, err := holidayEvents(p, loc)
+		if err != nil {
+			return make([]structs.Event, 0), err
 		}
-		result = append(result, event)
+		result = append(result, holidays...)
 	}
 
 	if p.Sorting {
@@ -119,8 +225,40 @@ func (s *eventService) checkData(newEvent structs.Event) (bool, error) {
 	if newEvent.End == (time.Time{}) {
 		return false, &structs.MandatoryFieldError{FieldName: "end"}
 	}
+	if newEvent.AllDay && !newEvent.End.After(newEvent.Start) {
+		return false, structs.NewValidationError("end date of an all-day event is exclusive",
+			structs.FieldError{Field: "end", Message: "must be after start"})
+	}
 	if newEvent.Start.Unix() > newEvent.End.Unix() {
-		return false, errors.New("end of the event is ahead of the start")
+		return false, structs.NewValidationError("end of the event is ahead of the start",
+			structs.FieldError{Field: "end", Message: "must not be before start"})
 	}
 	return true, nil
 }
+
+// inLocation shows the times of e in loc. Dates of all-day events are the
+// same everywhere, so they are left as they are.
+func inLocation(e structs.Event, loc time.Location) structs.Event {
+	if !e.AllDay {
+		e.Start = e.Start.In(&loc)
+		e.End = e.End.In(&loc)
+	}
+	if e.Alert != (time.Time{}) {
+		e.Alert = e.Alert.In(&loc)
+	}
+	e.Reminders = structs.CopyReminders(e.Reminders)
+	for i := range e.Reminders {
+		e.Reminders[i].RemindAt = e.Reminders[i].RemindAt.In(&loc)
+	}
+	e.Resources = structs.CopyResources(e.Resources)
+	return e
+}
+
+// present is inLocation for events that are returned to clients. Clients that
+// only know the alert field see the first reminder in it.
+func present(e structs.Event, loc time.Location) structs.Event {
+	if first, ok := structs.FirstReminder(e); ok && e.Alert.IsZero() {
+		e.Alert = first
+	}
+	return inLocation(e, loc)
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -113,22 +115,22 @@ func TestAdd(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			newEvent, err := testService.AddEvent(*time.Local, test.event)
+			newEvent, err := testService.AddEvent(context.Background(), *time.Local, test.event)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
 			}
 
-			if (newEvent == (structs.Event{}) && err == nil) || (newEvent == test.event && err != nil) {
+			if (reflect.DeepEqual(newEvent, structs.Event{}) && err == nil) || (reflect.DeepEqual(newEvent, test.event) && err != nil) {
 				t.Errorf("event was added incorrectly")
 			}
 
-			newEventFromRepo, err2 := testService.GetById(newEvent.Id, *time.Local)
+			newEventFromRepo, err2 := testService.GetById(context.Background(), newEvent.Id, "testUsername", *time.Local)
 			if err2 != nil && err == nil {
 				t.Errorf("event was added incorrectly")
 			}
 
-			if newEventFromRepo != (structs.Event{}) && err == nil && !structs.CompareTwoEvents(newEvent, newEventFromRepo) {
+			if !reflect.DeepEqual(newEventFromRepo, structs.Event{}) && err == nil && !structs.CompareTwoEvents(newEvent, newEventFromRepo) {
 				t.Errorf("event was added incorrectly")
 			}
 		})
@@ -141,7 +143,7 @@ func TestUpdateEvent(t *testing.T) {
 	var testRepo, _ = db.NewArrayRepository()
 	var testService = newEventsService(testRepo)
 
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now(),
@@ -238,14 +240,14 @@ func TestUpdateEvent(t *testing.T) {
 	}
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			updatedEvent, err := testService.UpdateEvent(test.id, test.event, *time.Local)
-			if updatedEvent == (structs.Event{}) && err == nil {
+			updatedEvent, err := testService.UpdateEvent(context.Background(), test.id, "testUsername", test.event, *time.Local)
+			if reflect.DeepEqual(updatedEvent, structs.Event{}) && err == nil {
 				t.Errorf("no errors in update function occured, but returned result is an empty struct")
 			}
 
 			test.event.Id = testService.repository.GetLastUsedId()
 
-			wasUpdated, err2 := testService.GetById(test.id, *time.Local)
+			wasUpdated, err2 := testService.GetById(context.Background(), test.id, "testUsername", *time.Local)
 			// check if event was indeed updated
 			if err2 == nil && err == nil && !structs.CompareTwoEvents(updatedEvent, wasUpdated) {
 				t.Errorf("event with id [%v] was not updated correctly", test.id)
@@ -272,7 +274,7 @@ func TestGetEvent(t *testing.T) {
 	var testRepo, _ = db.NewArrayRepository()
 	var testService = newEventsService(testRepo)
 
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now(),
@@ -332,20 +334,20 @@ func TestGetEvent(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			events, err := testService.GetEventsOfTheDay(test.params, *time.Local)
+			events, err := testService.GetEventsOfTheDay(context.Background(), test.params, *time.Local)
 			for _, v := range events {
 
-				if (v == structs.Event{} && err == nil) {
+				if (reflect.DeepEqual(v, structs.Event{}) && err == nil) {
 					t.Errorf("result returned by get function is incorrect")
 				}
 
-				event, err2 := testService.GetById(v.Id, *time.Local)
-				if v != (structs.Event{}) && err2 != nil {
+				event, err2 := testService.GetById(context.Background(), v.Id, "testUsername", *time.Local)
+				if !reflect.DeepEqual(v, structs.Event{}) && err2 != nil {
 					t.Errorf("event with id [%v] does not exist", v.Id)
 				}
 
 				resultMatchesInputParams := false
-				events, err := testService.repository.Get(test.params)
+				events, err := testService.repository.Get(context.Background(), test.params)
 				if err != nil {
 					t.Errorf(err.Error())
 				}
@@ -370,7 +372,7 @@ func TestDeleteEvent(t *testing.T) {
 	var testRepo, _ = db.NewArrayRepository()
 	var testService = newEventsService(testRepo)
 
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now(),
@@ -387,12 +389,12 @@ func TestDeleteEvent(t *testing.T) {
 	}
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			err := testService.DeleteEvent(test.id, "testUsername")
+			err := testService.DeleteEvent(context.Background(), test.id, "testUsername")
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("got %q, wanted %q", err, test.errorMessage)
 			}
 
-			_, err2 := testService.GetById(test.id, *time.Local)
+			_, err2 := testService.GetById(context.Background(), test.id, "testUsername", *time.Local)
 
 			if !errors.Is(err2, structs.ErrNoMatch) && err == nil {
 				t.Errorf("event with id [" + fmt.Sprint(test.id) + "] was not deleted")
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -12,8 +14,7 @@ import (
 )
 
 func TestAddToDB(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -125,22 +126,22 @@ func TestAddToDB(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			newEvent, err := testService.AddEvent(*time.Local, test.event)
+			newEvent, err := testService.AddEvent(context.Background(), *time.Local, test.event)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
 			}
 			test.event.Id = testRepo.GetLastUsedId()
-			if (newEvent == (structs.Event{}) && err == nil) || (structs.CompareTwoEvents(newEvent, test.event) && err != nil) {
+			if (reflect.DeepEqual(newEvent, structs.Event{}) && err == nil) || (structs.CompareTwoEvents(newEvent, test.event) && err != nil) {
 				t.Errorf("event was added incorrectly:\n wanted %v\n got %v\n", test.event, newEvent)
 			}
 
-			newEventFromRepo, err2 := testService.GetById(newEvent.Id, *time.Local)
+			newEventFromRepo, err2 := testService.GetById(context.Background(), newEvent.Id, "testUsername", *time.Local)
 			if err2 != nil && err == nil {
 				t.Errorf("freshy added event was not found in the db")
 			}
 
-			if err2 == nil && err == nil && newEventFromRepo != (structs.Event{}) && !structs.CompareTwoEvents(newEvent, newEventFromRepo) {
+			if err2 == nil && err == nil && !reflect.DeepEqual(newEventFromRepo, structs.Event{}) && !structs.CompareTwoEvents(newEvent, newEventFromRepo) {
 				t.Errorf("event was added incorrectly:\n wanted %v\n got %v\n", newEvent, newEventFromRepo)
 			}
 		})
@@ -150,8 +151,7 @@ func TestAddToDB(t *testing.T) {
 }
 
 func TestUpdateEventInDB(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -165,7 +165,7 @@ func TestUpdateEventInDB(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now().In(time.UTC),
@@ -262,14 +262,14 @@ func TestUpdateEventInDB(t *testing.T) {
 	}
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			updatedEvent, err := testService.UpdateEvent(test.id, test.event, *time.Local)
-			if updatedEvent == (structs.Event{}) && err == nil {
+			updatedEvent, err := testService.UpdateEvent(context.Background(), test.id, "testUsername", test.event, *time.Local)
+			if reflect.DeepEqual(updatedEvent, structs.Event{}) && err == nil {
 				t.Errorf("no errors in update function occured, but returned result is an empty struct")
 			}
 
 			test.event.Id = testService.repository.GetLastUsedId()
 
-			wasUpdated, err2 := testService.GetById(test.id, *time.Local)
+			wasUpdated, err2 := testService.GetById(context.Background(), test.id, "testUsername", *time.Local)
 			// check if event was indeed updated
 			if err2 == nil && err == nil && !structs.CompareTwoEvents(updatedEvent, wasUpdated) {
 				t.Errorf("event with id [%v] was not updated correctly", test.id)
@@ -293,8 +293,7 @@ func TestUpdateEventInDB(t *testing.T) {
 }
 
 func TestGetEventFromDB(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -308,7 +307,7 @@ func TestGetEventFromDB(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now().In(time.UTC),
@@ -368,20 +367,20 @@ func TestGetEventFromDB(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			events, err := testService.GetEventsOfTheDay(test.params, *time.Local)
+			events, err := testService.GetEventsOfTheDay(context.Background(), test.params, *time.Local)
 			for _, v := range events {
 
-				if (v == structs.Event{} && err == nil) {
+				if (reflect.DeepEqual(v, structs.Event{}) && err == nil) {
 					t.Errorf("result returned by get function is incorrect")
 				}
 
-				event, err2 := testService.GetById(v.Id, *time.Local)
-				if v != (structs.Event{}) && err2 != nil {
+				event, err2 := testService.GetById(context.Background(), v.Id, "testUsername", *time.Local)
+				if !reflect.DeepEqual(v, structs.Event{}) && err2 != nil {
 					t.Errorf("event with id [%v] does not exist", v.Id)
 				}
 
 				resultMatchesInputParams := false
-				events, err := testService.repository.Get(test.params)
+				events, err := testService.repository.Get(context.Background(), test.params)
 				if err != nil {
 					t.Errorf(err.Error())
 				}
@@ -403,8 +402,7 @@ func TestGetEventFromDB(t *testing.T) {
 }
 
 func TestDeleteEventFromDB(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -418,7 +416,7 @@ func TestDeleteEventFromDB(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now().In(time.UTC),
@@ -435,12 +433,12 @@ func TestDeleteEventFromDB(t *testing.T) {
 	}
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			err := testService.DeleteEvent(test.id, "testUsername")
+			err := testService.DeleteEvent(context.Background(), test.id, "testUsername")
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("got %q, wanted %q", err, test.errorMessage)
 			}
 
-			_, err2 := testService.GetById(test.id, *time.Local)
+			_, err2 := testService.GetById(context.Background(), test.id, "testUsername", *time.Local)
 
 			if !errors.Is(err2, structs.ErrNoMatch) && err == nil {
 				t.Errorf("event with id [" + fmt.Sprint(test.id) + "] was not deleted")
@@ -448,3 +446,45 @@ func TestDeleteEventFromDB(t *testing.T) {
 		})
 	}
 }
+
+// The name filter matches names exactly, case included, in every repository.
+func TestGetEventByExactNameFromDB(t *testing.T) {
+	conn, err := db.Initialize(os.Getenv("DSN"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbRepo, _ := db.NewDatabaseRepository(conn)
+	if err = dbRepo.ClearRepoData(); err != nil {
+		t.Fatal(err)
+	}
+	mapRepo, _ := db.NewMapRepository()
+
+	repos := map[string]db.EventsRepository{"Postgres": dbRepo, "Map": mapRepo}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			testService := newEventsService(repo)
+			_, err := testService.AddEvent(context.Background(), *time.UTC, structs.Event{
+				Name:  "Ok Test Event",
+				Start: time.Date(2021, 12, 13, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, 12, 13, 11, 0, 0, 0, time.UTC),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := repo.Get(context.Background(), structs.EventParams{Name: "Ok Test Event"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 1 || events[0].Name != "Ok Test Event" {
+				t.Errorf("expected the event named Ok Test Event, got %v", events)
+			}
+			events, err = repo.Get(context.Background(), structs.EventParams{Name: "ok TEST event"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 0 {
+				t.Errorf("expected no event named ok TEST event, got %v", events)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -112,22 +117,22 @@ func TestAddOnMap(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			newEvent, err := testService.AddEvent(*time.Local, test.event)
+			newEvent, err := testService.AddEvent(context.Background(), *time.Local, test.event)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
 			}
 
-			if (newEvent == (structs.Event{}) && err == nil) || (newEvent == test.event && err != nil) {
+			if (reflect.DeepEqual(newEvent, structs.Event{}) && err == nil) || (reflect.DeepEqual(newEvent, test.event) && err != nil) {
 				t.Errorf("event was added incorrectly")
 			}
 
-			newEventFromRepo, err2 := testService.GetById(newEvent.Id, *time.Local)
+			newEventFromRepo, err2 := testService.GetById(context.Background(), newEvent.Id, "testUsername", *time.Local)
 			if err2 != nil && err == nil {
 				t.Errorf("event with id [%v] was not found", newEvent.Id)
 			}
 
-			if newEventFromRepo != (structs.Event{}) && err == nil && !structs.CompareTwoEvents(newEvent, newEventFromRepo) {
+			if !reflect.DeepEqual(newEventFromRepo, structs.Event{}) && err == nil && !structs.CompareTwoEvents(newEvent, newEventFromRepo) {
 				t.Errorf("event returned by add function is not equal to the test event")
 			}
 		})
@@ -139,7 +144,7 @@ func TestUpdateEventOnMap(t *testing.T) {
 	var testRepo, _ = db.NewMapRepository()
 	var testService = newEventsService(testRepo)
 
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now(),
@@ -236,12 +241,12 @@ func TestUpdateEventOnMap(t *testing.T) {
 	}
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			updatedEvent, err := testService.UpdateEvent(test.id, test.event, *time.Local)
-			if (updatedEvent == structs.Event{} && err == nil) || (updatedEvent == test.event && err != nil) {
+			updatedEvent, err := testService.UpdateEvent(context.Background(), test.id, "testUsername", test.event, *time.Local)
+			if (reflect.DeepEqual(updatedEvent, structs.Event{}) && err == nil) || (reflect.DeepEqual(updatedEvent, test.event) && err != nil) {
 				t.Errorf("result returned by update function is incorrect")
 			}
 
-			wasUpdated, err2 := testService.GetById(test.id, *time.Local)
+			wasUpdated, err2 := testService.GetById(context.Background(), test.id, "testUsername", *time.Local)
 			if err2 != nil && err == nil {
 				t.Errorf("event with id [%v] was not found", test.id)
 			}
@@ -268,11 +273,124 @@ func TestUpdateEventOnMap(t *testing.T) {
 	}
 }
 
+func TestPatchEventOnMap(t *testing.T) {
+	var testRepo, _ = db.NewMapRepository()
+	var testService = newEventsService(testRepo)
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	start := time.Date(2021, 12, 10, 13, 45, 0, 0, loc)
+	added, err := testService.AddEvent(context.Background(), *loc, structs.Event{
+		Name:  "Patched Event",
+		Start: start.UTC(),
+		End:   start.Add(time.Hour).UTC(),
+		Alert: start.Add(-time.Hour).UTC(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		id           int
+		patch        func(e structs.EventCreation) (structs.EventCreation, error)
+		check        func(e structs.Event) bool
+		errorMessage string
+	}{
+		"Clear alert": {
+			added.Id,
+			func(e structs.EventCreation) (structs.EventCreation, error) {
+				e.Alert = time.Time{}
+				return e, nil
+			},
+			func(e structs.Event) bool { return e.Alert.IsZero() && e.Start.Equal(start) },
+			"",
+		},
+		"Wall clock end in the user location": {
+			added.Id,
+			func(e structs.EventCreation) (structs.EventCreation, error) {
+				e.End = time.Date(2021, 12, 10, 16, 0, 0, 0, time.UTC)
+				return e, nil
+			},
+			func(e structs.Event) bool { return e.End.Equal(time.Date(2021, 12, 10, 16, 0, 0, 0, loc)) },
+			"",
+		},
+		"End before start": {
+			added.Id,
+			func(e structs.EventCreation) (structs.EventCreation, error) {
+				e.End = e.Start.Add(-time.Minute)
+				return e, nil
+			},
+			nil,
+			"end of the event is ahead of the start",
+		},
+		"Missing event": {
+			-1,
+			func(e structs.EventCreation) (structs.EventCreation, error) { return e, nil },
+			nil,
+			"does not exist",
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			patched, err := testService.PatchEvent(context.Background(), test.id, "testUsername", *loc, test.patch)
+			if !ErrorContains(err, test.errorMessage) {
+				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
+			}
+			if err == nil && !test.check(patched) {
+				t.Errorf("wrong patched event %+v", patched)
+			}
+		})
+	}
+}
+
+func TestPatchEventInDSTOverlapOnMap(t *testing.T) {
+	var testRepo, _ = db.NewMapRepository()
+	var testService = newEventsService(testRepo)
+	kyiv, err := time.LoadLocation("Europe/Kiev")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	// clocks go back from 04:00 EEST to 03:00 EET, the event starts at the second 03:30
+	start := time.Date(2021, 10, 31, 1, 30, 0, 0, time.UTC)
+	added, err := testService.AddEvent(context.Background(), *kyiv, structs.Event{
+		Name:  "Night shift",
+		Start: start,
+		End:   start.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed, err := testService.PatchEvent(context.Background(), added.Id, "testUsername", *kyiv, func(e structs.EventCreation) (structs.EventCreation, error) {
+		e.Name = "Late shift"
+		return e, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renamed.Start.Equal(start) || !renamed.End.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected a rename to keep %v - %v, got %v - %v", start, start.Add(time.Hour), renamed.Start, renamed.End)
+	}
+
+	// a patched end is read again, the untouched start stays where it was
+	lengthened, err := testService.PatchEvent(context.Background(), added.Id, "testUsername", *kyiv, func(e structs.EventCreation) (structs.EventCreation, error) {
+		e.End = e.End.Add(time.Hour)
+		return e, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lengthened.Start.Equal(start) || !lengthened.End.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("expected %v - %v, got %v - %v", start, start.Add(2*time.Hour), lengthened.Start, lengthened.End)
+	}
+}
+
 func TestGetEventOnMap(t *testing.T) {
 	var testRepo, _ = db.NewMapRepository()
 	var testService = newEventsService(testRepo)
 
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now(),
@@ -332,20 +450,20 @@ func TestGetEventOnMap(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			events, err := testService.GetEventsOfTheDay(test.params, *time.Local)
+			events, err := testService.GetEventsOfTheDay(context.Background(), test.params, *time.Local)
 			for _, v := range events {
 
-				if (v == structs.Event{} && err == nil) {
+				if (reflect.DeepEqual(v, structs.Event{}) && err == nil) {
 					t.Errorf("result returned by get function is incorrect")
 				}
 
-				event, err2 := testService.GetById(v.Id, *time.Local)
-				if v != (structs.Event{}) && err2 != nil {
+				event, err2 := testService.GetById(context.Background(), v.Id, "testUsername", *time.Local)
+				if !reflect.DeepEqual(v, structs.Event{}) && err2 != nil {
 					t.Errorf("event with id [%v] does not exist", v.Id)
 				}
 
 				resultMatchesInputParams := false
-				events, err := testService.repository.Get(test.params)
+				events, err := testService.repository.Get(context.Background(), test.params)
 				if err != nil {
 					t.Errorf(err.Error())
 				}
@@ -372,7 +490,7 @@ func TestDeleteEventOnMap(t *testing.T) {
 	var testRepo, _ = db.NewMapRepository()
 	var testService = newEventsService(testRepo)
 
-	testService.AddEvent(*time.Local, structs.Event{
+	testService.AddEvent(context.Background(), *time.Local, structs.Event{
 		Name:        "Ok Test Event",
 		Description: "an ok event for testing",
 		Start:       time.Now(),
@@ -389,12 +507,12 @@ func TestDeleteEventOnMap(t *testing.T) {
 	}
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			err := testService.DeleteEvent(test.id, "testUsername")
+			err := testService.DeleteEvent(context.Background(), test.id, "testUsername")
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("got %q, wanted %q", err, test.errorMessage)
 			}
 
-			_, err2 := testService.GetById(test.id, *time.Local)
+			_, err2 := testService.GetById(context.Background(), test.id, "testUsername", *time.Local)
 
 			if !errors.Is(err2, structs.ErrNoMatch) && err == nil {
 				t.Errorf("event with id [" + fmt.Sprint(test.id) + "] was not deleted")
@@ -403,3 +521,373 @@ func TestDeleteEventOnMap(t *testing.T) {
 
 	}
 }
+
+func TestEventsOfOtherUsersOnMap(t *testing.T) {
+	ctx := context.Background()
+	var testRepo, _ = db.NewMapRepository()
+	var testService = newEventsService(testRepo)
+	start := time.Now().Truncate(time.Hour)
+	owned, _ := testService.AddEvent(ctx, *time.UTC, structs.Event{Name: "Dentist", Start: start, End: start.Add(time.Hour), Owner: "ann"})
+	// stored before events had owners
+	shared, _ := testRepo.Add(ctx, structs.Event{Name: "Legacy", Start: start, End: start.Add(time.Hour)})
+	rename := func(e structs.EventCreation) (structs.EventCreation, error) {
+		e.Name = "Renamed"
+		return e, nil
+	}
+
+	testCases := map[string]func(id int) error{
+		"Get": func(id int) error {
+			_, err := testService.GetById(ctx, id, "bob", *time.UTC)
+			return err
+		},
+		"Update": func(id int) error {
+			_, err := testService.UpdateEvent(ctx, id, "bob", structs.Event{Name: "Renamed", Start: start, End: start.Add(time.Hour)}, *time.UTC)
+			return err
+		},
+		"Patch": func(id int) error {
+			_, err := testService.PatchEvent(ctx, id, "bob", *time.UTC, rename)
+			return err
+		},
+		"Delete": func(id int) error {
+			return testService.DeleteEvent(ctx, id, "bob")
+		},
+	}
+	for name, operation := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if err := operation(owned.Id); structs.KindOf(err) != structs.KindNotFound {
+				t.Errorf("expected the event of ann to be missing for bob, got %v", err)
+			}
+			if stored, err := testService.GetById(ctx, owned.Id, "ann", *time.UTC); err != nil || stored.Name != "Dentist" {
+				t.Errorf("expected the event of ann to stay as it was, got %v, %v", stored, err)
+			}
+		})
+	}
+
+	found, err := testService.GetEventsOfTheDay(ctx, structs.EventParams{Owner: "bob"}, *time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Id != shared.Id {
+		t.Errorf("expected bob to find only the event without an owner, got %v", found)
+	}
+	if _, err = testService.PatchEvent(ctx, shared.Id, "bob", *time.UTC, rename); err != nil {
+		t.Errorf("expected the event without an owner to be changed by anyone, got %v", err)
+	}
+}
+
+func TestAllDayEventsOnMap(t *testing.T) {
+	var testRepo, _ = db.NewMapRepository()
+	var testService = newEventsService(testRepo)
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+
+	var creation structs.EventCreation
+	body := `{"name":"Holidays","start":"2021-12-30","end":"2022-01-03","all_day":true}`
+	if err := json.Unmarshal([]byte(body), &creation); err != nil {
+		t.Fatal(err)
+	}
+	event, err := structs.CreateEvent(*loc, creation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	holidays, err := testService.AddEvent(context.Background(), *loc, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !holidays.Start.Equal(time.Date(2021, 12, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("all-day start moved to %v", holidays.Start)
+	}
+	encoded, _ := json.Marshal(holidays)
+	expected := fmt.Sprintf(`{"id":%d,"name":"Holidays","start":"2021-12-30","end":"2022-01-03","description":"","alert":"0001-01-01T00:00:00Z","all_day":true}`, holidays.Id)
+	if string(encoded) != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+	var decoded structs.Event
+	if err := json.Unmarshal(encoded, &decoded); err != nil || !reflect.DeepEqual(decoded, holidays) {
+		t.Errorf("all-day event did not survive a JSON round trip : %v %v", decoded, err)
+	}
+
+	// a timed event from 23:00 to 01:00 UTC takes place on two days
+	night, err := testService.AddEvent(context.Background(), *time.UTC, structs.Event{
+		Name:  "Night",
+		Start: time.Date(2021, 12, 31, 23, 0, 0, 0, time.UTC),
+		End:   time.Date(2022, 1, 1, 1, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		params structs.EventParams
+		result []int
+	}{
+		"First day":                 {structs.EventParams{Day: 30, Month: 12}, []int{holidays.Id}},
+		"Day in the next year":      {structs.EventParams{Day: 2, Month: 1, Year: 2022}, []int{holidays.Id}},
+		"Exclusive end date":        {structs.EventParams{Day: 3, Month: 1}, []int{}},
+		"Both events":               {structs.EventParams{Day: 1, Month: 1, Year: 2022}, []int{holidays.Id, night.Id}},
+		"Month of the start":        {structs.EventParams{Month: 12, Year: 2021, Sorting: true}, []int{holidays.Id, night.Id}},
+		"Week and name":             {structs.EventParams{Week: 52, Name: "Night"}, []int{night.Id}},
+		"Parts from different days": {structs.EventParams{Day: 30, Year: 2022}, []int{}},
+		"Window from the end":       {structs.EventParams{From: time.Date(2022, 1, 1, 1, 0, 0, 0, time.UTC)}, []int{holidays.Id}},
+		"Window up to the start":    {structs.EventParams{To: time.Date(2021, 12, 31, 23, 0, 0, 0, time.UTC)}, []int{holidays.Id}},
+		"Window of the night":       {structs.EventParams{From: night.Start, To: night.End}, []int{holidays.Id, night.Id}},
+		"Window after the end date": {structs.EventParams{From: time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC)}, []int{}},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			events, err := testService.GetEventsOfTheDay(context.Background(), test.params, *loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]int, 0, len(events))
+			for _, e := range events {
+				ids = append(ids, e.Id)
+			}
+			sort.Ints(ids)
+			if fmt.Sprint(ids) != fmt.Sprint(test.result) {
+				t.Errorf("expected events %v, got %v", test.result, ids)
+			}
+		})
+	}
+
+	rejected := map[string]string{
+		"End before start":       `{"name":"Wrong","start":"2021-12-30","end":"2021-12-30","all_day":true}`,
+		"Dates of a timed event": `{"name":"Wrong","start":"2021-12-30","end":"2021-12-31"}`,
+	}
+	for name, body := range rejected {
+		t.Run(name, func(t *testing.T) {
+			var creation structs.EventCreation
+			err := json.Unmarshal([]byte(body), &creation)
+			if err == nil {
+				var event structs.Event
+				if event, err = structs.CreateEvent(*loc, creation); err == nil {
+					_, err = testService.AddEvent(context.Background(), *loc, event)
+				}
+			}
+			if structs.KindOf(err) != structs.KindValidation {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEventTimeZonesOnMap(t *testing.T) {
+	var testRepo, _ = db.NewMapRepository()
+	var testService = newEventsService(testRepo)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	london, _ := time.LoadLocation("Europe/London")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	testCases := map[string]struct {
+		body         string
+		start        time.Time
+		end          time.Time
+		startTZ      string
+		endTZ        string
+		errorMessage string
+	}{
+		"Owner location": {
+			`{"name":"Standup","start":"2021-12-10T10:00:00Z","end":"2021-12-10T10:15:00Z"}`,
+			time.Date(2021, 12, 10, 10, 0, 0, 0, newYork),
+			time.Date(2021, 12, 10, 10, 15, 0, 0, newYork),
+			"", "", "",
+		},
+		"Meeting in London": {
+			`{"name":"Meeting","start":"2021-12-10T10:00:00Z","end":"2021-12-10T11:00:00Z","start_tz":"Europe/London"}`,
+			time.Date(2021, 12, 10, 10, 0, 0, 0, london),
+			time.Date(2021, 12, 10, 11, 0, 0, 0, london),
+			"Europe/London", "Europe/London", "",
+		},
+		"Flight to Tokyo": {
+			`{"name":"Flight","start":"2021-12-10T11:00:00Z","end":"2021-12-11T15:00:00Z","start_tz":"Europe/London","end_tz":"Asia/Tokyo"}`,
+			time.Date(2021, 12, 10, 11, 0, 0, 0, london),
+			time.Date(2021, 12, 11, 15, 0, 0, 0, tokyo),
+			"Europe/London", "Asia/Tokyo", "",
+		},
+		"Unknown zone": {
+			`{"name":"Meeting","start":"2021-12-10T10:00:00Z","end":"2021-12-10T11:00:00Z","start_tz":"Mars/Olympus"}`,
+			time.Time{}, time.Time{}, "", "",
+			"unknown time zone [Mars/Olympus]",
+		},
+		"All-day event with a zone": {
+			`{"name":"Trip","start":"2021-12-10","all_day":true,"start_tz":"Europe/London"}`,
+			time.Time{}, time.Time{}, "", "",
+			"all-day events are not tied to a time zone",
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var creation structs.EventCreation
+			if err := json.Unmarshal([]byte(test.body), &creation); err != nil {
+				t.Fatal(err)
+			}
+			event, err := structs.CreateEvent(*newYork, creation)
+			if err == nil {
+				event, err = testService.AddEvent(context.Background(), *newYork, event)
+			}
+			if test.errorMessage != "" {
+				if err == nil || err.Error() != test.errorMessage {
+					t.Errorf("expected error %q, got %v", test.errorMessage, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !event.Start.Equal(test.start) || !event.End.Equal(test.end) {
+				t.Errorf("expected %v - %v, got %v - %v", test.start, test.end, event.Start, event.End)
+			}
+			if event.Start.Location().String() != newYork.String() {
+				t.Errorf("event is not shown in the location of its owner : %v", event.Start)
+			}
+			if event.StartTZ != test.startTZ || event.EndTZ != test.endTZ {
+				t.Errorf("expected zones %q %q, got %q %q", test.startTZ, test.endTZ, event.StartTZ, event.EndTZ)
+			}
+		})
+	}
+
+	// a patch is written in the zones of the event, not in the owner location
+	flight, err := structs.CreateEvent(*newYork, structs.EventCreation{
+		Name:    "Flight",
+		Start:   time.Date(2021, 12, 10, 11, 0, 0, 0, time.UTC),
+		End:     time.Date(2021, 12, 11, 15, 0, 0, 0, time.UTC),
+		StartTZ: "Europe/London",
+		EndTZ:   "Asia/Tokyo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flight, err = testService.AddEvent(context.Background(), *newYork, flight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := testService.PatchEvent(context.Background(), flight.Id, "testUsername", *newYork, func(e structs.EventCreation) (structs.EventCreation, error) {
+		e.End = e.End.Add(30 * time.Minute)
+		return e, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2021, 12, 11, 15, 30, 0, 0, tokyo); !patched.End.Equal(want) || !patched.Start.Equal(flight.Start) {
+		t.Errorf("expected the flight to land at %v, got %v", want, patched.End)
+	}
+}
+
+func TestEventRemindersOnMap(t *testing.T) {
+	var testRepo, _ = db.NewMapRepository()
+	var testService = newEventsService(testRepo)
+	kyiv, err := time.LoadLocation("Europe/Kiev")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+
+	testCases := map[string]struct {
+		body         string
+		remindAt     []time.Time
+		channels     []string
+		errorMessage string
+	}{
+		"Minutes before start": {
+			`{"name":"Call","start":"2021-12-10T10:00:00Z","end":"2021-12-10T11:00:00Z","reminders":[{"offset":"-15m"}]}`,
+			[]time.Time{time.Date(2021, 12, 10, 9, 45, 0, 0, kyiv)},
+			[]string{structs.ChannelPush},
+			"",
+		},
+		"Day before at nine and before the end": {
+			`{"name":"Call","start":"2021-12-10T18:00:00Z","end":"2021-12-10T19:00:00Z","reminders":[
+				{"offset":"-1d","at":"09:00","channel":"email"},{"relative_to":"end","offset":"-5m","channel":"sms"}]}`,
+			[]time.Time{time.Date(2021, 12, 9, 9, 0, 0, 0, kyiv), time.Date(2021, 12, 10, 18, 55, 0, 0, kyiv)},
+			[]string{structs.ChannelEmail, structs.ChannelSMS},
+			"",
+		},
+		"Days keep the wall clock across a transition": {
+			`{"name":"Call","start":"2021-03-29T10:00:00Z","end":"2021-03-29T11:00:00Z","reminders":[{"offset":"-2d"}]}`,
+			[]time.Time{time.Date(2021, 3, 27, 10, 0, 0, 0, kyiv)},
+			[]string{structs.ChannelPush},
+			"",
+		},
+		"Bad offset": {
+			`{"name":"Call","start":"2021-12-10T10:00:00Z","end":"2021-12-10T11:00:00Z","reminders":[{"offset":"soon"}]}`,
+			nil, nil,
+			"invalid reminder",
+		},
+		"Time of day with a duration": {
+			`{"name":"Call","start":"2021-12-10T10:00:00Z","end":"2021-12-10T11:00:00Z","reminders":[{"offset":"-1h","at":"09:00"}]}`,
+			nil, nil,
+			"invalid reminder",
+		},
+		"Unknown channel": {
+			`{"name":"Call","start":"2021-12-10T10:00:00Z","end":"2021-12-10T11:00:00Z","reminders":[{"offset":"-1h","channel":"pigeon"}]}`,
+			nil, nil,
+			"validator : invalid data format",
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var creation structs.EventCreation
+			if err := json.Unmarshal([]byte(test.body), &creation); err != nil {
+				t.Fatal(err)
+			}
+			event, err := structs.CreateEvent(*kyiv, creation)
+			if err == nil {
+				event, err = testService.AddEvent(context.Background(), *kyiv, event)
+			}
+			if test.errorMessage != "" {
+				if err == nil || err.Error() != test.errorMessage || structs.KindOf(err) != structs.KindValidation {
+					t.Errorf("expected error %q, got %v", test.errorMessage, err)
+				} else if field := structs.FieldsOf(err)[0].Field; !strings.HasPrefix(field, "reminders[0].") {
+					t.Errorf("expected the reminder to be named, got %q", field)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(event.Reminders) != len(test.remindAt) {
+				t.Fatalf("expected %v reminders, got %v", len(test.remindAt), event.Reminders)
+			}
+			for i, r := range event.Reminders {
+				if !r.RemindAt.Equal(test.remindAt[i]) || r.Channel != test.channels[i] {
+					t.Errorf("expected %v by %v, got %v by %v", test.remindAt[i], test.channels[i], r.RemindAt, r.Channel)
+				}
+			}
+			// old clients read the first reminder as the alert
+			if !event.Alert.Equal(test.remindAt[0]) {
+				t.Errorf("expected the alert at %v, got %v", test.remindAt[0], event.Alert)
+			}
+		})
+	}
+
+	// moving the event moves its reminders, without the derived alert becoming its own
+	event, _ := structs.CreateEvent(*kyiv, structs.EventCreation{
+		Name:      "Review",
+		Start:     time.Date(2021, 12, 10, 10, 0, 0, 0, time.UTC),
+		End:       time.Date(2021, 12, 10, 11, 0, 0, 0, time.UTC),
+		Reminders: []structs.Reminder{{Offset: "-30m"}},
+	})
+	event, err = testService.AddEvent(context.Background(), *kyiv, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := testService.PatchEvent(context.Background(), event.Id, "testUsername", *kyiv, func(e structs.EventCreation) (structs.EventCreation, error) {
+		e.Start = e.Start.Add(2 * time.Hour)
+		e.End = e.End.Add(2 * time.Hour)
+		return e, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2021, 12, 10, 11, 30, 0, 0, kyiv)
+	if !patched.Reminders[0].RemindAt.Equal(want) || !patched.Alert.Equal(want) {
+		t.Errorf("expected the reminder at %v, got %v with alert %v", want, patched.Reminders[0].RemindAt, patched.Alert)
+	}
+	stored, _ := testRepo.GetByID(context.Background(), event.Id)
+	if !stored.Alert.IsZero() {
+		t.Errorf("derived alert was stored : %v", stored.Alert)
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat records when a background worker last woke up, so that readiness
+// checks can tell a running worker from a stopped or stuck one.
+type Heartbeat struct {
+	// last is the time of the last tick in unix nanoseconds, 0 before the first one
+	last     int64
+	interval time.Duration
+}
+
+// NewHeartbeat returns the heartbeat of a worker waking up every interval.
+func NewHeartbeat(interval time.Duration) *Heartbeat {
+	return &Heartbeat{interval: interval}
+}
+
+// Beat records that the worker woke up at now.
+func (h *Heartbeat) Beat(now time.Time) {
+	atomic.StoreInt64(&h.last, now.UnixNano())
+}
+
+func (h *Heartbeat) LastTick() time.Time {
+	last := atomic.LoadInt64(&h.last)
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+func (h *Heartbeat) Interval() time.Duration {
+	return h.interval
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// holidayService lists public holidays from the rules bundled with structs, nothing is stored.
+type holidayService struct{}
+
+func newHolidayService() *holidayService {
+	return &holidayService{}
+}
+
+// GetHolidays returns the holidays of country in year.
+func (s *holidayService) GetHolidays(ctx context.Context, country string, year int) ([]structs.Holiday, error) {
+	c, err := structs.HolidayCalendarOf(country)
+	if err != nil {
+		return nil, err
+	}
+	return c.Holidays(year)
+}
+
+// Countries returns the codes of the calendars holidays can be listed for.
+func (s *holidayService) Countries() []string {
+	return structs.HolidayCountries()
+}
+
+// holidayEvents returns the holidays of p.Holidays matching p as all-day events. They
+// are taken from the year of p, or from the current year in loc without one.
+func holidayEvents(p structs.EventParams, loc time.Location) ([]structs.Event, error) {
+	c, err := structs.HolidayCalendarOf(p.Holidays)
+	if err != nil {
+		return nil, structs.NewValidationError("unknown holiday calendar ["+p.Holidays+"]",
+			structs.FieldError{Field: "holidays", Message: structs.FieldsOf(err)[0].Message})
+	}
+	year := p.Year
+	if year == 0 {
+		year = time.Now().In(&loc).Year()
+	}
+	return structs.HolidayEvents(c, []int{year}, p)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestHolidaysOnMap(t *testing.T) {
+	ctx := context.Background()
+	s, loc := newMapService(t, "Europe/London")
+	start := time.Date(2021, time.December, 27, 10, 0, 0, 0, time.UTC)
+	if _, err := s.Events.AddEvent(ctx, loc, structs.Event{Name: "Walk", Start: start, End: start.Add(time.Hour), Owner: "ann"}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		params   structs.EventParams
+		expected []string
+		kind     structs.ErrorKind
+	}{
+		"Without holidays": {
+			params:   structs.EventParams{Owner: "ann", Year: 2021, Month: 12},
+			expected: []string{"Walk"},
+		},
+		"Holidays of the month": {
+			params:   structs.EventParams{Owner: "ann", Year: 2021, Month: 12, Holidays: "gb", Sorting: true},
+			expected: []string{"Christmas Day", "Boxing Day", "Christmas Day (observed)", "Walk", "Boxing Day (observed)"},
+		},
+		"Holidays by name": {
+			params:   structs.EventParams{Owner: "ann", Year: 2021, Name: "Good Friday", Holidays: "GB"},
+			expected: []string{"Good Friday"},
+		},
+		"Unknown calendar": {
+			params: structs.EventParams{Owner: "ann", Holidays: "XX"},
+			kind:   structs.KindValidation,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			events, err := s.Events.GetEventsOfTheDay(ctx, tc.params, loc)
+			if tc.kind != "" {
+				if structs.KindOf(err) != tc.kind {
+					t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, 0, len(events))
+			for _, e := range events {
+				names = append(names, e.Name)
+			}
+			if len(names) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, names)
+			}
+			for i := range names {
+				if names[i] != tc.expected[i] {
+					t.Errorf("expected %v, got %v", tc.expected, names)
+					break
+				}
+			}
+		})
+	}
+
+	// the observed Boxing Day is a day off, the day after is a working day
+	workday := []structs.TimeRange{{Start: "09:00", End: "17:00"}}
+	hours, err := s.Users.UpdateAvailability(ctx, "ann", structs.Availability{
+		Weekly:   map[string][]structs.TimeRange{"tuesday": workday, "wednesday": workday},
+		Holidays: "gb",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hours.Holidays != "GB" {
+		t.Errorf("expected the calendar to be stored as GB, got %v", hours.Holidays)
+	}
+	working := hours.WorkingIntervals(time.Date(2021, time.December, 27, 0, 0, 0, 0, time.UTC), time.Date(2021, time.December, 30, 0, 0, 0, 0, time.UTC), &loc)
+	if len(working) != 1 || working[0].Start.Day() != 29 {
+		t.Errorf("expected to work on the 29th only, got %v", working)
+	}
+}
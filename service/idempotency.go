@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/logger"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+const (
+	// idempotencyPollInterval is how often a retry checks on a request with the
+	// same key that is handled by another instance of the service.
+	idempotencyPollInterval = 100 * time.Millisecond
+	idempotencyWriteTimeout = 5 * time.Second
+	// defaultIdempotencyLease is how long a request holds its key before a retry may take
+	// it over, it outlasts the request timeout so that only crashed requests lose their keys.
+	defaultIdempotencyLease = time.Minute
+)
+
+// idempotencyService lets clients retry requests sent with an Idempotency-Key
+// without repeating their effects, the first response is replayed instead.
+type idempotencyService struct {
+	repository db.IdempotencyRepository
+	ttl        time.Duration
+	lease      time.Duration
+	locks      keyLocks
+
+	statsMu sync.Mutex
+	stats   IdempotencyStats
+}
+
+type IdempotencyStats struct {
+	TTL         string    `json:"ttl"`
+	LastCleanup time.Time `json:"last_cleanup"`
+	Deleted     int64     `json:"deleted"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+func newIdempotencyService(repository db.IdempotencyRepository, ttl time.Duration, lease time.Duration) *idempotencyService {
+	return &idempotencyService{
+		repository: repository,
+		ttl:        ttl,
+		lease:      lease,
+		locks:      keyLocks{locks: make(map[string]*keyLock)},
+		stats:      IdempotencyStats{TTL: ttl.String()},
+	}
+}
+
+// FinishFunc stores the response to a request started with Begin, with the headers
+// the handler set. Responses with a 5xx status are not stored, so the request can be retried.
+type FinishFunc func(status int, header map[string][]string, body []byte)
+
+// Begin claims key for a request with the given hash. It returns the stored record when
+// the request was already handled, or finish when the caller has to handle it now.
+// Requests with the same key are handled one at a time, finish ends the turn of the caller.
+// A reservation left behind by a crashed request is taken over once its lease expired.
+func (s *idempotencyService) Begin(ctx context.Context, key string, requestHash string) (*structs.IdempotencyRecord, FinishFunc, error) {
+	unlock := s.locks.lock(key)
+	for {
+		// Postgres keeps microseconds, the reservation is found again by its creation time
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		reservation := structs.IdempotencyRecord{
+			Key:         key,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.lease),
+		}
+		existing, reserved, err := s.repository.Reserve(ctx, reservation)
+		if err != nil {
+			unlock()
+			return nil, nil, err
+		}
+		if reserved {
+			return nil, s.finish(ctx, reservation, unlock), nil
+		}
+		if existing.RequestHash != requestHash {
+			unlock()
+			return nil, nil, structs.NewError(structs.KindUnprocessable,
+				"the idempotency key was already used for a different request")
+		}
+		if existing.Completed() {
+			unlock()
+			return &existing, nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			unlock()
+			return nil, nil, structs.NewError(structs.KindConflict,
+				"a request with the same idempotency key is still being processed")
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+func (s *idempotencyService) finish(ctx context.Context, reservation structs.IdempotencyRecord, unlock func()) FinishFunc {
+	entry := logger.FromContext(ctx)
+	var once sync.Once
+	return func(status int, header map[string][]string, body []byte) {
+		once.Do(func() {
+			defer unlock()
+			// the request context may be gone already, the outcome still has to be saved
+			ctx, cancel := context.WithTimeout(context.Background(), idempotencyWriteTimeout)
+			defer cancel()
+			var err error
+			if status >= 500 || status == 0 {
+				err = s.repository.Release(ctx, reservation)
+			} else {
+				completed := reservation
+				completed.Status, completed.Header, completed.Body = status, header, body
+				completed.ExpiresAt = reservation.CreatedAt.Add(s.ttl)
+				err = s.repository.Complete(ctx, completed)
+			}
+			if err != nil {
+				entry.Errorf("saving idempotent response : %v", err)
+			}
+		})
+	}
+}
+
+// Purge deletes the keys that expired.
+func (s *idempotencyService) Purge(ctx context.Context) (int64, error) {
+	deleted, err := s.repository.DeleteExpired(ctx, time.Now().UTC())
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	s.stats.LastCleanup = time.Now()
+	s.stats.Deleted += deleted
+	s.stats.LastError = ""
+	if err != nil {
+		s.stats.LastError = err.Error()
+	}
+	return deleted, err
+}
+
+// RunCleanup purges expired keys every interval of heartbeat until ctx is done.
+func (s *idempotencyService) RunCleanup(ctx context.Context, heartbeat *Heartbeat) {
+	ticker := time.NewTicker(heartbeat.Interval())
+	defer ticker.Stop()
+	heartbeat.Beat(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			heartbeat.Beat(now)
+			deleted, err := s.Purge(ctx)
+			if err != nil {
+				logger.Errorf("deleting expired idempotency keys : %v", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.Debugf("deleted %d expired idempotency keys", deleted)
+			}
+		}
+	}
+}
+
+func (s *idempotencyService) Stats() IdempotencyStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	return s.stats
+}
+
+// keyLocks serializes requests with the same key inside one process.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	mu      sync.Mutex
+	holders int
+}
+
+func (l *keyLocks) lock(key string) (unlock func()) {
+	l.mu.Lock()
+	kl, ok := l.locks[key]
+	if !ok {
+		kl = &keyLock{}
+		l.locks[key] = kl
+	}
+	kl.holders++
+	l.mu.Unlock()
+
+	kl.mu.Lock()
+	return func() {
+		kl.mu.Unlock()
+		l.mu.Lock()
+		kl.holders--
+		if kl.holders == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestIdempotencyBegin(t *testing.T) {
+	testCases := map[string]struct {
+		firstStatus  int
+		secondHash   string
+		replayed     bool
+		kindExpected structs.ErrorKind
+	}{
+		"Retry is replayed": {
+			firstStatus: 201,
+			secondHash:  "hash",
+			replayed:    true,
+		},
+		"Rejected request is replayed": {
+			firstStatus: 400,
+			secondHash:  "hash",
+			replayed:    true,
+		},
+		"Different payload": {
+			firstStatus:  201,
+			secondHash:   "other",
+			kindExpected: structs.KindUnprocessable,
+		},
+		"Failed request can be retried": {
+			firstStatus: 500,
+			secondHash:  "hash",
+			replayed:    false,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			repo, _ := db.NewIdempotencyInMemoryRepository()
+			s := newIdempotencyService(repo, time.Hour, time.Minute)
+
+			stored, finish, err := s.Begin(context.Background(), "key", "hash")
+			if err != nil || stored != nil || finish == nil {
+				t.Fatalf("first request was not started : %v", err)
+			}
+			finish(test.firstStatus, map[string][]string{"Content-Type": {"application/json"}}, []byte(`{"id":1}`))
+
+			stored, finish, err = s.Begin(context.Background(), "key", test.secondHash)
+			if test.kindExpected != "" {
+				if structs.KindOf(err) != test.kindExpected {
+					t.Fatalf("wrong error : got %v, wanted kind %v", err, test.kindExpected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.replayed && (stored == nil || stored.Status != test.firstStatus || string(stored.Body) != `{"id":1}` ||
+				len(stored.Header["Content-Type"]) != 1 || stored.Header["Content-Type"][0] != "application/json") {
+				t.Errorf("response was not replayed : %+v", stored)
+			}
+			if !test.replayed {
+				if finish == nil {
+					t.Fatalf("retry was not started")
+				}
+				finish(201, nil, nil)
+			}
+		})
+	}
+}
+
+func TestIdempotencyConcurrentRequests(t *testing.T) {
+	repo, _ := db.NewIdempotencyInMemoryRepository()
+	s := newIdempotencyService(repo, time.Hour, time.Minute)
+
+	var handled int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stored, finish, err := s.Begin(context.Background(), "key", "hash")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if stored != nil {
+				return
+			}
+			atomic.AddInt32(&handled, 1)
+			time.Sleep(10 * time.Millisecond)
+			finish(201, nil, []byte("created"))
+		}()
+	}
+	wg.Wait()
+	if handled != 1 {
+		t.Errorf("request was handled %v times", handled)
+	}
+}
+
+func TestIdempotencyExpiry(t *testing.T) {
+	repo, _ := db.NewIdempotencyInMemoryRepository()
+	s := newIdempotencyService(repo, time.Millisecond, time.Minute)
+
+	_, finish, err := s.Begin(context.Background(), "key", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish(201, nil, nil)
+	time.Sleep(5 * time.Millisecond)
+
+	deleted, err := s.Purge(context.Background())
+	if err != nil || deleted != 1 {
+		t.Errorf("expired key was not deleted : %v, %v", deleted, err)
+	}
+	stored, finish, err := s.Begin(context.Background(), "key", "other")
+	if err != nil || stored != nil || finish == nil {
+		t.Fatalf("expired key was not reusable : %v", err)
+	}
+	finish(201, nil, nil)
+}
+
+func TestIdempotencyLease(t *testing.T) {
+	repo, _ := db.NewIdempotencyInMemoryRepository()
+	// two instances of the service share the keys, the first one crashes
+	crashed := newIdempotencyService(repo, time.Hour, 20*time.Millisecond)
+	retrying := newIdempotencyService(repo, time.Hour, 20*time.Millisecond)
+
+	_, lateFinish, err := crashed.Begin(context.Background(), "key", "hash")
+	if err != nil || lateFinish == nil {
+		t.Fatalf("first request was not started : %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, _, err = retrying.Begin(ctx, "key", "hash"); structs.KindOf(err) != structs.KindConflict {
+		t.Fatalf("expected a conflict while the lease holds, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	stored, finish, err := retrying.Begin(context.Background(), "key", "hash")
+	if err != nil || stored != nil || finish == nil {
+		t.Fatalf("expired reservation was not taken over : %+v, %v", stored, err)
+	}
+	finish(201, nil, []byte("retried"))
+
+	// the crashed request coming back late can not replace the response of the retry
+	lateFinish(201, nil, []byte("late"))
+	stored, _, err = retrying.Begin(context.Background(), "key", "hash")
+	if err != nil || stored == nil || string(stored.Body) != "retried" {
+		t.Fatalf("expected the response of the retry, got %+v, %v", stored, err)
+	}
+	if !stored.ExpiresAt.After(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("expected the response to be kept for the TTL, it expires at %v", stored.ExpiresAt)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestQuickAddOnMap(t *testing.T) {
+	ctx := context.Background()
+	s, loc := newMapService(t, "Europe/Kiev")
+
+	testCases := map[string]struct {
+		text   string
+		dryRun bool
+		name   string
+		stored int
+		kind   structs.ErrorKind
+	}{
+		"Dry run stores nothing": {
+			text: "Lunch with Ana tomorrow 13:00-14:00", dryRun: true,
+			name: "Lunch with Ana",
+		},
+		"Event is stored": {
+			text: "Lunch with Ana tomorrow 13:00-14:00",
+			name: "Lunch with Ana", stored: 1,
+		},
+		"Dry run of a repeating event": {
+			text: "Standup every monday at 9:30 for 15 minutes", dryRun: true,
+			name: "Standup",
+		},
+		"Repeating event without a dry run": {
+			text: "Standup every monday at 9:30 for 15 minutes",
+			kind: structs.KindUnprocessable,
+		},
+		"Text without a time": {
+			text: "Lunch with Ana",
+			kind: structs.KindValidation,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			before := s.eventsRepo.GetLastUsedId()
+			result, err := s.Events.QuickAdd(ctx, "ann", loc, tc.text, tc.dryRun)
+			if stored := s.eventsRepo.GetLastUsedId() - before; stored != tc.stored {
+				t.Errorf("expected %d stored events, got %d", tc.stored, stored)
+			}
+			if tc.kind != "" {
+				if structs.KindOf(err) != tc.kind {
+					t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.dryRun != (result.Event == nil) {
+				t.Errorf("expected the added event only without a dry run, got %v", result.Event)
+			}
+			if result.QuickInterpretation.Event.Name != tc.name {
+				t.Errorf("expected the name %v, got %q", tc.name, result.QuickInterpretation.Event.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestRelocationInDB(t *testing.T) {
+	ctx := context.Background()
+	conn := connectDB(t)
+	s, kyiv := newDBService(t, conn, "Europe/Kiev")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	future := time.Now().In(&kyiv).Add(48 * time.Hour).Truncate(time.Hour)
+	event, err := s.Events.AddEvent(ctx, kyiv, structs.Event{
+		Name: "future", Start: future, End: future.Add(time.Hour), Alert: future.Add(-time.Hour), Owner: "ann",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.Events.AddEvent(ctx, kyiv, structs.Event{Name: "other", Start: future, End: future.Add(time.Hour), Owner: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// stored before events had owners
+	legacy, err := s.eventsRepo.Add(ctx, structs.Event{Name: "legacy", Start: future, End: future.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.Users.UpdateLocation(ctx, "ann", *tokyo, structs.RebaseOptions{Scope: structs.RebaseFuture}); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := s.Events.GetById(ctx, event.Id, "ann", *tokyo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Start.Format("15:04") != future.Format("15:04") || !moved.Alert.Equal(moved.Start.Add(-time.Hour)) {
+		t.Errorf("expected the event to keep starting at %v with its alert, got %v alerted at %v", future.Format("15:04"), moved.Start, moved.Alert)
+	}
+	if shared, _ := s.Events.GetById(ctx, legacy.Id, "ann", *tokyo); shared.Start.Format("15:04") != future.Format("15:04") {
+		t.Errorf("expected the event without an owner to keep starting at %v, got %v", future.Format("15:04"), shared.Start)
+	}
+
+	// the event of bob fails the transaction after ann and her event were changed in it
+	relocationRepo, _ := db.NewRelocationDBRepository(conn)
+	rebased, err := structs.RebaseEvent(moved, tokyo, &kyiv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = relocationRepo.Relocate(ctx, "ann", kyiv, []structs.Event{rebased, other})
+	if structs.KindOf(err) != structs.KindNotFound {
+		t.Fatalf("expected the event of another user to be rejected, got %v", err)
+	}
+	loc, _ := s.Users.GetUserLocation(ctx, "ann")
+	kept, _ := s.Events.GetById(ctx, event.Id, "ann", *tokyo)
+	if loc.String() != tokyo.String() || !kept.Start.Equal(moved.Start) {
+		t.Errorf("expected the failed relocation to be rolled back, got %v and a start at %v", loc.String(), kept.Start)
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// reportService tells users how their time is spent, the sums are left to the repository.
+type reportService struct {
+	repository db.EventsRepository
+	weekStart  time.Weekday
+}
+
+func newReportService(repository db.EventsRepository, weekStart time.Weekday) *reportService {
+	return &reportService{repository: repository, weekStart: weekStart}
+}
+
+// GetTimeReport sums up the time the events of user take between the dates from and to
+// in loc, the current week by default. Events are grouped by groupBy and filtered by name.
+func (s *reportService) GetTimeReport(ctx context.Context, user string, from string, to string, groupBy string,
+	name string, loc time.Location) (structs.TimeReport, error) {
+	p, err := structs.NewReportParams(from, to, groupBy, name, s.weekStart, &loc, time.Now())
+	if err != nil {
+		return structs.TimeReport{}, err
+	}
+	p.Owner = user
+	return s.repository.TimeReport(ctx, p)
+}
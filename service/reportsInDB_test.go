@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestTimeReportInDB(t *testing.T) {
+	s, loc := newDBService(t, connectDB(t), "Europe/Kiev")
+	addReportEvents(t, s, loc)
+	testTimeReport(t, s, loc)
+
+	onMap, mapLoc := newMapService(t, "Europe/Kiev")
+	addReportEvents(t, onMap, mapLoc)
+	for _, groupBy := range []string{structs.ReportByDay, structs.ReportByWeek, structs.ReportByName, structs.ReportByTag} {
+		for _, week := range []string{"2021-11-29", "2021-12-13", "2021-12-20"} {
+			fromDB, err := s.Reports.GetTimeReport(context.Background(), "ann", week, "", groupBy, "", loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fromMap, err := onMap.Reports.GetTimeReport(context.Background(), "ann", week, "", groupBy, "", mapLoc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(fromDB, fromMap) {
+				t.Errorf("expected the report by %v of the week of %v to be %+v as on the map, got %+v", groupBy, week, fromMap, fromDB)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestTimeReportOnMap(t *testing.T) {
+	s, loc := newMapService(t, "Europe/Kiev")
+	addReportEvents(t, s, loc)
+	testTimeReport(t, s, loc)
+}
+
+// addReportEvents adds the events the time reports of ann are made of.
+func addReportEvents(t *testing.T, s *Service, loc time.Location) {
+	at := func(day int, hour int, min int) time.Time {
+		return time.Date(2021, time.December, day, hour, min, 0, 0, &loc)
+	}
+	events := []structs.Event{
+		{Name: "Sync #team", Start: at(13, 10, 0), End: at(13, 11, 0), Owner: "ann"},
+		{Name: "Review", Description: "#Team #code", Start: at(13, 10, 30), End: at(13, 12, 0), Owner: "ann"},
+		{Name: "Standup", Start: at(14, 9, 0), End: at(14, 9, 15), Owner: "ann"},
+		{Name: "Late call #ops", Start: at(14, 23, 0), End: at(15, 1, 0), Owner: "ann"},
+		{Name: "Old", Start: at(1, 9, 0), End: at(1, 10, 0), Owner: "ann"},
+		{Name: "Trip", Start: structs.Date(at(13, 0, 0)), End: structs.Date(at(14, 0, 0)), AllDay: true, Owner: "ann"},
+		{Name: "Gym", Start: at(13, 10, 0), End: at(13, 11, 0), Owner: "bob"},
+		{Name: "Lecture #навчання", Start: at(20, 10, 0), End: at(20, 11, 0), Owner: "ann"},
+	}
+	for _, e := range events {
+		if _, err := s.Events.AddEvent(context.Background(), loc, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testTimeReport checks the time reports of ann on the events of addReportEvents.
+func testTimeReport(t *testing.T, s *Service, loc time.Location) {
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		from    string
+		to      string
+		groupBy string
+		name    string
+		hours   float64
+		events  int
+		groups  []structs.ReportGroup
+		kind    structs.ErrorKind
+	}{
+		"By day with overlaps counted once": {
+			from: "2021-12-13", to: "2021-12-19",
+			hours: 4.25, events: 4,
+			groups: []structs.ReportGroup{
+				{Key: "2021-12-13", Hours: 2, Events: 2},
+				{Key: "2021-12-14", Hours: 1.25, Events: 2},
+				{Key: "2021-12-15", Hours: 1, Events: 1},
+			},
+		},
+		"By week": {
+			from: "2021-12-13", to: "2021-12-19", groupBy: structs.ReportByWeek,
+			hours: 4.25, events: 4,
+			groups: []structs.ReportGroup{{Key: "2021-12-13", Hours: 4.25, Events: 4}},
+		},
+		"By tag": {
+			from: "2021-12-13", to: "2021-12-19", groupBy: structs.ReportByTag,
+			hours: 4.25, events: 4,
+			groups: []structs.ReportGroup{
+				{Key: "ops", Hours: 2, Events: 1},
+				{Key: "team", Hours: 2, Events: 2},
+				{Key: "code", Hours: 1.5, Events: 1},
+				{Key: structs.Untagged, Hours: 0.25, Events: 1},
+			},
+		},
+		"By name matching a pattern": {
+			from: "2021-12-13", to: "2021-12-19", groupBy: structs.ReportByName, name: "*CALL*",
+			hours: 2, events: 1,
+			groups: []structs.ReportGroup{{Key: "Late call #ops", Hours: 2, Events: 1}},
+		},
+		"Window cuts events": {
+			from: "2021-12-15", to: "2021-12-15",
+			hours: 1, events: 1,
+			groups: []structs.ReportGroup{{Key: "2021-12-15", Hours: 1, Events: 1}},
+		},
+		"Tags keep to ASCII": {
+			from: "2021-12-20", to: "2021-12-20", groupBy: structs.ReportByTag,
+			hours: 1, events: 1,
+			groups: []structs.ReportGroup{{Key: structs.Untagged, Hours: 1, Events: 1}},
+		},
+		"Unknown grouping": {
+			groupBy: "month",
+			kind:    structs.KindValidation,
+		},
+		"To before from": {
+			from: "2021-12-13", to: "2021-12-12",
+			kind: structs.KindValidation,
+		},
+		"Too long window": {
+			from: "2021-01-01", to: "2021-12-31",
+			kind: structs.KindValidation,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			report, err := s.Reports.GetTimeReport(ctx, "ann", tc.from, tc.to, tc.groupBy, tc.name, loc)
+			if tc.kind != "" {
+				if structs.KindOf(err) != tc.kind {
+					t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Hours != tc.hours || report.Events != tc.events {
+				t.Errorf("expected %v hours of %d events, got %v of %d", tc.hours, tc.events, report.Hours, report.Events)
+			}
+			if len(report.Groups) != len(tc.groups) {
+				t.Fatalf("expected groups %v, got %v", tc.groups, report.Groups)
+			}
+			for i := range report.Groups {
+				if report.Groups[i] != tc.groups[i] {
+					t.Errorf("expected groups %v, got %v", tc.groups, report.Groups)
+				}
+			}
+		})
+	}
+
+	report, _ := s.Reports.GetTimeReport(ctx, "ann", "2021-12-13", "", "", "", loc)
+	if report.To != "2021-12-19" || len(report.Heatmap) != 7 || report.AverageMinutes != 71.25 {
+		t.Errorf("expected a week with meetings of 71.25 minutes on average, got %v - %v, %v", report.From, report.To, report.AverageMinutes)
+	}
+	levels := []int{4, 3, 2, 0, 0, 0, 0}
+	for i, day := range report.Heatmap {
+		if day.Level != levels[i] {
+			t.Errorf("expected level %d on %v, got %d", levels[i], day.Date, day.Level)
+		}
+	}
+	if len(report.BusiestDays) != 3 || report.BusiestDays[0] != "2021-12-13" || report.BusiestDays[2] != "2021-12-15" {
+		t.Errorf("expected the busiest days from monday to wednesday, got %v", report.BusiestDays)
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// resourceService keeps the rooms and equipment events can book.
+type resourceService struct {
+	repository db.ResourcesRepository
+}
+
+func newResourcesService(repository db.ResourcesRepository) *resourceService {
+	return &resourceService{repository: repository}
+}
+
+func (s *resourceService) AddResource(ctx context.Context, r structs.Resource) (structs.Resource, error) {
+	if err := structs.Validate(r, "validator : invalid data format"); err != nil {
+		return structs.Resource{}, err
+	}
+	if r.Kind == structs.ResourceEquipment && r.Capacity != 0 {
+		return structs.Resource{}, structs.NewValidationError("equipment has no capacity",
+			structs.FieldError{Field: "capacity", Message: "must be 0 for equipment"})
+	}
+	r.Id = 0
+	return s.repository.Add(ctx, r)
+}
+
+func (s *resourceService) GetResources(ctx context.Context) ([]structs.Resource, error) {
+	return s.repository.Get(ctx)
+}
+
+func (s *resourceService) GetResource(ctx context.Context, id int) (structs.Resource, error) {
+	return s.repository.GetByID(ctx, id)
+}
+
+// book checks the resources e is about to book. Whether they are free is
+// checked by the events repository, together with storing the event.
+func (s *resourceService) book(ctx context.Context, e structs.Event) (structs.Event, error) {
+	ids, err := structs.NormalizeResources(e.Resources)
+	if err != nil {
+		return structs.Event{}, err
+	}
+	if s != nil {
+		for _, id := range ids {
+			if _, err = s.repository.GetByID(ctx, id); err != nil {
+				return structs.Event{}, err
+			}
+		}
+	}
+	e.Resources = ids
+	return e, nil
+}
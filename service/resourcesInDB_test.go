@@ -0,0 +1,9 @@
+package service
+
+import "testing"
+
+// The exclusion constraint of event_resources rejects the double bookings here.
+func TestResourcesInDB(t *testing.T) {
+	s, loc := newDBService(t, connectDB(t), "UTC")
+	testResources(t, s, loc)
+}
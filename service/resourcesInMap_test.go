@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestResourcesOnMap(t *testing.T) {
+	s, loc := newMapService(t, "UTC")
+	testResources(t, s, loc)
+}
+
+// testResources books resources of events against each other, s has to be empty.
+func testResources(t *testing.T, s *Service, loc time.Location) {
+	ctx := context.Background()
+	room, err := s.Resources.AddResource(ctx, structs.Resource{Name: "Kyiv 3.01", Kind: structs.ResourceRoom, Capacity: 8, Location: "3rd floor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projector, err := s.Resources.AddResource(ctx, structs.Resource{Name: "Projector", Kind: structs.ResourceEquipment})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Resources.AddResource(ctx, structs.Resource{Name: "Kyiv 3.01", Kind: structs.ResourceRoom}); structs.KindOf(err) != structs.KindConflict {
+		t.Errorf("expected a second resource with the same name to be rejected, got %v", err)
+	}
+	if _, err = s.Resources.AddResource(ctx, structs.Resource{Name: "Screen", Kind: structs.ResourceEquipment, Capacity: 3}); structs.KindOf(err) != structs.KindValidation {
+		t.Errorf("expected equipment with a capacity to be rejected, got %v", err)
+	}
+	if all, _ := s.Resources.GetResources(ctx); len(all) != 2 || all[0] != room || all[1] != projector {
+		t.Errorf("expected the room and the projector, got %v", all)
+	}
+
+	start := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	meeting, err := s.Events.AddEvent(ctx, loc, structs.Event{
+		Name: "Planning", Start: start, End: start.Add(time.Hour), Owner: "ann",
+		Resources: []int{projector.Id, room.Id, room.Id},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int{room.Id, projector.Id}; !reflect.DeepEqual(meeting.Resources, expected) {
+		t.Errorf("expected resources %v, got %v", expected, meeting.Resources)
+	}
+
+	testCases := map[string]struct {
+		start     time.Time
+		resources []int
+		kind      structs.ErrorKind
+	}{
+		"overlapping booking": {start: start.Add(30 * time.Minute), resources: []int{room.Id}, kind: structs.KindConflict},
+		"booking right after": {start: start.Add(time.Hour), resources: []int{room.Id, projector.Id}},
+		"other resource":      {start: start, resources: nil},
+		"unknown resource":    {start: start.Add(5 * time.Hour), resources: []int{42}, kind: structs.KindNotFound},
+		"invalid resource":    {start: start.Add(5 * time.Hour), resources: []int{-1}, kind: structs.KindValidation},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Events.AddEvent(ctx, loc, structs.Event{
+				Name: name, Start: tc.start, End: tc.start.Add(time.Hour), Owner: "bob", Resources: tc.resources,
+			})
+			if tc.kind == "" && err != nil {
+				t.Errorf("expected the event to be booked, got %v", err)
+			}
+			if tc.kind != "" && structs.KindOf(err) != tc.kind {
+				t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+			}
+		})
+	}
+
+	// moving an event onto a booked resource fails and keeps the stored event
+	later, _ := s.Events.AddEvent(ctx, loc, structs.Event{
+		Name: "Retro", Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour), Owner: "bob", Resources: []int{room.Id},
+	})
+	moved := later
+	moved.Start, moved.End = start, start.Add(time.Hour)
+	if _, err = s.Events.UpdateEvent(ctx, later.Id, "bob", moved, loc); structs.KindOf(err) != structs.KindConflict {
+		t.Errorf("expected the move to be rejected, got %v", err)
+	}
+	if stored, _ := s.Events.GetById(ctx, later.Id, "bob", loc); !stored.Start.Equal(later.Start) {
+		t.Errorf("rejected update changed the event to %v", stored)
+	}
+
+	if err = s.Events.DeleteEvent(ctx, meeting.Id, "ann"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Events.UpdateEvent(ctx, later.Id, "bob", moved, loc); err != nil {
+		t.Errorf("expected the room to be free after the meeting was deleted, got %v", err)
+	}
+}
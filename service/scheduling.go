@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// schedulingService finds times several users can meet at, from their events
+// and working hours.
+type schedulingService struct {
+	events *eventService
+	users  *usersService
+}
+
+func newSchedulingService(events *eventService, users *usersService) *schedulingService {
+	return &schedulingService{events: events, users: users}
+}
+
+// Suggest returns the best slots of the search window of r in which every user
+// of r is free. All-day events do not take a time of day, so they do not make
+// their owners busy.
+func (s *schedulingService) Suggest(ctx context.Context, r structs.SuggestionRequest) ([]structs.Suggestion, error) {
+	q, err := structs.NewSuggestionQuery(r)
+	if err != nil {
+		return nil, err
+	}
+	attendees := make([]structs.Attendee, 0, len(q.Users))
+	for _, user := range q.Users {
+		a, err := s.attendee(ctx, user, q)
+		if err != nil {
+			return nil, err
+		}
+		attendees = append(attendees, a)
+	}
+	return structs.FindSlots(q, attendees, time.Now().UTC()), nil
+}
+
+func (s *schedulingService) attendee(ctx context.Context, user string, q structs.SuggestionQuery) (structs.Attendee, error) {
+	loc, err := s.users.GetUserLocation(ctx, user)
+	if err != nil {
+		return structs.Attendee{}, err
+	}
+	a := structs.Attendee{Username: user, Location: &loc}
+
+	window := structs.Interval{Start: q.From.Add(-q.Buffer), End: q.To.Add(q.Buffer)}
+	events, err := s.events.GetEventsOfTheDay(ctx, structs.EventParams{Owner: user, From: window.Start, To: window.End}, loc)
+	if err != nil {
+		return structs.Attendee{}, err
+	}
+	for _, e := range events {
+		busy := structs.Interval{Start: e.Start, End: e.End}
+		if !e.AllDay && busy.Overlaps(window) {
+			a.Busy = append(a.Busy, busy)
+		}
+	}
+
+	if q.WorkingHoursOnly {
+		availability, err := s.users.GetAvailability(ctx, user)
+		if err != nil {
+			return structs.Attendee{}, err
+		}
+		if len(availability.Weekly) > 0 || len(availability.Overrides) > 0 {
+			a.Working = availability.WorkingIntervals(q.From, q.To, &loc)
+			if a.Working == nil {
+				a.Working = []structs.Interval{}
+			}
+		}
+	}
+	return a, nil
+}
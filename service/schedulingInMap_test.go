@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestSchedulingOnMap(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newMapService(t, "Europe/Kiev")
+	if _, err := s.Users.AddUser(ctx, structs.CreateUser{Username: "bob", Password: "pw", Location: "Europe/London"}); err != nil {
+		t.Fatal(err)
+	}
+	workday := structs.Availability{Weekly: map[string][]structs.TimeRange{"monday": {{Start: "09:00", End: "17:00"}}}}
+	for _, user := range []string{"ann", "bob"} {
+		if _, err := s.Users.UpdateAvailability(ctx, user, workday); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a monday, working hours of both of them are 09:00 - 15:00 UTC
+	monday := time.Date(2030, time.January, 7, 0, 0, 0, 0, time.UTC)
+	meeting := monday.Add(9 * time.Hour)
+	if _, err := s.Events.AddEvent(ctx, *time.UTC, structs.Event{
+		Name: "Standup", Start: meeting, End: meeting.Add(time.Hour), Owner: "ann",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	request := structs.SuggestionRequest{
+		Users:            []string{"ann", "bob"},
+		Duration:         "1h",
+		From:             monday,
+		To:               monday.Add(24 * time.Hour),
+		WorkingHoursOnly: true,
+		Buffer:           "15m",
+		Limit:            2,
+	}
+
+	suggestions, err := s.Scheduling.Suggest(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []time.Time{monday.Add(10*time.Hour + 15*time.Minute), monday.Add(11*time.Hour + 15*time.Minute)}
+	if len(suggestions) != len(expected) {
+		t.Fatalf("expected %d suggestions, got %v", len(expected), suggestions)
+	}
+	for i, suggestion := range suggestions {
+		if !suggestion.Start.Equal(expected[i]) || !suggestion.End.Equal(expected[i].Add(time.Hour)) {
+			t.Errorf("expected suggestion %d to start at %v, got %v", i, expected[i], suggestion)
+		}
+	}
+	if local := suggestions[0].Attendees[0]; local.Timezone != "Europe/Kiev" || local.Start.Hour() != 12 {
+		t.Errorf("expected the slot in the time of ann, got %+v", local)
+	}
+
+	testCases := map[string]struct {
+		change func(r *structs.SuggestionRequest)
+		kind   structs.ErrorKind
+	}{
+		"unknown user":    {change: func(r *structs.SuggestionRequest) { r.Users = append(r.Users, "carol") }, kind: structs.KindNotFound},
+		"bad duration":    {change: func(r *structs.SuggestionRequest) { r.Duration = "an hour" }, kind: structs.KindValidation},
+		"negative buffer": {change: func(r *structs.SuggestionRequest) { r.Buffer = "-5m" }, kind: structs.KindValidation},
+		"empty window":    {change: func(r *structs.SuggestionRequest) { r.To = r.From }, kind: structs.KindValidation},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := request
+			r.Users = append([]string(nil), request.Users...)
+			tc.change(&r)
+			if _, err := s.Scheduling.Suggest(ctx, r); structs.KindOf(err) != tc.kind {
+				t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+			}
+		})
+	}
+}
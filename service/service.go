@@ -1,19 +1,42 @@
 package service
 
 import (
+	"time"
+
 	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
 )
 
+const defaultIdempotencyTTL = 24 * time.Hour
+
 type Config struct {
-	EventsRepo db.EventsRepository
-	UsersRepo  db.UserRepository
+	EventsRepo      db.EventsRepository
+	UsersRepo       db.UserRepository
+	IdempotencyRepo db.IdempotencyRepository
+	IdempotencyTTL  time.Duration
+	RelocationRepo  db.RelocationRepository
+	AlertsRepo      db.AlertsRepository
+	ResourcesRepo   db.ResourcesRepository
+	BookingRepo     db.BookingRepository
+	TasksRepo       db.TasksRepository
+	// WeekStart is the weekday views begin weeks on, monday when it is empty
+	WeekStart string
 }
 
 type Service struct {
-	eventsRepo db.EventsRepository
-	usersRepo  db.UserRepository
-	Events     *eventService
-	Users      *usersService
+	eventsRepo  db.EventsRepository
+	usersRepo   db.UserRepository
+	Events      *eventService
+	Users       *usersService
+	Idempotency *idempotencyService
+	Alerts      *alertService
+	Scheduling  *schedulingService
+	Resources   *resourceService
+	Booking     *bookingService
+	Holidays    *holidayService
+	Tasks       *taskService
+	Views       *viewService
+	Reports     *reportService
 }
 
 func NewService(conf *Config) *Service {
@@ -22,7 +45,61 @@ func NewService(conf *Config) *Service {
 		usersRepo:  conf.UsersRepo,
 	}
 
+	alertsRepo := conf.AlertsRepo
+	if alertsRepo == nil {
+		alertsRepo, _ = db.NewAlertsInMemoryRepository()
+	}
+	service.Alerts = newAlertsService(alertsRepo)
+
+	resourcesRepo := conf.ResourcesRepo
+	if resourcesRepo == nil {
+		resourcesRepo, _ = db.NewResourcesInMemoryRepository()
+	}
+	service.Resources = newResourcesService(resourcesRepo)
+
 	service.Events = newEventsService(service.eventsRepo)
+	service.Events.alerts = service.Alerts
+	service.Events.resources = service.Resources
 	service.Users = newUsersService(service.usersRepo)
+	service.Users.events = service.eventsRepo
+	service.Users.alerts = service.Alerts
+	service.Users.relocations = conf.RelocationRepo
+	if service.Users.relocations == nil {
+		service.Users.relocations, _ = db.NewRelocationInMemoryRepository(service.usersRepo, service.eventsRepo)
+	}
+	service.Scheduling = newSchedulingService(service.Events, service.Users)
+
+	bookingRepo := conf.BookingRepo
+	if bookingRepo == nil {
+		bookingRepo, _ = db.NewBookingInMemoryRepository(service.eventsRepo)
+	}
+	service.Booking = newBookingService(bookingRepo)
+	service.Booking.users = service.Users
+	service.Booking.events = service.Events
+	service.Booking.alerts = service.Alerts
+	service.Holidays = newHolidayService()
+
+	tasksRepo := conf.TasksRepo
+	if tasksRepo == nil {
+		tasksRepo, _ = db.NewTasksInMemoryRepository()
+	}
+	service.Tasks = newTaskService(tasksRepo)
+	service.Tasks.alerts = service.Alerts
+
+	weekStart, ok := structs.ParseWeekday(conf.WeekStart)
+	if !ok {
+		weekStart = time.Monday
+	}
+	service.Views = newViewService(service.Events, weekStart)
+	service.Reports = newReportService(service.eventsRepo, weekStart)
+
+	idempotencyRepo, ttl := conf.IdempotencyRepo, conf.IdempotencyTTL
+	if idempotencyRepo == nil {
+		idempotencyRepo, _ = db.NewIdempotencyInMemoryRepository()
+	}
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	service.Idempotency = newIdempotencyService(idempotencyRepo, ttl, defaultIdempotencyLease)
 	return service
 }
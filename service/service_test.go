@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// newMapService returns a service keeping everything in memory, with the user ann living in location.
+func newMapService(t *testing.T, location string) (*Service, time.Location) {
+	eventsRepo, _ := db.NewMapRepository()
+	usersRepo, _ := db.NewUsersInMemoryRepository()
+	s := NewService(&Config{EventsRepo: eventsRepo, UsersRepo: usersRepo})
+	return s, addAnn(t, s, location)
+}
+
+// connectDB connects to the Postgres database named by the DSN environment variable,
+// applies the migrations and empties every table.
+func connectDB(t *testing.T) *sql.DB {
+	conn, err := db.Initialize(os.Getenv("DSN"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	usersRepo, _ := db.NewUsersDBRepository(conn)
+	eventsRepo, _ := db.NewDatabaseRepository(conn)
+	resourcesRepo, _ := db.NewResourcesDBRepository(conn)
+	idempotencyRepo, _ := db.NewIdempotencyDBRepository(conn)
+	for _, clear := range []func() error{usersRepo.ClearRepoData, eventsRepo.ClearRepoData,
+		resourcesRepo.ClearRepoData, idempotencyRepo.ClearRepoData} {
+		if err = clear(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return conn
+}
+
+// newDBService returns a service storing everything in conn, with the user ann living in location.
+func newDBService(t *testing.T, conn *sql.DB, location string) (*Service, time.Location) {
+	eventsRepo, _ := db.NewDatabaseRepository(conn)
+	usersRepo, _ := db.NewUsersDBRepository(conn)
+	idempotencyRepo, _ := db.NewIdempotencyDBRepository(conn)
+	relocationRepo, _ := db.NewRelocationDBRepository(conn)
+	alertsRepo, _ := db.NewAlertsDBRepository(conn)
+	resourcesRepo, _ := db.NewResourcesDBRepository(conn)
+	bookingRepo, _ := db.NewBookingDBRepository(conn)
+	tasksRepo, _ := db.NewTasksDBRepository(conn)
+	s := NewService(&Config{
+		EventsRepo:      eventsRepo,
+		UsersRepo:       usersRepo,
+		IdempotencyRepo: idempotencyRepo,
+		RelocationRepo:  relocationRepo,
+		AlertsRepo:      alertsRepo,
+		ResourcesRepo:   resourcesRepo,
+		BookingRepo:     bookingRepo,
+		TasksRepo:       tasksRepo,
+	})
+	return s, addAnn(t, s, location)
+}
+
+func addAnn(t *testing.T, s *Service, location string) time.Location {
+	ctx := context.Background()
+	if _, err := s.Users.AddUser(ctx, structs.CreateUser{Username: "ann", Password: "pw", Location: location}); err != nil {
+		t.Fatal(err)
+	}
+	loc, err := s.Users.GetUserLocation(ctx, "ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// taskService keeps the to-dos of users, their due reminders are alerts like the ones of events.
+type taskService struct {
+	repository db.TasksRepository
+	alerts     *alertService
+}
+
+func newTaskService(repository db.TasksRepository) *taskService {
+	return &taskService{repository: repository}
+}
+
+func (s *taskService) AddTask(ctx context.Context, user string, loc time.Location, t structs.Task) (structs.Task, error) {
+	t, err := structs.NormalizeTask(t, nil, &loc, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return structs.Task{}, err
+	}
+	t.Owner = user
+	added, err := s.repository.Add(ctx, t)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	s.alerts.scheduleTask(ctx, added)
+	return taskInLocation(added, loc), nil
+}
+
+// GetTasks lists the tasks of user by their due times, due filters them by
+// structs.TasksOverdue or structs.TasksDueToday.
+func (s *taskService) GetTasks(ctx context.Context, user string, status string, due string, loc time.Location) ([]structs.Task, error) {
+	p, err := structs.NewTaskParams(user, status, due, &loc, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repository.Get(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i] = taskInLocation(tasks[i], loc)
+	}
+	return tasks, nil
+}
+
+func (s *taskService) GetTask(ctx context.Context, user string, id int, loc time.Location) (structs.Task, error) {
+	t, err := s.own(ctx, user, id)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	return taskInLocation(t, loc), nil
+}
+
+// UpdateTask replaces the task of user with id. A task is completed when its status
+// becomes done, its reminder is dropped once it is finished.
+func (s *taskService) UpdateTask(ctx context.Context, user string, id int, loc time.Location, t structs.Task) (structs.Task, error) {
+	stored, err := s.own(ctx, user, id)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	t, err = structs.NormalizeTask(t, &stored, &loc, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return structs.Task{}, err
+	}
+	t.Id, t.Owner = id, user
+	updated, err := s.repository.Update(ctx, t)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	s.alerts.scheduleTask(ctx, updated)
+	return taskInLocation(updated, loc), nil
+}
+
+func (s *taskService) DeleteTask(ctx context.Context, user string, id int) error {
+	if _, err := s.own(ctx, user, id); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.alerts.forgetTask(ctx, id)
+	return nil
+}
+
+// own returns the task with id, tasks of other users are reported as missing.
+func (s *taskService) own(ctx context.Context, user string, id int) (structs.Task, error) {
+	t, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return structs.Task{}, err
+	}
+	if t.Owner != user {
+		message := "task with id [" + fmt.Sprint(id) + "] does not exist"
+		return structs.Task{}, fmt.Errorf("%w : %v ", structs.ErrNoMatch, message)
+	}
+	return t, nil
+}
+
+func taskInLocation(t structs.Task, loc time.Location) structs.Task {
+	t.Due = t.Due.In(&loc)
+	if !t.CompletedAt.IsZero() {
+		t.CompletedAt = t.CompletedAt.In(&loc)
+	}
+	return t
+}
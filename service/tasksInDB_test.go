@@ -0,0 +1,8 @@
+package service
+
+import "testing"
+
+func TestTasksInDB(t *testing.T) {
+	s, loc := newDBService(t, connectDB(t), "UTC")
+	testTasks(t, s, loc)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestTasksOnMap(t *testing.T) {
+	s, loc := newMapService(t, "UTC")
+	testTasks(t, s, loc)
+}
+
+// testTasks filters, completes and deletes tasks of ann, s has to be empty apart from her.
+func testTasks(t *testing.T, s *Service, loc time.Location) {
+	ctx := context.Background()
+	// tasks belong to known users in Postgres
+	if _, err := s.Users.AddUser(ctx, structs.CreateUser{Username: "bob", Password: "pw", Location: "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	late, err := s.Tasks.AddTask(ctx, "ann", loc, structs.Task{Title: "Taxes", Due: now.AddDate(0, 0, -2), Priority: structs.TaskHigh})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if late.Status != structs.TaskOpen || !late.CompletedAt.IsZero() {
+		t.Errorf("expected a new open task, got %+v", late)
+	}
+	if _, err = s.Tasks.AddTask(ctx, "ann", loc, structs.Task{Title: "Call mum", Due: now.Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	later, err := s.Tasks.AddTask(ctx, "ann", loc, structs.Task{Title: "Report", Due: now.AddDate(0, 0, 3), RemindBefore: "60m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if later.RemindBefore != "1h" {
+		t.Errorf("expected the reminder to be normalized to 1h, got %v", later.RemindBefore)
+	}
+	if _, err = s.Tasks.AddTask(ctx, "bob", loc, structs.Task{Title: "Gym", Due: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		status   string
+		due      string
+		expected []string
+		kind     structs.ErrorKind
+	}{
+		"All by due time": {
+			expected: []string{"Taxes", "Call mum", "Report"},
+		},
+		"Overdue": {
+			due:      structs.TasksOverdue,
+			expected: []string{"Taxes", "Call mum"},
+		},
+		"Open and overdue": {
+			status:   "open",
+			due:      structs.TasksOverdue,
+			expected: []string{"Taxes", "Call mum"},
+		},
+		"Done": {
+			status:   "done",
+			expected: []string{},
+		},
+		"Unknown status": {
+			status: "later",
+			kind:   structs.KindValidation,
+		},
+		"Unknown due filter": {
+			due:  "tomorrow",
+			kind: structs.KindValidation,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tasks, err := s.Tasks.GetTasks(ctx, "ann", tc.status, tc.due, loc)
+			if tc.kind != "" {
+				if structs.KindOf(err) != tc.kind {
+					t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			titles := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				titles = append(titles, task.Title)
+			}
+			if len(titles) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, titles)
+			}
+			for i := range titles {
+				if titles[i] != tc.expected[i] {
+					t.Errorf("expected %v, got %v", tc.expected, titles)
+				}
+			}
+		})
+	}
+
+	pending, err := s.Alerts.GetAlerts(ctx, "ann", structs.AlertPending, loc)
+	if err != nil || len(pending) != 1 || pending[0].TaskId != later.Id || !pending[0].DueAt.Equal(later.Due.Add(-time.Hour)) {
+		t.Fatalf("expected one pending reminder of the task, got %v %v", pending, err)
+	}
+
+	later.Status = structs.TaskDone
+	done, err := s.Tasks.UpdateTask(ctx, "ann", later.Id, loc, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.CompletedAt.IsZero() {
+		t.Errorf("expected a completion time of a done task")
+	}
+	if pending, _ = s.Alerts.GetAlerts(ctx, "ann", structs.AlertPending, loc); len(pending) != 0 {
+		t.Errorf("expected the reminder of a done task to be dropped, got %v", pending)
+	}
+	done.Status = structs.TaskOpen
+	if reopened, _ := s.Tasks.UpdateTask(ctx, "ann", later.Id, loc, done); !reopened.CompletedAt.IsZero() {
+		t.Errorf("expected a reopened task to lose its completion time, got %v", reopened.CompletedAt)
+	}
+
+	if _, err = s.Tasks.GetTask(ctx, "bob", late.Id, loc); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("expected tasks of other users to be missing, got %v", err)
+	}
+	if err = s.Tasks.DeleteTask(ctx, "bob", late.Id); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("expected tasks of other users not to be deleted, got %v", err)
+	}
+	if err = s.Tasks.DeleteTask(ctx, "ann", later.Id); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ = s.Alerts.GetAlerts(ctx, "ann", "", loc); len(pending) != 0 {
+		t.Errorf("expected the alerts of a deleted task to be dropped, got %v", pending)
+	}
+	if _, err = s.Tasks.GetTask(ctx, "ann", later.Id, loc); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("expected a deleted task to be missing, got %v", err)
+	}
+}
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/dkucheru/Calendar/db"
@@ -10,7 +13,10 @@ import (
 )
 
 type usersService struct {
-	repository db.UserRepository
+	repository  db.UserRepository
+	events      db.EventsRepository
+	relocations db.RelocationRepository
+	alerts      *alertService
 }
 
 func newUsersService(repository db.UserRepository) *usersService {
@@ -20,19 +26,22 @@ func newUsersService(repository db.UserRepository) *usersService {
 	return &s
 }
 
-func (s *usersService) AddUser(newUser structs.CreateUser) (structs.HashedInfo, error) {
-	return s.repository.AddUser(newUser)
+func (s *usersService) AddUser(ctx context.Context, newUser structs.CreateUser) (structs.HashedInfo, error) {
+	return s.repository.AddUser(ctx, newUser)
 }
 
-func (s *usersService) CheckPassword(user string, pass string) error {
-	userInfo, err := s.repository.GetUser(user)
+func (s *usersService) CheckPassword(ctx context.Context, user string, pass string) error {
+	userInfo, err := s.repository.GetUser(ctx, user)
 	if err != nil {
-		return errors.New("username is not valid")
+		if errors.Is(err, structs.ErrCanceled) || errors.Is(err, structs.ErrTimeout) {
+			return err
+		}
+		return fmt.Errorf("%w : username is not valid", structs.ErrUnauthorized)
 	}
 
 	hashedPassword := userInfo.HashedPass
 	if err := compare(hashedPassword, pass); err != nil {
-		return errors.New("incorrect password")
+		return fmt.Errorf("%w : incorrect password", structs.ErrUnauthorized)
 	}
 	return nil
 }
@@ -44,14 +53,165 @@ func compare(hash string, s string) error {
 	return bcrypt.CompareHashAndPassword(existing, incoming)
 }
 
-func (s *usersService) GetUserLocation(username string) (time.Location, error) {
-	userInfo, err := s.repository.GetUser(username)
+func (s *usersService) GetUserLocation(ctx context.Context, username string) (time.Location, error) {
+	userInfo, err := s.repository.GetUser(ctx, username)
 	if err != nil {
 		return time.Location{}, err
 	}
 	return userInfo.Location, nil
 }
 
-func (s *usersService) UpdateLocation(user string, newLocation time.Location) (structs.HashedInfo, error) {
-	return s.repository.UpdateLocation(user, newLocation)
+// GetAvailability returns the working hours of user, written in their location.
+func (s *usersService) GetAvailability(ctx context.Context, username string) (structs.Availability, error) {
+	userInfo, err := s.repository.GetUser(ctx, username)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	a, err := s.repository.GetAvailability(ctx, username)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	a.Timezone = userInfo.Location.String()
+	return a, nil
+}
+
+// UpdateAvailability replaces the working hours of user. They are kept in the
+// location of the user, so the timezone of a is not used.
+func (s *usersService) UpdateAvailability(ctx context.Context, username string, a structs.Availability) (structs.Availability, error) {
+	normalized, err := structs.NormalizeAvailability(a)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	userInfo, err := s.repository.GetUser(ctx, username)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	updated, err := s.repository.UpdateAvailability(ctx, username, normalized)
+	if err != nil {
+		return structs.Availability{}, err
+	}
+	updated.Timezone = userInfo.Location.String()
+	return updated, nil
+}
+
+// UpdateLocation moves user to newLocation. Events picked by opts are rebased, so
+// that they keep their wall-clock times in the new location. All-day events and
+// events with time zones of their own do not depend on the location and stay as
+// they are. A dry run only reports what would be rebased.
+func (s *usersService) UpdateLocation(ctx context.Context, user string, newLocation time.Location,
+	opts structs.RebaseOptions) (structs.Relocation, error) {
+	if opts.Scope == "" {
+		opts.Scope = structs.RebaseNone
+	}
+	switch opts.Scope {
+	case structs.RebaseNone, structs.RebaseFuture, structs.RebaseAll:
+		if len(opts.EventIDs) > 0 {
+			return structs.Relocation{}, structs.NewValidationError("events can only be picked for a selected rebase",
+				structs.FieldError{Field: "events", Message: "requires rebase=" + string(structs.RebaseSelected)})
+		}
+	case structs.RebaseSelected:
+		if len(opts.EventIDs) == 0 {
+			return structs.Relocation{}, structs.NewValidationError("no events were selected",
+				structs.FieldError{Field: "events", Message: "is required for a selected rebase"})
+		}
+	default:
+		return structs.Relocation{}, structs.NewValidationError("unknown rebase scope ["+string(opts.Scope)+"]",
+			structs.FieldError{Field: "rebase", Message: "must be one of none, future, all, selected"})
+	}
+
+	current, err := s.repository.GetUser(ctx, user)
+	if err != nil {
+		return structs.Relocation{}, err
+	}
+	rebased, moved, err := s.rebaseEvents(ctx, current, newLocation, opts)
+	if err != nil {
+		return structs.Relocation{}, err
+	}
+	relocation := structs.Relocation{
+		User:     current,
+		Location: newLocation.String(),
+		Scope:    opts.Scope,
+		DryRun:   opts.DryRun,
+		Events:   rebased,
+	}
+	if opts.DryRun {
+		relocation.User.Location = newLocation
+		return relocation, nil
+	}
+
+	if len(moved) == 0 {
+		relocation.User, err = s.repository.UpdateLocation(ctx, user, newLocation)
+	} else {
+		relocation.User, err = s.relocations.Relocate(ctx, user, newLocation, moved)
+	}
+	if err != nil {
+		return structs.Relocation{}, err
+	}
+	for _, e := range moved {
+		s.alerts.schedule(ctx, e)
+	}
+	return relocation, nil
+}
+
+// rebaseEvents picks the events of user that follow the change of location and
+// returns them both as a report and in the form they are stored in. Events stored
+// without an owner belong to every user, so they follow whoever relocates.
+func (s *usersService) rebaseEvents(ctx context.Context, user structs.HashedInfo, newLocation time.Location,
+	opts structs.RebaseOptions) ([]structs.RebasedEvent, []structs.Event, error) {
+	rebased := make([]structs.RebasedEvent, 0)
+	if opts.Scope == structs.RebaseNone {
+		return rebased, nil, nil
+	}
+	owned, err := s.events.Get(ctx, structs.EventParams{Owner: user.Username})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Sort(ByStartTime(owned))
+
+	selected := make(map[int]bool, len(opts.EventIDs))
+	for _, id := range opts.EventIDs {
+		selected[id] = true
+	}
+	now := time.Now()
+	var moved []structs.Event
+	for _, e := range owned {
+		switch opts.Scope {
+		case structs.RebaseFuture:
+			if !e.Start.After(now) {
+				continue
+			}
+		case structs.RebaseSelected:
+			if !selected[e.Id] {
+				continue
+			}
+			delete(selected, e.Id)
+		}
+		if e.AllDay || e.StartTZ != "" || e.EndTZ != "" {
+			continue
+		}
+		m, err := structs.RebaseEvent(e, &user.Location, &newLocation)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rebasing event [%d] : %w", e.Id, err)
+		}
+		moved = append(moved, m)
+		rebased = append(rebased, structs.RebasedEvent{
+			Id:           e.Id,
+			Name:         e.Name,
+			Start:        e.Start.In(&user.Location),
+			End:          e.End.In(&user.Location),
+			RebasedStart: m.Start.In(&newLocation),
+			RebasedEnd:   m.End.In(&newLocation),
+		})
+	}
+
+	// events of other users are reported as missing, like any other unknown id
+	if len(selected) > 0 {
+		missing := make([]int, 0, len(selected))
+		for id := range selected {
+			missing = append(missing, id)
+		}
+		sort.Ints(missing)
+		return nil, nil, fmt.Errorf("%w : events %v do not exist ", structs.ErrNoMatch, missing)
+	}
+	return rebased, moved, nil
 }
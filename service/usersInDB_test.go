@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -11,7 +12,7 @@ import (
 func AddUserInDB(t *testing.T) {
 	var testRepo, _ = db.NewUsersInMemoryRepository()
 	var testService = newUsersService(testRepo)
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         structs.CreateUser
 		errorMessage string
@@ -36,13 +37,13 @@ func AddUserInDB(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			returnedInfo, err := testService.AddUser(test.user)
+			returnedInfo, err := testService.AddUser(context.Background(), test.user)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
 			}
 
-			foundUser, err := testService.repository.GetUser(test.user.Username)
+			foundUser, err := testService.repository.GetUser(context.Background(), test.user.Username)
 			if err != nil {
 				t.Errorf("error finding new user")
 			}
@@ -63,7 +64,7 @@ func AddUserInDB(t *testing.T) {
 func UpdateUserTimezone(t *testing.T) {
 	var testRepo, _ = db.NewUsersInMemoryRepository()
 	var testService = newUsersService(testRepo)
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         string
 		loc          time.Location
@@ -83,18 +84,18 @@ func UpdateUserTimezone(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			returnedInfo, err := testService.UpdateLocation(test.user, test.loc)
+			returnedInfo, err := testService.UpdateLocation(context.Background(), test.user, test.loc, structs.RebaseOptions{})
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
 			}
 
-			foundUser, err := testService.repository.GetUser(test.user)
+			foundUser, err := testService.repository.GetUser(context.Background(), test.user)
 			if err != nil {
 				t.Errorf("error finding changed user")
 			}
 
-			if foundUser.Location.String() != returnedInfo.Location.String() {
+			if foundUser.Location.String() != returnedInfo.User.Location.String() {
 				t.Errorf("location change malfunction")
 			}
 		})
@@ -106,7 +107,7 @@ func UpdateUserTimezone(t *testing.T) {
 func CheckPassword(t *testing.T) {
 	var testRepo, _ = db.NewUsersInMemoryRepository()
 	var testService = newUsersService(testRepo)
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         string
 		pass         string
@@ -131,7 +132,7 @@ func CheckPassword(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			err := testService.CheckPassword(test.user, test.pass)
+			err := testService.CheckPassword(context.Background(), test.user, test.pass)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
@@ -143,7 +144,7 @@ func CheckPassword(t *testing.T) {
 func GetUserLocation(t *testing.T) {
 	var testRepo, _ = db.NewUsersInMemoryRepository()
 	var testService = newUsersService(testRepo)
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         string
 		errorMessage string
@@ -160,7 +161,7 @@ func GetUserLocation(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			_, err := testService.GetUserLocation(test.user)
+			_, err := testService.GetUserLocation(context.Background(), test.user)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
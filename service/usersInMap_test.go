@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/db"
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// failingEventsRepo fails to update the event with id failOn.
+type failingEventsRepo struct {
+	*db.MapRepository
+	failOn int
+}
+
+func (f *failingEventsRepo) Update(ctx context.Context, id int, newEvent structs.Event) (structs.Event, error) {
+	if id == f.failOn {
+		return structs.Event{}, errors.New("disk is full")
+	}
+	return f.MapRepository.Update(ctx, id, newEvent)
+}
+
+func TestUpdateLocationRebase(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kiev")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	past := time.Date(2021, 7, 15, 14, 0, 0, 0, kyiv)
+	future := time.Now().In(kyiv).Add(48 * time.Hour).Truncate(time.Hour)
+
+	setup := func(t *testing.T, failOn int) (*Service, map[string]structs.Event) {
+		mapRepo, _ := db.NewMapRepository()
+		eventsRepo := &failingEventsRepo{MapRepository: mapRepo, failOn: failOn}
+		usersRepo, _ := db.NewUsersInMemoryRepository()
+		s := NewService(&Config{EventsRepo: eventsRepo, UsersRepo: usersRepo})
+		if _, err := s.Users.AddUser(context.Background(), structs.CreateUser{Username: "ann", Password: "pw", Location: "Europe/Kiev"}); err != nil {
+			t.Fatal(err)
+		}
+		events := map[string]structs.Event{
+			"past":    {Name: "past", Start: past, End: past.Add(time.Hour), Alert: past.Add(-time.Hour), Owner: "ann"},
+			"future":  {Name: "future", Start: future, End: future.Add(time.Hour), Owner: "ann"},
+			"all-day": {Name: "all-day", Start: structs.Date(future), End: structs.Date(future).AddDate(0, 0, 1), AllDay: true, Owner: "ann"},
+			"pinned":  {Name: "pinned", Start: future, End: future.Add(time.Hour), StartTZ: "Europe/Kiev", EndTZ: "Europe/Kiev", Owner: "ann"},
+			"other":   {Name: "other", Start: future, End: future.Add(time.Hour), Owner: "bob"},
+		}
+		// added in a fixed order, so "future" always gets id 2
+		for _, name := range []string{"past", "future", "all-day", "pinned", "other"} {
+			added, err := s.Events.AddEvent(context.Background(), *kyiv, events[name])
+			if err != nil {
+				t.Fatal(err)
+			}
+			events[name] = added
+		}
+		return s, events
+	}
+
+	testCases := map[string]struct {
+		opts         func(events map[string]structs.Event) structs.RebaseOptions
+		failOn       int
+		rebased      []string
+		errorMessage string
+	}{
+		"Location only": {
+			opts:    func(map[string]structs.Event) structs.RebaseOptions { return structs.RebaseOptions{} },
+			rebased: []string{},
+		},
+		"Future events": {
+			opts: func(map[string]structs.Event) structs.RebaseOptions {
+				return structs.RebaseOptions{Scope: structs.RebaseFuture}
+			},
+			rebased: []string{"future"},
+		},
+		"All events": {
+			opts: func(map[string]structs.Event) structs.RebaseOptions {
+				return structs.RebaseOptions{Scope: structs.RebaseAll}
+			},
+			rebased: []string{"past", "future"},
+		},
+		"Dry run": {
+			opts: func(map[string]structs.Event) structs.RebaseOptions {
+				return structs.RebaseOptions{Scope: structs.RebaseAll, DryRun: true}
+			},
+			rebased: []string{"past", "future"},
+		},
+		"Selected events": {
+			opts: func(events map[string]structs.Event) structs.RebaseOptions {
+				return structs.RebaseOptions{Scope: structs.RebaseSelected, EventIDs: []int{events["past"].Id}}
+			},
+			rebased: []string{"past"},
+		},
+		"Event of another user": {
+			opts: func(events map[string]structs.Event) structs.RebaseOptions {
+				return structs.RebaseOptions{Scope: structs.RebaseSelected, EventIDs: []int{events["past"].Id, events["other"].Id}}
+			},
+			errorMessage: "do not exist",
+		},
+		"Unknown scope": {
+			opts: func(map[string]structs.Event) structs.RebaseOptions {
+				return structs.RebaseOptions{Scope: "some"}
+			},
+			errorMessage: "unknown rebase scope [some]",
+		},
+		"Failure rolls back": {
+			opts: func(map[string]structs.Event) structs.RebaseOptions {
+				return structs.RebaseOptions{Scope: structs.RebaseAll}
+			},
+			failOn:       2,
+			errorMessage: "disk is full",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s, events := setup(t, test.failOn)
+			opts := test.opts(events)
+			relocation, err := s.Users.UpdateLocation(context.Background(), "ann", *tokyo, opts)
+			if !ErrorContains(err, test.errorMessage) {
+				t.Fatalf("wrong error : got %q, wanted %q", err, test.errorMessage)
+			}
+
+			loc, _ := s.Users.GetUserLocation(context.Background(), "ann")
+			changed := err == nil && !opts.DryRun
+			if changed != (loc.String() == tokyo.String()) {
+				t.Errorf("unexpected location %v", loc.String())
+			}
+			if err != nil {
+				test.rebased = nil
+			}
+
+			reported := make(map[int]bool)
+			for _, r := range relocation.Events {
+				reported[r.Id] = true
+				if r.Start.Format("15:04") != r.RebasedStart.Format("15:04") {
+					t.Errorf("wall clock of %v changed from %v to %v", r.Name, r.Start, r.RebasedStart)
+				}
+			}
+			if len(reported) != len(test.rebased) {
+				t.Errorf("expected %v to be rebased, got %v", test.rebased, relocation.Events)
+			}
+			for _, name := range test.rebased {
+				if !reported[events[name].Id] {
+					t.Errorf("event %v was not reported", name)
+				}
+			}
+
+			for name, e := range events {
+				stored, err := s.Events.GetById(context.Background(), e.Id, e.Owner, *tokyo)
+				if err != nil {
+					t.Fatal(err)
+				}
+				moved := changed && reported[e.Id]
+				if moved != !stored.Start.Equal(e.Start) {
+					t.Errorf("event %v moved : %v, expected %v", name, !moved, moved)
+				}
+				if moved && fmt.Sprint(stored.Start.Clock()) != fmt.Sprint(e.Start.In(kyiv).Clock()) {
+					t.Errorf("event %v starts at %v in the new location", name, stored.Start)
+				}
+				if rebased, _ := structs.RebaseEvent(e, kyiv, tokyo); moved && !e.Alert.IsZero() && !stored.Alert.Equal(rebased.Alert) {
+					t.Errorf("alert of %v was not rebased", name)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateLocationWithUnschedulableReminder(t *testing.T) {
+	ctx := context.Background()
+	s, kyiv := newMapService(t, "Europe/Kiev")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	kept, err := s.Events.AddEvent(ctx, kyiv, structs.Event{Name: "Kept", Start: start, End: start.Add(time.Hour), Owner: "ann"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// written around the service, which would not have taken the offset
+	if _, err = s.eventsRepo.Add(ctx, structs.Event{Name: "Broken", Start: start, End: start.Add(time.Hour), Owner: "ann",
+		Reminders: []structs.Reminder{{Offset: "soon"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Users.UpdateLocation(ctx, "ann", *tokyo, structs.RebaseOptions{Scope: structs.RebaseAll})
+	if structs.KindOf(err) != structs.KindValidation {
+		t.Fatalf("expected the reminder to fail the relocation, got %v", err)
+	}
+	if loc, _ := s.Users.GetUserLocation(ctx, "ann"); loc.String() != "Europe/Kiev" {
+		t.Errorf("expected ann to stay in Europe/Kiev, got %v", loc.String())
+	}
+	if stored, _ := s.eventsRepo.GetByID(ctx, kept.Id); !stored.Start.Equal(kept.Start) {
+		t.Errorf("expected %v to stay at %v, got %v", kept.Name, kept.Start, stored.Start)
+	}
+}
+
+func TestUpdateLocationRebasesEventsWithoutOwner(t *testing.T) {
+	ctx := context.Background()
+	s, kyiv := newMapService(t, "Europe/Kiev")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	start := time.Now().In(&kyiv).Add(48 * time.Hour).Truncate(time.Hour)
+	// stored before events had owners
+	legacy, err := s.eventsRepo.Add(ctx, structs.Event{Name: "Legacy", Start: start, End: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := s.Users.UpdateLocation(ctx, "ann", *tokyo, structs.RebaseOptions{Scope: structs.RebaseAll, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Events) != 1 || preview.Events[0].Id != legacy.Id {
+		t.Fatalf("expected the preview to list the event without an owner, got %v", preview.Events)
+	}
+	if _, err = s.Users.UpdateLocation(ctx, "ann", *tokyo, structs.RebaseOptions{Scope: structs.RebaseAll}); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := s.eventsRepo.GetByID(ctx, legacy.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Start.In(tokyo).Format("15:04") != start.Format("15:04") {
+		t.Errorf("expected the event to keep starting at %v, got %v", start.Format("15:04"), moved.Start.In(tokyo))
+	}
+}
+
+func TestAvailabilityOnMap(t *testing.T) {
+	usersRepo, _ := db.NewUsersInMemoryRepository()
+	s := newUsersService(usersRepo)
+	ctx := context.Background()
+	if _, err := s.AddUser(ctx, structs.CreateUser{Username: "ann", Password: "pw", Location: "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+
+	empty, err := s.GetAvailability(ctx, "ann")
+	if err != nil || len(empty.Weekly) != 0 || len(empty.Overrides) != 0 || empty.Timezone != "UTC" {
+		t.Errorf("expected an empty profile in UTC, got %+v %v", empty, err)
+	}
+
+	profile := structs.Availability{
+		Timezone:  "Asia/Tokyo",
+		Weekly:    map[string][]structs.TimeRange{"monday": {{Start: "13:00", End: "17:00"}, {Start: "09:00", End: "12:00"}}},
+		Overrides: []structs.DateOverride{{Date: "2021-12-27"}},
+	}
+	updated, err := s.UpdateAvailability(ctx, "ann", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Timezone != "UTC" || updated.Weekly["monday"][0].Start != "09:00" {
+		t.Errorf("expected a sorted profile in the location of the user, got %+v", updated)
+	}
+	stored, err := s.GetAvailability(ctx, "ann")
+	if err != nil || !reflect.DeepEqual(stored, updated) {
+		t.Errorf("expected %+v to be stored, got %+v %v", updated, stored, err)
+	}
+
+	if _, err = s.UpdateAvailability(ctx, "bob", profile); structs.KindOf(err) != structs.KindNotFound {
+		t.Errorf("expected an unknown user, got %v", err)
+	}
+	profile.Weekly["monday"] = append(profile.Weekly["monday"], structs.TimeRange{Start: "11:00", End: "14:00"})
+	if _, err = s.UpdateAvailability(ctx, "ann", profile); structs.KindOf(err) != structs.KindValidation {
+		t.Errorf("expected overlapping ranges to be rejected, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -11,8 +12,7 @@ import (
 )
 
 func AddUser(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -27,7 +27,7 @@ func AddUser(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         structs.CreateUser
 		errorMessage string
@@ -52,13 +52,13 @@ func AddUser(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			returnedInfo, err := testService.AddUser(test.user)
+			returnedInfo, err := testService.AddUser(context.Background(), test.user)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
 			}
 
-			foundUser, err := testService.repository.GetUser(test.user.Username)
+			foundUser, err := testService.repository.GetUser(context.Background(), test.user.Username)
 			if err != nil {
 				t.Errorf("error finding new user")
 			}
@@ -77,8 +77,7 @@ func AddUser(t *testing.T) {
 }
 
 func UpdateUserTimezoneInDB(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -92,7 +91,7 @@ func UpdateUserTimezoneInDB(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         string
 		loc          time.Location
@@ -112,18 +111,18 @@ func UpdateUserTimezoneInDB(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			returnedInfo, err := testService.UpdateLocation(test.user, test.loc)
+			returnedInfo, err := testService.UpdateLocation(context.Background(), test.user, test.loc, structs.RebaseOptions{})
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
 			}
 
-			foundUser, err := testService.repository.GetUser(test.user)
+			foundUser, err := testService.repository.GetUser(context.Background(), test.user)
 			if err != nil {
 				t.Errorf("error finding changed user")
 			}
 
-			if foundUser.Location.String() != returnedInfo.Location.String() {
+			if foundUser.Location.String() != returnedInfo.User.Location.String() {
 				t.Errorf("location change malfunction")
 			}
 		})
@@ -133,8 +132,7 @@ func UpdateUserTimezoneInDB(t *testing.T) {
 }
 
 func CheckPasswordFromDB(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -148,7 +146,7 @@ func CheckPasswordFromDB(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         string
 		pass         string
@@ -173,7 +171,7 @@ func CheckPasswordFromDB(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			err := testService.CheckPassword(test.user, test.pass)
+			err := testService.CheckPassword(context.Background(), test.user, test.pass)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
@@ -183,8 +181,7 @@ func CheckPasswordFromDB(t *testing.T) {
 }
 
 func GetUserLocationFromDB(t *testing.T) {
-	downMigrate := false
-	repo, err := db.Initialize(os.Getenv("DSN"), downMigrate)
+	repo, err := db.Initialize(os.Getenv("DSN"))
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -198,7 +195,7 @@ func GetUserLocationFromDB(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	testService.AddUser(structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
+	testService.AddUser(context.Background(), structs.CreateUser{Username: "testUserExists", Password: "o!", Location: "Local"})
 	testCases := map[string]struct {
 		user         string
 		errorMessage string
@@ -215,7 +212,7 @@ func GetUserLocationFromDB(t *testing.T) {
 
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			_, err := testService.GetUserLocation(test.user)
+			_, err := testService.GetUserLocation(context.Background(), test.user)
 
 			if !ErrorContains(err, test.errorMessage) {
 				t.Errorf("wrong error : got %q, wanted %q", err, test.errorMessage)
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+// viewService lays the events of a user out on the days of agenda, day, week and month views.
+type viewService struct {
+	events    *eventService
+	weekStart time.Weekday
+}
+
+func newViewService(events *eventService, weekStart time.Weekday) *viewService {
+	return &viewService{events: events, weekStart: weekStart}
+}
+
+// GetView returns the view of kind around date in loc, today by default. Weeks begin
+// on weekStart, or on the configured weekday when it is empty. Agenda views list the
+// days with events from date on.
+func (s *viewService) GetView(ctx context.Context, user string, kind string, date string, days string,
+	weekStart string, holidays string, loc time.Location) (structs.CalendarView, error) {
+	p, err := structs.NewViewParams(kind, date, days, weekStart, s.weekStart, &loc, time.Now())
+	if err != nil {
+		return structs.CalendarView{}, err
+	}
+	p.Owner, p.Holidays = user, holidays
+	events, err := s.eventsOf(ctx, p, loc)
+	if err != nil {
+		return structs.CalendarView{}, err
+	}
+	return structs.BuildView(p, events, &loc), nil
+}
+
+// eventsOf collects the events of the months the days of p fall on. Repositories
+// match days in UTC, so the months of the days around the view are read too.
+func (s *viewService) eventsOf(ctx context.Context, p structs.ViewParams, loc time.Location) ([]structs.Event, error) {
+	first := structs.Date(p.First.AddDate(0, 0, -1))
+	last := structs.Date(p.End())
+	seen := make(map[int]bool)
+	var events []structs.Event
+	for month := first.AddDate(0, 0, 1-first.Day()); !month.After(last); month = month.AddDate(0, 1, 0) {
+		found, err := s.events.GetEventsOfTheDay(ctx, structs.EventParams{
+			Owner:    p.Owner,
+			Year:     month.Year(),
+			Month:    int(month.Month()),
+			Holidays: p.Holidays,
+		}, loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range found {
+			// holidays are not stored, they are listed in the month they fall on only
+			if e.Id != 0 && seen[e.Id] {
+				continue
+			}
+			seen[e.Id] = true
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
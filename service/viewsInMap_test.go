@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkucheru/Calendar/structs"
+)
+
+func TestViewsOnMap(t *testing.T) {
+	ctx := context.Background()
+	s, loc := newMapService(t, "Europe/Kiev")
+	at := func(day int, hour int, min int) time.Time {
+		return time.Date(2021, time.December, day, hour, min, 0, 0, &loc)
+	}
+	events := []structs.Event{
+		// the first one is on the 30th of November in UTC
+		{Name: "Early", Start: at(1, 0, 30), End: at(1, 1, 0)},
+		{Name: "Late call", Start: at(13, 23, 0), End: at(14, 1, 0)},
+		{Name: "Standup", Start: at(15, 9, 0), End: at(15, 9, 15)},
+		{Name: "Trip", Start: structs.Date(at(24, 0, 0)), End: structs.Date(at(27, 0, 0)), AllDay: true},
+	}
+	for _, e := range events {
+		e.Owner = "ann"
+		if _, err := s.Events.AddEvent(ctx, loc, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := map[string]struct {
+		view      string
+		date      string
+		days      string
+		weekStart string
+		start     string
+		end       string
+		total     int
+		counts    map[string]int
+		kind      structs.ErrorKind
+	}{
+		"Day with the end of an event": {
+			view: structs.DayView, date: "2021-12-14",
+			start: "2021-12-14", end: "2021-12-14", total: 1,
+			counts: map[string]int{"2021-12-14": 1},
+		},
+		"Week from monday": {
+			view: structs.WeekView, date: "2021-12-15",
+			start: "2021-12-13", end: "2021-12-19", total: 2,
+			counts: map[string]int{"2021-12-13": 1, "2021-12-14": 1, "2021-12-15": 1},
+		},
+		"Week from sunday": {
+			view: structs.WeekView, date: "2021-12-12", weekStart: "sunday",
+			start: "2021-12-12", end: "2021-12-18", total: 2,
+			counts: map[string]int{"2021-12-13": 1, "2021-12-14": 1, "2021-12-15": 1},
+		},
+		"Month grid": {
+			view: structs.MonthView, date: "2021-12-20",
+			start: "2021-11-29", end: "2022-01-02", total: 4,
+			counts: map[string]int{
+				"2021-12-01": 1, "2021-12-13": 1, "2021-12-14": 1, "2021-12-15": 1,
+				"2021-12-24": 1, "2021-12-25": 1, "2021-12-26": 1,
+			},
+		},
+		"Agenda lists days with events": {
+			view: structs.AgendaView, date: "2021-12-13", days: "14",
+			start: "2021-12-13", end: "2021-12-26", total: 3,
+			counts: map[string]int{
+				"2021-12-13": 1, "2021-12-14": 1, "2021-12-15": 1, "2021-12-24": 1, "2021-12-25": 1, "2021-12-26": 1,
+			},
+		},
+		"Unknown week start": {
+			view: structs.WeekView, weekStart: "someday",
+			kind: structs.KindValidation,
+		},
+		"Too long agenda": {
+			view: structs.AgendaView, days: "100",
+			kind: structs.KindValidation,
+		},
+		"Invalid date": {
+			view: structs.DayView, date: "14.12.2021",
+			kind: structs.KindValidation,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, err := s.Views.GetView(ctx, "ann", tc.view, tc.date, tc.days, tc.weekStart, "", loc)
+			if tc.kind != "" {
+				if structs.KindOf(err) != tc.kind {
+					t.Errorf("expected an error of kind %v, got %v", tc.kind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if view.Start != tc.start || view.End != tc.end || view.Total != tc.total {
+				t.Errorf("expected %v - %v with %d events, got %v - %v with %d", tc.start, tc.end, tc.total, view.Start, view.End, view.Total)
+			}
+			for _, d := range view.Days {
+				if d.Count != tc.counts[d.Date] || len(d.Events) != d.Count {
+					t.Errorf("expected %d events on %v, got %d", tc.counts[d.Date], d.Date, d.Count)
+				}
+			}
+		})
+	}
+
+	week, _ := s.Views.GetView(ctx, "ann", structs.WeekView, "2021-12-13", "", "", "", loc)
+	call := week.Days[0].Events[0]
+	if !call.Start.Equal(at(13, 23, 0)) || !call.End.Equal(at(14, 0, 0)) || call.Continued || !call.Continues {
+		t.Errorf("expected the call to be split at midnight, got %+v", call)
+	}
+	if rest := week.Days[1].Events[0]; !rest.Start.Equal(at(14, 0, 0)) || !rest.Continued || rest.Continues {
+		t.Errorf("expected the call to go on after midnight, got %+v", rest)
+	}
+	month, _ := s.Views.GetView(ctx, "ann", structs.MonthView, "2021-12-01", "", "", "", loc)
+	if len(month.Days) != 35 || !month.Days[0].Outside || month.Days[2].Outside {
+		t.Errorf("expected five whole weeks with the days of november marked, got %v days", len(month.Days))
+	}
+}
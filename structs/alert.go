@@ -0,0 +1,94 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlertState is where an alert instance is in its life:
+// pending -> delivered -> (snoozed -> delivered)* -> acknowledged or dismissed.
+type AlertState string
+
+const (
+	AlertPending      AlertState = "pending"
+	AlertDelivered    AlertState = "delivered"
+	AlertSnoozed      AlertState = "snoozed"
+	AlertAcknowledged AlertState = "acknowledged"
+	AlertDismissed    AlertState = "dismissed"
+)
+
+// MaxSnooze is the longest an alert can be snoozed for at once.
+const MaxSnooze = 7 * 24 * time.Hour
+
+// AlertInstance is one firing of the alert of an event, or the reminder of a
+// task. AlertAt is the alert time of the event, DueAt is when the instance is
+// delivered next, which is AlertAt at first and the end of the snooze after a
+// snooze. Alerts of tasks have a TaskId instead of an EventId, their EventName
+// is the title of the task.
+type AlertInstance struct {
+	Id         int        `json:"id"`
+	EventId    int        `json:"event_id,omitempty"`
+	TaskId     int        `json:"task_id,omitempty"`
+	EventName  string     `json:"event_name"`
+	Owner      string     `json:"-"`
+	State      AlertState `json:"state"`
+	AlertAt    time.Time  `json:"alert_at"`
+	DueAt      time.Time  `json:"due_at"`
+	Deliveries int        `json:"deliveries"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type AlertParams struct {
+	Owner string
+	State AlertState
+}
+
+// ParseAlertState accepts the states a list of alerts can be filtered by, the empty state matches all.
+func ParseAlertState(state string) (AlertState, error) {
+	switch s := AlertState(state); s {
+	case "", AlertPending, AlertDelivered, AlertSnoozed, AlertAcknowledged, AlertDismissed:
+		return s, nil
+	}
+	return "", NewValidationError("unknown alert state ["+state+"]",
+		FieldError{Field: "state", Message: "must be one of pending, delivered, snoozed, acknowledged, dismissed"})
+}
+
+// Done tells whether the user already reacted to the alert for good.
+func (a AlertInstance) Done() bool {
+	return a.State == AlertAcknowledged || a.State == AlertDismissed
+}
+
+// Snooze delivers a delivered alert again after d.
+func (a AlertInstance) Snooze(now time.Time, d time.Duration) (AlertInstance, error) {
+	if d <= 0 || d > MaxSnooze {
+		return AlertInstance{}, NewValidationError("bad snooze duration",
+			FieldError{Field: "for", Message: "must be a positive duration of at most " + fmt.Sprint(MaxSnooze)})
+	}
+	if a.State != AlertDelivered && a.State != AlertSnoozed {
+		return AlertInstance{}, a.stateError("snoozed")
+	}
+	a.State, a.DueAt, a.UpdatedAt = AlertSnoozed, now.Add(d), now
+	return a, nil
+}
+
+// Acknowledge marks a delivered alert as seen.
+func (a AlertInstance) Acknowledge(now time.Time) (AlertInstance, error) {
+	if a.State != AlertDelivered && a.State != AlertSnoozed {
+		return AlertInstance{}, a.stateError("acknowledged")
+	}
+	a.State, a.UpdatedAt = AlertAcknowledged, now
+	return a, nil
+}
+
+// Dismiss stops the alert, pending alerts are then never delivered.
+func (a AlertInstance) Dismiss(now time.Time) (AlertInstance, error) {
+	if a.Done() {
+		return AlertInstance{}, a.stateError("dismissed")
+	}
+	a.State, a.UpdatedAt = AlertDismissed, now
+	return a, nil
+}
+
+func (a AlertInstance) stateError(action string) error {
+	return NewError(KindConflict, fmt.Sprintf("alert [%v] is %v and can not be %v", a.Id, a.State, action))
+}
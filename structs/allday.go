@@ -0,0 +1,233 @@
+package structs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DateLayout is how the dates of all-day events are sent and received.
+const DateLayout = "2006-01-02"
+
+// All-day events are not tied to a location: their start and end are calendar
+// dates, kept as midnight UTC. The end date is exclusive, so an event on a
+// single day ends on the next one.
+
+// Date drops the time of day from t, keeping the date as it is written in t.
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CoveredDays returns the first and the last day e takes place on. Days of
+// timed events are taken in UTC, like every other time the repositories keep.
+func CoveredDays(e Event) (first time.Time, last time.Time) {
+	if e.AllDay {
+		first, last = Date(e.Start), Date(e.End).AddDate(0, 0, -1)
+	} else {
+		// an event ending at midnight does not take place on the day it ends
+		first, last = Date(e.Start.UTC()), Date(e.End.UTC().Add(-time.Nanosecond))
+	}
+	if last.Before(first) {
+		last = first
+	}
+	return first, last
+}
+
+func createAllDayEvent(loc time.Location, newEvent EventCreation) (Event, error) {
+	e := Event{
+		Name:        newEvent.Name,
+		Start:       Date(newEvent.Start),
+		End:         Date(newEvent.End),
+		Description: newEvent.Description,
+		AllDay:      true,
+		Reminders:   CopyReminders(newEvent.Reminders),
+		Resources:   CopyResources(newEvent.Resources),
+	}
+	if !newEvent.Alert.IsZero() {
+		alert, err := localTime("alert", newEvent.Alert, &loc, newEvent.DSTPolicy)
+		if err != nil {
+			return Event{}, err
+		}
+		e.Alert = alert
+	}
+	return e, nil
+}
+
+// parseDate accepts a date or a full RFC 3339 time, of which only the date is kept.
+func parseDate(field string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.Parse(DateLayout, value); err == nil {
+		return d, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, NewValidationError("error parsing all-day event dates",
+			FieldError{Field: field, Message: "must be a date like " + DateLayout})
+	}
+	return Date(t), nil
+}
+
+// isDate reports whether the raw JSON value is a date without a time.
+func isDate(raw json.RawMessage) bool {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return false
+	}
+	_, err := time.Parse(DateLayout, value)
+	return err == nil
+}
+
+type eventFields Event
+
+type allDayEvent struct {
+	Id          int        `json:"id"`
+	Name        string     `json:"name"`
+	Start       string     `json:"start"`
+	End         string     `json:"end"`
+	Description string     `json:"description"`
+	Alert       time.Time  `json:"alert"`
+	AllDay      bool       `json:"all_day"`
+	Reminders   []Reminder `json:"reminders,omitempty"`
+	Resources   []int      `json:"resources,omitempty"`
+	Holiday     string     `json:"holiday,omitempty"`
+}
+
+// MarshalJSON sends the start and the end of all-day events as dates.
+func (e Event) MarshalJSON() ([]byte, error) {
+	if !e.AllDay {
+		return json.Marshal(eventFields(e))
+	}
+	return json.Marshal(allDayEvent{
+		Id:          e.Id,
+		Name:        e.Name,
+		Start:       e.Start.Format(DateLayout),
+		End:         e.End.Format(DateLayout),
+		Description: e.Description,
+		Alert:       e.Alert,
+		AllDay:      true,
+		Reminders:   e.Reminders,
+		Resources:   e.Resources,
+		Holiday:     e.Holiday,
+	})
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var kind struct {
+		AllDay bool `json:"all_day"`
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return err
+	}
+	if !kind.AllDay {
+		return json.Unmarshal(data, (*eventFields)(e))
+	}
+	var stored allDayEvent
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	start, err := parseDate("start", stored.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseDate("end", stored.End)
+	if err != nil {
+		return err
+	}
+	*e = Event{
+		Id:          stored.Id,
+		Name:        stored.Name,
+		Start:       start,
+		End:         end,
+		Description: stored.Description,
+		Alert:       stored.Alert,
+		AllDay:      true,
+		Reminders:   stored.Reminders,
+		Resources:   stored.Resources,
+	}
+	return nil
+}
+
+type eventCreationFields EventCreation
+
+type allDayEventCreation struct {
+	Name        string     `json:"name"`
+	Start       string     `json:"start"`
+	End         string     `json:"end"`
+	Description string     `json:"description"`
+	Alert       time.Time  `json:"alert"`
+	AllDay      bool       `json:"all_day"`
+	StartTZ     string     `json:"start_tz"`
+	EndTZ       string     `json:"end_tz"`
+	DSTPolicy   DSTPolicy  `json:"dst_policy"`
+	Reminders   []Reminder `json:"reminders"`
+	Resources   []int      `json:"resources"`
+}
+
+func (e EventCreation) MarshalJSON() ([]byte, error) {
+	if !e.AllDay {
+		return json.Marshal(eventCreationFields(e))
+	}
+	created := allDayEventCreation{
+		Name:        e.Name,
+		Start:       e.Start.Format(DateLayout),
+		Description: e.Description,
+		Alert:       e.Alert,
+		AllDay:      true,
+		StartTZ:     e.StartTZ,
+		EndTZ:       e.EndTZ,
+		DSTPolicy:   e.DSTPolicy,
+		Reminders:   e.Reminders,
+		Resources:   e.Resources,
+	}
+	if !e.End.IsZero() {
+		created.End = e.End.Format(DateLayout)
+	}
+	return json.Marshal(created)
+}
+
+// UnmarshalJSON takes dates for the start and the end of all-day events,
+// other events need full RFC 3339 times.
+func (e *EventCreation) UnmarshalJSON(data []byte) error {
+	var kind struct {
+		AllDay bool            `json:"all_day"`
+		Start  json.RawMessage `json:"start"`
+		End    json.RawMessage `json:"end"`
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return err
+	}
+	if !kind.AllDay {
+		if isDate(kind.Start) || isDate(kind.End) {
+			return NewValidationError("dates without a time are only accepted for all-day events",
+				FieldError{Field: "all_day", Message: "has to be true for date-only start and end"})
+		}
+		return json.Unmarshal(data, (*eventCreationFields)(e))
+	}
+	var created allDayEventCreation
+	if err := json.Unmarshal(data, &created); err != nil {
+		return err
+	}
+	start, err := parseDate("start", created.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseDate("end", created.End)
+	if err != nil {
+		return err
+	}
+	*e = EventCreation{
+		Name:        created.Name,
+		Start:       start,
+		End:         end,
+		Description: created.Description,
+		Alert:       created.Alert,
+		AllDay:      true,
+		StartTZ:     created.StartTZ,
+		EndTZ:       created.EndTZ,
+		DSTPolicy:   created.DSTPolicy,
+		Reminders:   created.Reminders,
+		Resources:   created.Resources,
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClockLayout is the layout of the times of day in availability profiles.
+const ClockLayout = "15:04"
+
+const minutesPerDay = 24 * 60
+
+// Weekdays are the keys of Availability.Weekly, in the order of time.Weekday.
+var Weekdays = [...]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// TimeRange is a part of a day in the location of the user, like 09:00 - 17:30.
+// End is exclusive and may be 24:00 for ranges that last until midnight.
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// DateOverride replaces the working hours of the weekday on one date,
+// an override without ranges makes the whole day unavailable.
+type DateOverride struct {
+	Date   string      `json:"date"`
+	Ranges []TimeRange `json:"ranges"`
+}
+
+// Availability is the profile of a user, Weekly holds the working hours of
+// every weekday by its name. Timezone is the location of the user, the
+// profile follows it, so it can not be changed with the profile. Holidays
+// is the country whose public holidays the user has off.
+type Availability struct {
+	Timezone  string                 `json:"timezone"`
+	Weekly    map[string][]TimeRange `json:"weekly"`
+	Overrides []DateOverride         `json:"overrides"`
+	Holidays  string                 `json:"holidays,omitempty"`
+}
+
+// ParseClock returns the minutes since midnight of a time of day, 24:00 included.
+func ParseClock(clock string) (int, bool) {
+	if clock == "24:00" {
+		return minutesPerDay, true
+	}
+	t, err := time.Parse(ClockLayout, clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// FormatClock writes minutes since midnight the way ParseClock reads them.
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Minutes returns the bounds of a valid range in minutes since midnight.
+func (r TimeRange) Minutes() (start int, end int) {
+	start, _ = ParseClock(r.Start)
+	end, _ = ParseClock(r.End)
+	return start, end
+}
+
+// NormalizeAvailability checks a profile sent by a client and returns it with
+// its ranges and overrides in order. Ranges of a day must not overlap.
+func NormalizeAvailability(a Availability) (Availability, error) {
+	normalized := Availability{Weekly: make(map[string][]TimeRange), Overrides: make([]DateOverride, 0, len(a.Overrides))}
+	var fields []FieldError
+	for day, ranges := range a.Weekly {
+		name := strings.ToLower(day)
+		if !isWeekday(name) {
+			fields = append(fields, FieldError{Field: "weekly." + day, Message: "must be a day of the week like monday"})
+			continue
+		}
+		if _, ok := normalized.Weekly[name]; ok {
+			fields = append(fields, FieldError{Field: "weekly." + day, Message: "is given more than once"})
+			continue
+		}
+		sorted, errs := normalizeRanges("weekly."+name, ranges)
+		fields = append(fields, errs...)
+		if len(sorted) > 0 {
+			normalized.Weekly[name] = sorted
+		}
+	}
+
+	seen := make(map[string]bool, len(a.Overrides))
+	for i, o := range a.Overrides {
+		field := fmt.Sprintf("overrides[%d]", i)
+		date, err := time.Parse(DateLayout, o.Date)
+		if err != nil {
+			fields = append(fields, FieldError{Field: field + ".date", Message: "must be a date like 2021-12-24"})
+			continue
+		}
+		if seen[o.Date] {
+			fields = append(fields, FieldError{Field: field + ".date", Message: "is overridden more than once"})
+			continue
+		}
+		seen[o.Date] = true
+		sorted, errs := normalizeRanges(field+".ranges", o.Ranges)
+		fields = append(fields, errs...)
+		normalized.Overrides = append(normalized.Overrides, DateOverride{Date: date.Format(DateLayout), Ranges: sorted})
+	}
+	if a.Holidays != "" {
+		if c, err := HolidayCalendarOf(a.Holidays); err != nil {
+			fields = append(fields, FieldError{Field: "holidays", Message: FieldsOf(err)[0].Message})
+		} else {
+			normalized.Holidays = c.Country
+		}
+	}
+	if len(fields) > 0 {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+		return Availability{}, NewValidationError("invalid availability", fields...)
+	}
+	sort.Slice(normalized.Overrides, func(i, j int) bool { return normalized.Overrides[i].Date < normalized.Overrides[j].Date })
+	return normalized, nil
+}
+
+func normalizeRanges(field string, ranges []TimeRange) ([]TimeRange, []FieldError) {
+	var fields []FieldError
+	sorted := make([]TimeRange, 0, len(ranges))
+	for i, r := range ranges {
+		start, okStart := ParseClock(r.Start)
+		end, okEnd := ParseClock(r.End)
+		if !okStart || !okEnd || start == minutesPerDay {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("%v[%d]", field, i), Message: "start and end must be times of day like 09:00"})
+			continue
+		}
+		if start >= end {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("%v[%d]", field, i), Message: "end must be after start"})
+			continue
+		}
+		sorted = append(sorted, TimeRange{Start: FormatClock(start), End: FormatClock(end)})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Start < sorted[i-1].End {
+			fields = append(fields, FieldError{Field: field, Message: "ranges " + sorted[i-1].Start + "-" + sorted[i-1].End +
+				" and " + sorted[i].Start + "-" + sorted[i].End + " overlap"})
+		}
+	}
+	return sorted, fields
+}
+
+func isWeekday(name string) bool {
+	for _, day := range Weekdays {
+		if day == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RangesOn returns the working hours on the date of day, an override of the date wins
+// over the weekday. Public holidays of the profile are free unless they are overridden.
+func (a Availability) RangesOn(day time.Time) []TimeRange {
+	date := day.Format(DateLayout)
+	for _, o := range a.Overrides {
+		if o.Date == date {
+			return o.Ranges
+		}
+	}
+	if c, ok := holidayCalendars[a.Holidays]; ok && c.IsDayOff(day) {
+		return nil
+	}
+	return a.Weekly[Weekdays[day.Weekday()]]
+}
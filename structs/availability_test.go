@@ -0,0 +1,93 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeAvailability(t *testing.T) {
+	testCases := map[string]struct {
+		profile  Availability
+		expected Availability
+		fields   []string
+	}{
+		"Ranges are sorted": {
+			profile: Availability{
+				Weekly: map[string][]TimeRange{"Monday": {{"13:00", "17:30"}, {"9:00", "12:00"}}, "friday": {{"22:00", "24:00"}}},
+				Overrides: []DateOverride{
+					{Date: "2021-12-31", Ranges: []TimeRange{{"09:00", "12:00"}}},
+					{Date: "2021-12-24"},
+				},
+			},
+			expected: Availability{
+				Weekly: map[string][]TimeRange{"monday": {{"09:00", "12:00"}, {"13:00", "17:30"}}, "friday": {{"22:00", "24:00"}}},
+				Overrides: []DateOverride{
+					{Date: "2021-12-24", Ranges: []TimeRange{}},
+					{Date: "2021-12-31", Ranges: []TimeRange{{"09:00", "12:00"}}},
+				},
+			},
+		},
+		"Empty profile": {
+			profile:  Availability{},
+			expected: Availability{Weekly: map[string][]TimeRange{}, Overrides: []DateOverride{}},
+		},
+		"Unknown day": {
+			profile: Availability{Weekly: map[string][]TimeRange{"someday": {{"09:00", "17:00"}}}},
+			fields:  []string{"weekly.someday"},
+		},
+		"Overlapping ranges": {
+			profile: Availability{Weekly: map[string][]TimeRange{"tuesday": {{"09:00", "13:00"}, {"12:00", "17:00"}}}},
+			fields:  []string{"weekly.tuesday"},
+		},
+		"Range ends before it starts": {
+			profile: Availability{Weekly: map[string][]TimeRange{"tuesday": {{"17:00", "09:00"}}}},
+			fields:  []string{"weekly.tuesday[0]"},
+		},
+		"Bad time of day": {
+			profile: Availability{Weekly: map[string][]TimeRange{"tuesday": {{"24:00", "24:30"}}}},
+			fields:  []string{"weekly.tuesday[0]"},
+		},
+		"Bad and repeated dates": {
+			profile: Availability{Overrides: []DateOverride{{Date: "24.12.2021"}, {Date: "2021-12-31"}, {Date: "2021-12-31"}}},
+			fields:  []string{"overrides[0].date", "overrides[2].date"},
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			normalized, err := NormalizeAvailability(test.profile)
+			if test.fields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if !reflect.DeepEqual(normalized, test.expected) {
+					t.Errorf("expected %+v, got %+v", test.expected, normalized)
+				}
+				return
+			}
+			var fields []string
+			for _, f := range FieldsOf(err) {
+				fields = append(fields, f.Field)
+			}
+			if KindOf(err) != KindValidation || !reflect.DeepEqual(fields, test.fields) {
+				t.Errorf("expected errors of %v, got %v", test.fields, err)
+			}
+		})
+	}
+}
+
+func TestRangesOn(t *testing.T) {
+	a := Availability{
+		Weekly:    map[string][]TimeRange{"friday": {{"09:00", "17:00"}}},
+		Overrides: []DateOverride{{Date: "2021-12-24", Ranges: []TimeRange{}}},
+	}
+	if ranges := a.RangesOn(time.Date(2021, 12, 17, 0, 0, 0, 0, time.UTC)); len(ranges) != 1 {
+		t.Errorf("expected the friday hours, got %v", ranges)
+	}
+	if ranges := a.RangesOn(time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC)); len(ranges) != 0 {
+		t.Errorf("expected the override to free christmas eve, got %v", ranges)
+	}
+	if ranges := a.RangesOn(time.Date(2021, 12, 18, 0, 0, 0, 0, time.UTC)); len(ranges) != 0 {
+		t.Errorf("expected no hours on saturday, got %v", ranges)
+	}
+}
@@ -0,0 +1,218 @@
+package structs
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BookingHorizon is how far ahead booking pages list open slots by default.
+	BookingHorizon = 14 * 24 * time.Hour
+	// MaxBookingWindow bounds the time a single listing of open slots covers.
+	MaxBookingWindow = 62 * 24 * time.Hour
+
+	minGranularity = 5 * time.Minute
+	maxBuffer      = 24 * time.Hour
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{2,63}$`)
+
+// BookingPage lets people without an account book time with its owner. Slots of
+// Duration start every Granularity within the windows of a weekday, Buffer is the
+// free time kept around the events of the owner. Pages without windows offer the
+// working hours of the owner. MaxPerDay limits the bookings of a day, 0 does not.
+type BookingPage struct {
+	Slug        string                 `json:"slug"`
+	Owner       string                 `json:"-"`
+	Title       string                 `json:"title" validate:"required,max=100"`
+	Description string                 `json:"description" validate:"max=1024"`
+	Duration    string                 `json:"duration" validate:"required"`
+	Granularity string                 `json:"granularity"`
+	Buffer      string                 `json:"buffer"`
+	MaxPerDay   int                    `json:"max_per_day" validate:"min=0"`
+	Windows     map[string][]TimeRange `json:"windows"`
+	// Timezone is the location of the owner, the windows follow it
+	Timezone string `json:"timezone"`
+}
+
+// Booking is a slot booked through a page, CancelToken lets the guest cancel it.
+type Booking struct {
+	Slug        string    `json:"slug"`
+	EventId     int       `json:"event_id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	CancelToken string    `json:"cancel_token,omitempty"`
+}
+
+// BookingRequest is sent by a guest, the limits keep the event it creates
+// within the sizes of the columns of events.
+type BookingRequest struct {
+	Start time.Time `json:"start" validate:"required"`
+	Name  string    `json:"name" validate:"required,max=100"`
+	Email string    `json:"email" validate:"required,email,max=100"`
+	Notes string    `json:"notes" validate:"max=150"`
+}
+
+// OpenSlots is what a booking page shows to its visitors.
+type OpenSlots struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Duration    string     `json:"duration"`
+	Timezone    string     `json:"timezone"`
+	Slots       []Interval `json:"slots"`
+}
+
+// NormalizeBookingPage checks a page sent by its owner and writes its durations
+// and windows the same way every time.
+func NormalizeBookingPage(slug string, p BookingPage) (BookingPage, error) {
+	if err := Validate(p, "validator : invalid data format"); err != nil {
+		return BookingPage{}, err
+	}
+	var fields []FieldError
+	if !slugPattern.MatchString(slug) {
+		fields = append(fields, FieldError{Field: "slug", Message: "must be 3 to 64 lowercase letters, digits and dashes"})
+	}
+	duration, ok := parsePositiveDuration(p.Duration)
+	if !ok || duration < minGranularity || duration > 24*time.Hour || duration%time.Minute != 0 {
+		fields = append(fields, FieldError{Field: "duration", Message: "must be whole minutes between 5m and 24h"})
+	}
+	granularity, ok := parsePositiveDuration(p.Granularity)
+	if granularity == 0 && ok {
+		granularity = duration
+	}
+	if !ok || granularity < minGranularity || granularity%time.Minute != 0 {
+		fields = append(fields, FieldError{Field: "granularity", Message: "must be whole minutes of at least 5m"})
+	}
+	buffer, ok := parsePositiveDuration(p.Buffer)
+	if !ok || buffer > maxBuffer || buffer%time.Minute != 0 {
+		fields = append(fields, FieldError{Field: "buffer", Message: "must be whole minutes of at most 24h"})
+	}
+	windows, err := NormalizeAvailability(Availability{Weekly: p.Windows})
+	if err != nil {
+		for _, f := range FieldsOf(err) {
+			fields = append(fields, FieldError{Field: strings.Replace(f.Field, "weekly", "windows", 1), Message: f.Message})
+		}
+	}
+	if len(fields) > 0 {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+		return BookingPage{}, NewValidationError("invalid booking page", fields...)
+	}
+	p.Slug = slug
+	p.Duration = ShortDuration(duration)
+	p.Granularity = ShortDuration(granularity)
+	p.Buffer = ShortDuration(buffer)
+	p.Windows = windows.Weekly
+	return p, nil
+}
+
+// ShortDuration writes d without the zero units time.Duration.String adds, like 1h instead of 1h0m0s.
+func ShortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Durations returns the durations of a normalized page.
+func (p BookingPage) Durations() (duration time.Duration, granularity time.Duration, buffer time.Duration) {
+	duration, _ = parsePositiveDuration(p.Duration)
+	granularity, _ = parsePositiveDuration(p.Granularity)
+	buffer, _ = parsePositiveDuration(p.Buffer)
+	return duration, granularity, buffer
+}
+
+// FindOpenSlots returns the slots of p between from and to that can still be booked at now.
+// hours are the windows of the page or the working hours of the owner, read in loc. events
+// are the events of the owner and bookings the ones already made through the page.
+func FindOpenSlots(p BookingPage, hours Availability, loc *time.Location, events []Event, bookings []Booking,
+	from time.Time, to time.Time, now time.Time) []Interval {
+	duration, granularity, buffer := p.Durations()
+	perDay := make(map[string]int)
+	for _, b := range bookings {
+		perDay[b.Start.In(loc).Format(DateLayout)]++
+	}
+
+	slots := make([]Interval, 0)
+	for _, window := range hours.WorkingIntervals(from, to, loc) {
+		for t := window.Start; !t.Add(duration).After(window.End); t = t.Add(granularity) {
+			slot := Interval{Start: t, End: t.Add(duration)}
+			if t.Before(from) || t.Before(now) || slot.End.After(to) {
+				continue
+			}
+			if p.MaxPerDay > 0 && perDay[t.In(loc).Format(DateLayout)] >= p.MaxPerDay {
+				continue
+			}
+			if !busyAt(events, Interval{Start: slot.Start.Add(-buffer), End: slot.End.Add(buffer)}) {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	return slots
+}
+
+// busyAt reports whether a timed event overlaps slot, all-day events do not make their owner busy.
+func busyAt(events []Event, slot Interval) bool {
+	for _, e := range events {
+		if !e.AllDay && slot.Overlaps(Interval{Start: e.Start, End: e.End}) {
+			return true
+		}
+	}
+	return false
+}
+
+// BookedEvent is the event a booking of p creates in the calendar of its owner.
+func BookedEvent(p BookingPage, r BookingRequest, end time.Time) Event {
+	description := r.Email
+	if r.Notes != "" {
+		description += "\n" + r.Notes
+	}
+	return Event{
+		Name:        p.Title + " with " + r.Name,
+		Start:       r.Start.UTC(),
+		End:         end.UTC(),
+		Description: description,
+		Owner:       p.Owner,
+	}
+}
+
+// SlotTaken reports that a booking asked for a slot that is not open.
+func SlotTaken(start time.Time) error {
+	return NewError(KindConflict, "the slot starting at "+start.UTC().Format(time.RFC3339)+" is not open for booking")
+}
+
+// ParseBookingWindow reads the dates a visitor asks for open slots between. Dates are
+// read in loc and to is included. Without dates slots of the coming BookingHorizon are listed.
+func ParseBookingWindow(from string, to string, loc *time.Location, now time.Time) (Interval, error) {
+	window := Interval{Start: now, End: now.Add(BookingHorizon)}
+	if from != "" {
+		date, err := time.ParseInLocation(DateLayout, from, loc)
+		if err != nil {
+			return Interval{}, NewValidationError("invalid date", FieldError{Field: "from", Message: "must be a date like 2021-12-24"})
+		}
+		window.Start = date
+		window.End = date.Add(BookingHorizon)
+	}
+	if to != "" {
+		date, err := time.ParseInLocation(DateLayout, to, loc)
+		if err != nil {
+			return Interval{}, NewValidationError("invalid date", FieldError{Field: "to", Message: "must be a date like 2021-12-24"})
+		}
+		window.End = date.AddDate(0, 0, 1)
+	}
+	if !window.End.After(window.Start) {
+		return Interval{}, NewValidationError("invalid dates", FieldError{Field: "to", Message: "must not be before from"})
+	}
+	if window.End.Sub(window.Start) > MaxBookingWindow {
+		return Interval{}, NewValidationError("invalid dates", FieldError{Field: "to", Message: "must be at most 62 days after from"})
+	}
+	return window, nil
+}
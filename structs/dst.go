@@ -0,0 +1,112 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DSTPolicy decides what a wall-clock time means when a daylight saving
+// transition makes it skipped (a gap) or repeated (an overlap).
+type DSTPolicy string
+
+const (
+	// DSTReject refuses skipped and repeated times.
+	DSTReject DSTPolicy = "reject"
+	// DSTShiftForward moves skipped times forward by the length of the gap,
+	// repeated times get their first occurrence.
+	DSTShiftForward DSTPolicy = "shift_forward"
+	// DSTEarlier reads the time with the offset in effect before the transition.
+	DSTEarlier DSTPolicy = "earlier"
+	// DSTLater reads the time with the offset in effect after the transition.
+	DSTLater DSTPolicy = "later"
+
+	DefaultDSTPolicy = DSTShiftForward
+)
+
+// transitionWindow bounds the search for a transition around a wall-clock time,
+// no zone changes its offset twice within it.
+const transitionWindow = 48 * time.Hour
+
+// localTime reads the date and the time written in t as a time in loc and returns
+// it in UTC. Times made ambiguous by a transition are resolved by policy, field
+// names the value in errors.
+func localTime(field string, t time.Time, loc *time.Location, policy DSTPolicy) (time.Time, error) {
+	if policy == "" {
+		policy = DefaultDSTPolicy
+	}
+	// the wall clock read as if it was UTC, the real instant is this minus the offset
+	naive := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	before := offsetAt(naive.Add(-transitionWindow), loc)
+	after := offsetAt(naive.Add(transitionWindow), loc)
+
+	var instants []time.Time
+	for _, offset := range []int{before, after} {
+		instant := naive.Add(-time.Duration(offset) * time.Second)
+		if offsetAt(instant, loc) == offset && (len(instants) == 0 || !instants[0].Equal(instant)) {
+			instants = append(instants, instant)
+		}
+	}
+	sort.Slice(instants, func(i, j int) bool { return instants[i].Before(instants[j]) })
+
+	switch len(instants) {
+	case 1:
+		return instants[0], nil
+	case 0:
+		switch policy {
+		case DSTShiftForward, DSTEarlier:
+			return naive.Add(-time.Duration(before) * time.Second), nil
+		case DSTLater:
+			return naive.Add(-time.Duration(after) * time.Second), nil
+		}
+		return time.Time{}, transitionError(field, "does not exist", naive, loc)
+	default:
+		switch policy {
+		case DSTShiftForward, DSTEarlier:
+			return instants[0], nil
+		case DSTLater:
+			return instants[1], nil
+		}
+		return time.Time{}, transitionError(field, "is ambiguous", naive, loc)
+	}
+}
+
+// wallClock is localTime for times that can not be rejected, like the ones
+// computed when events are rebased.
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	resolved, _ := localTime("", t, loc, DefaultDSTPolicy)
+	return resolved
+}
+
+func offsetAt(t time.Time, loc *time.Location) int {
+	_, offset := t.In(loc).Zone()
+	return offset
+}
+
+// transitionError names the transition that skips or repeats the wall-clock time naive.
+func transitionError(field string, problem string, naive time.Time, loc *time.Location) error {
+	from, to := naive.Add(-transitionWindow), naive.Add(transitionWindow)
+	// the offset changes exactly once between from and to
+	for to.Sub(from) > time.Second {
+		middle := from.Add(to.Sub(from) / 2)
+		if offsetAt(middle, loc) == offsetAt(from, loc) {
+			from = middle
+		} else {
+			to = middle
+		}
+	}
+	// transitions happen on whole seconds, to is less than a second after it
+	transition := to.Truncate(time.Second)
+	before, beforeOffset := from.In(loc).Zone()
+	after, afterOffset := transition.In(loc).Zone()
+	direction := "forward"
+	if afterOffset < beforeOffset {
+		direction = "back"
+	}
+	// written the way clocks showed it right before the change, e.g. 02:00 EST
+	message := fmt.Sprintf("local time %v %v in %v, clocks go %v from %v to %v at %v",
+		naive.Format("2006-01-02 15:04"), problem, loc.String(), direction, before, after,
+		transition.In(time.FixedZone(before, beforeOffset)).Format("2006-01-02 15:04 MST"))
+	return NewValidationError(message, FieldError{Field: field, Message: problem + " because of a daylight saving transition, " +
+		"choose a dst_policy of " + string(DSTShiftForward) + ", " + string(DSTEarlier) + " or " + string(DSTLater) + " to accept it"})
+}
@@ -0,0 +1,124 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalTime(t *testing.T) {
+	type resolved map[DSTPolicy]string
+
+	testCases := map[string]struct {
+		zone         string
+		wall         string
+		expected     resolved
+		errorMessage string
+	}{
+		"Regular time": {
+			"Europe/Kiev", "2021-07-15 14:00",
+			resolved{DSTReject: "2021-07-15T11:00:00Z", DSTShiftForward: "2021-07-15T11:00:00Z", DSTLater: "2021-07-15T11:00:00Z"},
+			"",
+		},
+		"New York spring forward": {
+			"America/New_York", "2021-03-14 02:30",
+			resolved{DSTShiftForward: "2021-03-14T07:30:00Z", DSTEarlier: "2021-03-14T07:30:00Z", DSTLater: "2021-03-14T06:30:00Z"},
+			"local time 2021-03-14 02:30 does not exist in America/New_York, clocks go forward from EST to EDT at 2021-03-14 02:00 EST",
+		},
+		"New York fall back": {
+			"America/New_York", "2021-11-07 01:30",
+			resolved{DSTShiftForward: "2021-11-07T05:30:00Z", DSTEarlier: "2021-11-07T05:30:00Z", DSTLater: "2021-11-07T06:30:00Z"},
+			"local time 2021-11-07 01:30 is ambiguous in America/New_York, clocks go back from EDT to EST at 2021-11-07 02:00 EDT",
+		},
+		"London spring forward": {
+			"Europe/London", "2021-03-28 01:30",
+			resolved{DSTShiftForward: "2021-03-28T01:30:00Z", DSTLater: "2021-03-28T00:30:00Z"},
+			"local time 2021-03-28 01:30 does not exist in Europe/London, clocks go forward from GMT to BST at 2021-03-28 01:00 GMT",
+		},
+		"London fall back": {
+			"Europe/London", "2021-10-31 01:30",
+			resolved{DSTEarlier: "2021-10-31T00:30:00Z", DSTLater: "2021-10-31T01:30:00Z"},
+			"local time 2021-10-31 01:30 is ambiguous in Europe/London, clocks go back from BST to GMT at 2021-10-31 02:00 BST",
+		},
+		"Sydney spring forward in October": {
+			"Australia/Sydney", "2021-10-03 02:30",
+			resolved{DSTShiftForward: "2021-10-02T16:30:00Z", DSTLater: "2021-10-02T15:30:00Z"},
+			"local time 2021-10-03 02:30 does not exist in Australia/Sydney, clocks go forward from AEST to AEDT at 2021-10-03 02:00 AEST",
+		},
+		"Sydney fall back in April": {
+			"Australia/Sydney", "2021-04-04 02:30",
+			resolved{DSTEarlier: "2021-04-03T15:30:00Z", DSTLater: "2021-04-03T16:30:00Z"},
+			"local time 2021-04-04 02:30 is ambiguous in Australia/Sydney, clocks go back from AEDT to AEST at 2021-04-04 03:00 AEDT",
+		},
+		"Lord Howe half hour gap": {
+			"Australia/Lord_Howe", "2021-10-03 02:15",
+			resolved{DSTShiftForward: "2021-10-02T15:45:00Z", DSTLater: "2021-10-02T15:15:00Z"},
+			"does not exist in Australia/Lord_Howe",
+		},
+		"Lord Howe half hour overlap": {
+			"Australia/Lord_Howe", "2021-04-04 01:45",
+			resolved{DSTEarlier: "2021-04-03T14:45:00Z", DSTLater: "2021-04-03T15:15:00Z"},
+			"is ambiguous in Australia/Lord_Howe",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			loc, err := time.LoadLocation(test.zone)
+			if err != nil {
+				t.Skip("time zone database is not available")
+			}
+			wall, _ := time.Parse("2006-01-02 15:04", test.wall)
+			for policy, expected := range test.expected {
+				got, err := localTime("start", wall, loc, policy)
+				if err != nil {
+					t.Fatalf("%v : unexpected error %v", policy, err)
+				}
+				if got.Format(time.RFC3339) != expected {
+					t.Errorf("%v : expected %v, got %v", policy, expected, got.Format(time.RFC3339))
+				}
+			}
+
+			_, err = localTime("start", wall, loc, DSTReject)
+			if test.errorMessage == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.errorMessage) {
+				t.Fatalf("expected error %q, got %v", test.errorMessage, err)
+			}
+			if KindOf(err) != KindValidation || len(FieldsOf(err)) != 1 || FieldsOf(err)[0].Field != "start" {
+				t.Errorf("expected a validation error of the start field, got %#v", err)
+			}
+		})
+	}
+}
+
+func TestCreateEventDSTPolicy(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	creation := EventCreation{
+		Name:  "Night shift",
+		Start: time.Date(2021, 3, 14, 1, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 3, 14, 2, 30, 0, 0, time.UTC),
+	}
+
+	event, err := CreateEvent(*loc, creation)
+	if err != nil || event.End.Sub(event.Start) != time.Hour+30*time.Minute {
+		t.Errorf("default policy should shift the end forward, got %v - %v, %v", event.Start, event.End, err)
+	}
+
+	creation.DSTPolicy = DSTReject
+	if _, err = CreateEvent(*loc, creation); err == nil || FieldsOf(err)[0].Field != "end" {
+		t.Errorf("expected the end to be rejected, got %v", err)
+	}
+
+	creation.DSTPolicy = "sometimes"
+	if _, err = CreateEvent(*loc, creation); KindOf(err) != KindValidation {
+		t.Errorf("expected an unknown policy to be rejected, got %v", err)
+	}
+}
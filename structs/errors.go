@@ -1,11 +1,113 @@
 package structs
 
-import "fmt"
+import "errors"
 
-var ErrNoMatch = fmt.Errorf("no matching record")
+// ErrorKind classifies an error, the api package maps every kind to a single HTTP status.
+type ErrorKind string
 
-var ErrPostgres = fmt.Errorf("postgres error")
+const (
+	KindValidation    ErrorKind = "validation"
+	KindNotFound      ErrorKind = "not-found"
+	KindConflict      ErrorKind = "conflict"
+	KindTestFailed    ErrorKind = "patch-test-failed"
+	KindUnauthorized  ErrorKind = "unauthorized"
+	KindForbidden     ErrorKind = "forbidden"
+	KindUnsupported   ErrorKind = "unsupported-media-type"
+	KindUnprocessable ErrorKind = "unprocessable"
+	KindInternal      ErrorKind = "internal"
+	KindCanceled      ErrorKind = "canceled"
+	KindTimeout       ErrorKind = "timeout"
+)
 
-var ErrSql = fmt.Errorf("databse/sql error")
+// FieldError describes what is wrong with a single field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
 
-var ErrDublicate = fmt.Errorf("record dublicate")
+// Error is an error of a known kind. Sentinel errors below are *Error values,
+// so errors.Is keeps working for them when they are wrapped with fmt.Errorf.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	if e.Err != nil {
+		return e.Message + " : " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewError(kind ErrorKind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func NewValidationError(message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Message: message, Fields: fields}
+}
+
+// WrapError gives err a kind without changing its message.
+func WrapError(kind ErrorKind, err error) *Error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of the outermost *Error in the chain of err.
+// Errors that were never classified are internal.
+func KindOf(err error) ErrorKind {
+	var mandatory *MandatoryFieldError
+	if errors.As(err, &mandatory) {
+		return KindValidation
+	}
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed.Kind
+	}
+	return KindInternal
+}
+
+// FieldsOf returns the first field level details found in the chain of err.
+func FieldsOf(err error) []FieldError {
+	var mandatory *MandatoryFieldError
+	if errors.As(err, &mandatory) {
+		return []FieldError{{Field: mandatory.FieldName, Message: "is required"}}
+	}
+	for err != nil {
+		var typed *Error
+		if !errors.As(err, &typed) {
+			return nil
+		}
+		if len(typed.Fields) > 0 {
+			return typed.Fields
+		}
+		err = typed.Err
+	}
+	return nil
+}
+
+var ErrNoMatch = NewError(KindNotFound, "no matching record")
+
+var ErrPostgres = NewError(KindInternal, "postgres error")
+
+var ErrSql = NewError(KindInternal, "databse/sql error")
+
+var ErrDublicate = NewError(KindConflict, "record dublicate")
+
+var ErrCanceled = NewError(KindCanceled, "request canceled")
+
+var ErrTimeout = NewError(KindTimeout, "request timed out")
+
+var ErrUnauthorized = NewError(KindUnauthorized, "unauthorized")
+
+var ErrForbidden = NewError(KindForbidden, "forbidden")
+
+var ErrBooked = NewError(KindConflict, "resource already booked")
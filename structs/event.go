@@ -1,11 +1,8 @@
 package structs
 
 import (
-	"errors"
 	"fmt"
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type Login struct {
@@ -26,12 +23,26 @@ type HashedInfo struct {
 }
 
 type Event struct {
-	Id          int       `json:"id"`
-	Name        string    `json:"name" validate:"required"`
-	Start       time.Time `json:"start" validate:"required"`
-	End         time.Time `json:"end" validate:"required"`
-	Description string    `json:"description"`
-	Alert       time.Time `json:"alert"`
+	Id          int        `json:"id"`
+	Name        string     `json:"name" validate:"required"`
+	Start       time.Time  `json:"start" validate:"required"`
+	End         time.Time  `json:"end" validate:"required"`
+	Description string     `json:"description"`
+	Alert       time.Time  `json:"alert"`
+	AllDay      bool       `json:"all_day,omitempty"`
+	StartTZ     string     `json:"start_tz,omitempty"`
+	EndTZ       string     `json:"end_tz,omitempty"`
+	Owner       string     `json:"-"`
+	Reminders   []Reminder `json:"reminders,omitempty"`
+	Resources   []int      `json:"resources,omitempty"`
+	// Holiday is the country of a public holiday shown among the events, those are not stored
+	Holiday string `json:"holiday,omitempty"`
+}
+
+// BelongsTo reports whether e is an event of user. Events stored before owners were
+// recorded have none, they belong to every user as all events used to.
+func (e Event) BelongsTo(user string) bool {
+	return e.Owner == user || e.Owner == ""
 }
 
 func CompareTwoEvents(f Event, s Event) bool {
@@ -53,6 +64,33 @@ func CompareTwoEvents(f Event, s Event) bool {
 	if f.Alert.Unix() != s.Alert.Unix() {
 		return false
 	}
+	if f.AllDay != s.AllDay {
+		return false
+	}
+	if f.StartTZ != s.StartTZ || f.EndTZ != s.EndTZ {
+		return false
+	}
+	if f.Owner != s.Owner {
+		return false
+	}
+	if len(f.Reminders) != len(s.Reminders) {
+		return false
+	}
+	for i := range f.Reminders {
+		a, b := f.Reminders[i], s.Reminders[i]
+		if a.RelativeTo != b.RelativeTo || a.Offset != b.Offset || a.At != b.At || a.Channel != b.Channel ||
+			!a.RemindAt.Equal(b.RemindAt) {
+			return false
+		}
+	}
+	if len(f.Resources) != len(s.Resources) {
+		return false
+	}
+	for i := range f.Resources {
+		if f.Resources[i] != s.Resources[i] {
+			return false
+		}
+	}
 	return true
 }
 
@@ -62,32 +100,38 @@ type EventCreation struct {
 	End         time.Time `json:"end" validate:"required"`
 	Description string    `json:"description"`
 	Alert       time.Time `json:"alert"`
+	AllDay      bool      `json:"all_day"`
+	StartTZ     string    `json:"start_tz"`
+	EndTZ       string    `json:"end_tz"`
+	// DSTPolicy applies to times skipped or repeated by a daylight saving transition
+	DSTPolicy DSTPolicy  `json:"dst_policy" validate:"omitempty,oneof=reject shift_forward earlier later"`
+	Reminders []Reminder `json:"reminders" validate:"dive"`
+	Resources []int      `json:"resources"`
 }
 
+// SuitsParams reports whether e matches every parameter set in p. Date parts
+// match when any single day covered by the event has all of them.
 func SuitsParams(p EventParams, e Event) bool {
-	if p.Day != 0 {
-		if e.Start.Day() != p.Day {
-			return false
+	if p.Day != 0 || p.Week != 0 || p.Month != 0 || p.Year != 0 {
+		first, last := CoveredDays(e)
+		matched := false
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			if dateSuitsParams(p, d) {
+				matched = true
+				break
+			}
 		}
-	}
-	if p.Week != 0 {
-		_, week := e.Start.ISOWeek()
-		if week != p.Week {
+		if !matched {
 			return false
 		}
 	}
-	if p.Month != 0 {
-		if int(e.Start.Month()) != p.Month {
+	if p.Name != "" {
+		if e.Name != p.Name {
 			return false
 		}
 	}
-	if p.Year != 0 {
-		if e.Start.Year() != p.Year {
-			return false
-		}
-	}
-	if p.Name != "" {
-		if e.Name != p.Name {
+	if p.Owner != "" {
+		if !e.BelongsTo(p.Owner) {
 			return false
 		}
 	}
@@ -101,30 +145,71 @@ func SuitsParams(p EventParams, e Event) bool {
 			return false
 		}
 	}
+	if !p.From.IsZero() && !e.End.After(p.From) {
+		return false
+	}
+	if !p.To.IsZero() && !e.Start.Before(p.To) {
+		return false
+	}
+	return true
+}
+
+func dateSuitsParams(p EventParams, d time.Time) bool {
+	if p.Day != 0 && d.Day() != p.Day {
+		return false
+	}
+	if p.Week != 0 {
+		if _, week := d.ISOWeek(); week != p.Week {
+			return false
+		}
+	}
+	if p.Month != 0 && int(d.Month()) != p.Month {
+		return false
+	}
+	if p.Year != 0 && d.Year() != p.Year {
+		return false
+	}
 	return true
 }
 
 func CreateEvent(loc time.Location, newEvent EventCreation) (Event, error) {
-	validate := validator.New()
-	err := validate.Struct(newEvent)
+	if newEvent.AllDay && newEvent.End.IsZero() && !newEvent.Start.IsZero() {
+		newEvent.End = newEvent.Start.AddDate(0, 0, 1)
+	}
+	if err := Validate(newEvent, "validator : invalid data format"); err != nil {
+		return Event{}, err
+	}
+	if newEvent.AllDay {
+		if newEvent.StartTZ != "" || newEvent.EndTZ != "" {
+			return Event{}, NewValidationError("all-day events are not tied to a time zone",
+				FieldError{Field: "start_tz", Message: "has to be empty for all-day events"})
+		}
+		return createAllDayEvent(loc, newEvent)
+	}
+
+	// the end is in the zone of the start, unless it has a zone of its own
+	if newEvent.EndTZ == "" {
+		newEvent.EndTZ = newEvent.StartTZ
+	}
+	startLoc, err := eventZone("start_tz", newEvent.StartTZ, &loc)
 	if err != nil {
-		return Event{}, errors.New("validator : invalid data format")
+		return Event{}, err
 	}
+	endLoc, err := eventZone("end_tz", newEvent.EndTZ, &loc)
 	if err != nil {
 		return Event{}, err
 	}
 
-	st := newEvent.Start
-	end := newEvent.End
-	a := newEvent.Alert
-
-	t := time.Date(st.Year(), st.Month(), st.Day(), st.Hour(), st.Minute(), st.Second(), st.Nanosecond(), &loc)
-	newEvent.Start = t.In(time.UTC)
-	t = time.Date(end.Year(), end.Month(), end.Day(), end.Hour(), end.Minute(), end.Second(), end.Nanosecond(), &loc)
-	newEvent.End = t.In(time.UTC)
+	if newEvent.Start, err = localTime("start", newEvent.Start, startLoc, newEvent.DSTPolicy); err != nil {
+		return Event{}, err
+	}
+	if newEvent.End, err = localTime("end", newEvent.End, endLoc, newEvent.DSTPolicy); err != nil {
+		return Event{}, err
+	}
 	if newEvent.Alert != (time.Time{}) {
-		t = time.Date(a.Year(), a.Month(), a.Day(), a.Hour(), a.Minute(), a.Second(), a.Nanosecond(), &loc)
-		newEvent.Alert = t.In(time.UTC)
+		if newEvent.Alert, err = localTime("alert", newEvent.Alert, startLoc, newEvent.DSTPolicy); err != nil {
+			return Event{}, err
+		}
 	}
 
 	return Event{
@@ -133,6 +218,10 @@ func CreateEvent(loc time.Location, newEvent EventCreation) (Event, error) {
 		End:         newEvent.End,
 		Alert:       newEvent.Alert,
 		Description: newEvent.Description,
+		StartTZ:     newEvent.StartTZ,
+		EndTZ:       newEvent.EndTZ,
+		Reminders:   CopyReminders(newEvent.Reminders),
+		Resources:   CopyResources(newEvent.Resources),
 	}, nil
 }
 
@@ -144,7 +233,13 @@ type EventParams struct {
 	Name    string
 	Start   time.Time
 	End     time.Time
+	Owner   string
 	Sorting bool
+	// Holidays is the country whose public holidays are listed with the events
+	Holidays string
+	// From and To select the events overlapping the time between them, a zero time leaves its side open
+	From time.Time
+	To   time.Time
 }
 
 type URLParams struct {
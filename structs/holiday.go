@@ -0,0 +1,324 @@
+package structs
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Holiday rules of every country are bundled in holidays/, one file per
+// calendar named after its country code. Rules are one of:
+//   - a fixed date, "date": "12-25"
+//   - the nth weekday of a month, "month": 11, "weekday": "thursday", "nth": 4,
+//     a negative nth counts from the end of the month
+//   - a day relative to Easter, "easter": -2, of the Orthodox one with "orthodox": true
+//
+// Observed moves a holiday falling on a weekend: nearest_weekday moves it to the
+// friday before or the monday after, next_weekday to the first weekday that is
+// not a holiday already. Since and Until bound the years a rule applies in.
+//
+//go:embed holidays/*.json
+var holidayFiles embed.FS
+
+const (
+	ObservedNearestWeekday = "nearest_weekday"
+	ObservedNextWeekday    = "next_weekday"
+
+	// the years the Gregorian Easter can be computed for
+	minHolidayYear = 1583
+	maxHolidayYear = 4099
+)
+
+// HolidayCalendar holds the public holidays of a country.
+type HolidayCalendar struct {
+	Country string        `json:"country"`
+	Name    string        `json:"name"`
+	Rules   []HolidayRule `json:"rules"`
+}
+
+type HolidayRule struct {
+	Name     string `json:"name"`
+	Date     string `json:"date,omitempty"`
+	Month    int    `json:"month,omitempty"`
+	Weekday  string `json:"weekday,omitempty"`
+	Nth      int    `json:"nth,omitempty"`
+	Easter   *int   `json:"easter,omitempty"`
+	Orthodox bool   `json:"orthodox,omitempty"`
+	Observed string `json:"observed,omitempty"`
+	Since    int    `json:"since,omitempty"`
+	Until    int    `json:"until,omitempty"`
+}
+
+// Holiday is a public holiday of a year. Observed is the day off in its place,
+// only set when the holiday falls on a weekend and is moved.
+type Holiday struct {
+	Country  string `json:"country"`
+	Name     string `json:"name"`
+	Date     string `json:"date"`
+	Observed string `json:"observed,omitempty"`
+}
+
+var holidayCalendars = mustLoadHolidayCalendars()
+
+func mustLoadHolidayCalendars() map[string]HolidayCalendar {
+	calendars, err := loadHolidayCalendars()
+	if err != nil {
+		panic("holiday rules : " + err.Error())
+	}
+	return calendars
+}
+
+func loadHolidayCalendars() (map[string]HolidayCalendar, error) {
+	files, err := holidayFiles.ReadDir("holidays")
+	if err != nil {
+		return nil, err
+	}
+	calendars := make(map[string]HolidayCalendar, len(files))
+	for _, file := range files {
+		data, err := holidayFiles.ReadFile(path.Join("holidays", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var c HolidayCalendar
+		if err = json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("%v : %w", file.Name(), err)
+		}
+		if c.Country != strings.ToUpper(strings.TrimSuffix(file.Name(), ".json")) {
+			return nil, fmt.Errorf("%v : holds the calendar of [%v]", file.Name(), c.Country)
+		}
+		for _, r := range c.Rules {
+			if err = r.check(); err != nil {
+				return nil, fmt.Errorf("%v : rule [%v] : %w", file.Name(), r.Name, err)
+			}
+		}
+		calendars[c.Country] = c
+	}
+	return calendars, nil
+}
+
+func (r HolidayRule) check() error {
+	kinds := 0
+	if r.Date != "" {
+		kinds++
+		if _, err := time.Parse("01-02", r.Date); err != nil {
+			return fmt.Errorf("date must be like 12-25")
+		}
+	}
+	if r.Nth != 0 {
+		kinds++
+		if r.Month < 1 || r.Month > 12 || !isWeekday(r.Weekday) || r.Nth < -5 || r.Nth > 5 {
+			return fmt.Errorf("needs a month, a weekday and an nth between -5 and 5")
+		}
+	}
+	if r.Easter != nil {
+		kinds++
+	}
+	if kinds != 1 || r.Name == "" {
+		return fmt.Errorf("needs a name and exactly one of date, nth and easter")
+	}
+	if r.Observed != "" && r.Observed != ObservedNearestWeekday && r.Observed != ObservedNextWeekday {
+		return fmt.Errorf("unknown observed rule [%v]", r.Observed)
+	}
+	return nil
+}
+
+// HolidayCountries returns the codes of the bundled calendars in order.
+func HolidayCountries() []string {
+	countries := make([]string, 0, len(holidayCalendars))
+	for country := range holidayCalendars {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	return countries
+}
+
+// HolidayCalendarOf returns the calendar of country, codes are case insensitive.
+func HolidayCalendarOf(country string) (HolidayCalendar, error) {
+	c, ok := holidayCalendars[strings.ToUpper(country)]
+	if !ok {
+		return HolidayCalendar{}, NewValidationError("unknown holiday calendar ["+country+"]",
+			FieldError{Field: "country", Message: "must be one of " + strings.Join(HolidayCountries(), ", ")})
+	}
+	return c, nil
+}
+
+// Holidays returns the holidays of c in year by their dates.
+func (c HolidayCalendar) Holidays(year int) ([]Holiday, error) {
+	if year < minHolidayYear || year > maxHolidayYear {
+		return nil, NewValidationError("unsupported year",
+			FieldError{Field: "year", Message: fmt.Sprintf("must be between %d and %d", minHolidayYear, maxHolidayYear)})
+	}
+	type dated struct {
+		rule HolidayRule
+		date time.Time
+	}
+	var days []dated
+	for _, r := range c.Rules {
+		if (r.Since != 0 && year < r.Since) || (r.Until != 0 && year > r.Until) {
+			continue
+		}
+		days = append(days, dated{rule: r, date: r.dateIn(year)})
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].date.Before(days[j].date) })
+
+	// holidays on weekdays are days off already, moved ones can not take their place
+	taken := make(map[time.Time]bool, len(days))
+	for _, d := range days {
+		if !isWeekend(d.date) {
+			taken[d.date] = true
+		}
+	}
+	holidays := make([]Holiday, 0, len(days))
+	for _, d := range days {
+		h := Holiday{Country: c.Country, Name: d.rule.Name, Date: d.date.Format(DateLayout)}
+		if observed := observedDate(d.rule.Observed, d.date, taken); !observed.Equal(d.date) {
+			taken[observed] = true
+			h.Observed = observed.Format(DateLayout)
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, nil
+}
+
+// IsDayOff reports whether day is a holiday of c, or the day off in place of one.
+func (c HolidayCalendar) IsDayOff(day time.Time) bool {
+	date := Date(day).Format(DateLayout)
+	// holidays early in a year can be observed at the end of the one before
+	for _, year := range []int{day.Year(), day.Year() + 1} {
+		holidays, err := c.Holidays(year)
+		if err != nil {
+			continue
+		}
+		for _, h := range holidays {
+			if h.Date == date || h.Observed == date {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r HolidayRule) dateIn(year int) time.Time {
+	switch {
+	case r.Date != "":
+		month, _ := strconv.Atoi(r.Date[:2])
+		day, _ := strconv.Atoi(r.Date[3:])
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	case r.Easter != nil:
+		easter := WesternEaster(year)
+		if r.Orthodox {
+			easter = OrthodoxEaster(year)
+		}
+		return easter.AddDate(0, 0, *r.Easter)
+	}
+	weekday := weekdayOf(r.Weekday)
+	if r.Nth > 0 {
+		first := time.Date(year, time.Month(r.Month), 1, 0, 0, 0, 0, time.UTC)
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+7*(r.Nth-1))
+	}
+	last := time.Date(year, time.Month(r.Month)+1, 0, 0, 0, 0, 0, time.UTC)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset+7*(r.Nth+1))
+}
+
+func observedDate(rule string, date time.Time, taken map[time.Time]bool) time.Time {
+	if !isWeekend(date) {
+		return date
+	}
+	switch rule {
+	case ObservedNearestWeekday:
+		if date.Weekday() == time.Saturday {
+			return date.AddDate(0, 0, -1)
+		}
+		return date.AddDate(0, 0, 1)
+	case ObservedNextWeekday:
+		next := date.AddDate(0, 0, 1)
+		for isWeekend(next) || taken[next] {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	}
+	return date
+}
+
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+func weekdayOf(name string) time.Weekday {
+	for i, day := range Weekdays {
+		if day == name {
+			return time.Weekday(i)
+		}
+	}
+	return time.Sunday
+}
+
+// WesternEaster returns the date of Easter Sunday in the Gregorian calendar.
+func WesternEaster(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// OrthodoxEaster returns the date of the Orthodox Easter Sunday, which is computed
+// in the Julian calendar, as a Gregorian date.
+func OrthodoxEaster(year int) time.Time {
+	a, b, c := year%4, year%7, year%19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := (d+e+114)%31 + 1
+	julianOffset := year/100 - year/400 - 2
+	return time.Date(year, time.Month(month), day+julianOffset, 0, 0, 0, 0, time.UTC)
+}
+
+// HolidayEvents returns the holidays of calendar in years as all-day events matching p.
+// A holiday moved off a weekend shows on its observed date as well.
+func HolidayEvents(c HolidayCalendar, years []int, p EventParams) ([]Event, error) {
+	events := make([]Event, 0)
+	for _, year := range years {
+		holidays, err := c.Holidays(year)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range holidays {
+			dates := map[string]string{h.Date: h.Name}
+			if h.Observed != "" {
+				dates[h.Observed] = h.Name + " (observed)"
+			}
+			for date, name := range dates {
+				start, _ := time.Parse(DateLayout, date)
+				e := Event{
+					Name:        name,
+					Start:       start,
+					End:         start.AddDate(0, 0, 1),
+					Description: "Public holiday in " + c.Name,
+					AllDay:      true,
+					Holiday:     c.Country,
+					Owner:       p.Owner,
+				}
+				if SuitsParams(p, e) {
+					events = append(events, e)
+				}
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
@@ -0,0 +1,63 @@
+package structs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHolidays(t *testing.T) {
+	testCases := map[string]struct {
+		country  string
+		year     int
+		name     string
+		date     string
+		observed string
+	}{
+		"Fixed date":                         {country: "DE", year: 2026, name: "German Unity Day", date: "2026-10-03"},
+		"Nth weekday":                        {country: "US", year: 2026, name: "Thanksgiving Day", date: "2026-11-26"},
+		"Last weekday":                       {country: "US", year: 2026, name: "Memorial Day", date: "2026-05-25"},
+		"Western Easter":                     {country: "GB", year: 2024, name: "Good Friday", date: "2024-03-29"},
+		"Orthodox Easter":                    {country: "UA", year: 2024, name: "Easter", date: "2024-05-05", observed: "2024-05-06"},
+		"Observed on the friday before":      {country: "US", year: 2026, name: "Independence Day", date: "2026-07-04", observed: "2026-07-03"},
+		"Observed in the year before":        {country: "US", year: 2022, name: "New Year's Day", date: "2022-01-01", observed: "2021-12-31"},
+		"Observed after the holiday after":   {country: "GB", year: 2021, name: "Boxing Day", date: "2021-12-26", observed: "2021-12-28"},
+		"Observed after a weekday holiday":   {country: "GB", year: 2022, name: "Christmas Day", date: "2022-12-25", observed: "2022-12-27"},
+		"Rule not in force yet":              {country: "PL", year: 2024, name: "Christmas Eve"},
+		"Rule in force since the given year": {country: "PL", year: 2025, name: "Christmas Eve", date: "2025-12-24"},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c, err := HolidayCalendarOf(test.country)
+			if err != nil {
+				t.Fatal(err)
+			}
+			holidays, err := c.Holidays(test.year)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var found *Holiday
+			for i := range holidays {
+				if holidays[i].Name == test.name {
+					found = &holidays[i]
+				}
+			}
+			if test.date == "" {
+				if found != nil {
+					t.Errorf("expected no %v in %d, got %+v", test.name, test.year, *found)
+				}
+				return
+			}
+			if found == nil || found.Date != test.date || found.Observed != test.observed {
+				t.Errorf("expected %v on %v observed on [%v], got %+v", test.name, test.date, test.observed, found)
+			}
+		})
+	}
+
+	if _, err := HolidayCalendarOf("XX"); KindOf(err) != KindValidation {
+		t.Errorf("expected an unknown country to be rejected, got %v", err)
+	}
+	gb, _ := HolidayCalendarOf("gb")
+	if !gb.IsDayOff(time.Date(2021, time.December, 28, 0, 0, 0, 0, time.UTC)) || gb.IsDayOff(time.Date(2021, time.December, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected only the observed Boxing Day to be off")
+	}
+}
@@ -0,0 +1,111 @@
+package structs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The iCalendar (RFC 5545) form of tasks, so they can be read by calendar
+// clients. Lines end with CRLF and are folded after 75 octets.
+
+const (
+	ICalContentType = "text/calendar; charset=utf-8"
+
+	icalProductId  = "-//dkucheru//Calendar//EN"
+	icalTimeLayout = "20060102T150405Z"
+	icalLineLength = 75
+)
+
+// TodoCalendar writes tasks as the VTODO components of a calendar stamped at now.
+func TodoCalendar(tasks []Task, now time.Time) string {
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:"+icalProductId)
+	for _, t := range tasks {
+		writeTodo(&b, t, now)
+	}
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+func writeTodo(b *strings.Builder, t Task, now time.Time) {
+	writeICalLine(b, "BEGIN:VTODO")
+	writeICalLine(b, fmt.Sprintf("UID:task-%d@calendar", t.Id))
+	writeICalLine(b, "DTSTAMP:"+icalTime(now))
+	writeICalLine(b, "SUMMARY:"+icalText(t.Title))
+	if t.Notes != "" {
+		writeICalLine(b, "DESCRIPTION:"+icalText(t.Notes))
+	}
+	writeICalLine(b, "DUE:"+icalTime(t.Due))
+	writeICalLine(b, fmt.Sprintf("PRIORITY:%d", icalPriority(t.Priority)))
+	writeICalLine(b, "STATUS:"+icalStatus(t.Status))
+	if t.Status == TaskDone && !t.CompletedAt.IsZero() {
+		writeICalLine(b, "COMPLETED:"+icalTime(t.CompletedAt))
+	}
+	if before, ok := parsePositiveDuration(t.RemindBefore); ok && before > 0 {
+		writeICalLine(b, "BEGIN:VALARM")
+		writeICalLine(b, "ACTION:DISPLAY")
+		writeICalLine(b, "DESCRIPTION:"+icalText(t.Title))
+		writeICalLine(b, fmt.Sprintf("TRIGGER;RELATED=END:-PT%dM", int(before/time.Minute)))
+		writeICalLine(b, "END:VALARM")
+	}
+	writeICalLine(b, "END:VTODO")
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format(icalTimeLayout)
+}
+
+// icalPriority maps priorities to the 1 (highest) to 9 (lowest) scale of iCalendar.
+func icalPriority(p TaskPriority) int {
+	switch p {
+	case TaskHigh:
+		return 1
+	case TaskLow:
+		return 9
+	}
+	return 5
+}
+
+func icalStatus(s TaskStatus) string {
+	switch s {
+	case TaskInProgress:
+		return "IN-PROCESS"
+	case TaskDone:
+		return "COMPLETED"
+	case TaskCancelled:
+		return "CANCELLED"
+	}
+	return "NEEDS-ACTION"
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icalText(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+// writeICalLine folds line into lines of at most 75 octets, continuation lines
+// start with a space. Lines are only folded between UTF-8 characters.
+func writeICalLine(b *strings.Builder, line string) {
+	limit := icalLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// the space starting a continuation line counts against its length
+		limit = icalLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
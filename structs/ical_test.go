@@ -0,0 +1,47 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTodoCalendar(t *testing.T) {
+	kyiv, _ := time.LoadLocation("Europe/Kiev")
+	now := time.Date(2026, time.March, 2, 8, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{
+			Id: 7, Title: "Pay rent; water, power", Notes: "line one\nline two", Due: time.Date(2026, time.March, 5, 18, 30, 0, 0, kyiv),
+			Priority: TaskHigh, Status: TaskOpen, RemindBefore: "1h30m",
+		},
+		{
+			Id: 8, Title: strings.Repeat("долгая задача ", 10), Due: now, Priority: TaskLow, Status: TaskDone, CompletedAt: now,
+		},
+	}
+	calendar := TodoCalendar(tasks, now)
+
+	expected := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VTODO\r\nUID:task-7@calendar\r\nDTSTAMP:20260302T080000Z\r\n",
+		"SUMMARY:Pay rent\\; water\\, power\r\n",
+		"DESCRIPTION:line one\\nline two\r\n",
+		"DUE:20260305T163000Z\r\nPRIORITY:1\r\nSTATUS:NEEDS-ACTION\r\n",
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\n",
+		"TRIGGER;RELATED=END:-PT90M\r\nEND:VALARM\r\nEND:VTODO\r\n",
+		"PRIORITY:9\r\nSTATUS:COMPLETED\r\nCOMPLETED:20260302T080000Z\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(calendar, part) {
+			t.Errorf("expected %q in\n%v", part, calendar)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(calendar, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("expected lines to be folded at 75 octets, got %d in %q", len(line), line)
+		}
+	}
+	if strings.Count(calendar, "BEGIN:VALARM") != 1 {
+		t.Errorf("expected only tasks with reminders to have alarms")
+	}
+}
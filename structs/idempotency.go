@@ -0,0 +1,21 @@
+package structs
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a request sent with an Idempotency-Key header.
+// Status stays zero while the first request with the key is being handled, such a
+// reservation expires after a short lease and is told apart from later ones by CreatedAt.
+// Header holds the response headers set by the handler, like Content-Type and Location.
+type IdempotencyRecord struct {
+	Key         string              `json:"key"`
+	RequestHash string              `json:"request_hash"`
+	Status      int                 `json:"status"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body"`
+	CreatedAt   time.Time           `json:"created_at"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.Status != 0
+}
@@ -0,0 +1,427 @@
+package structs
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quickDefaultLength is the length of quickly added events that name neither an end nor a duration.
+const quickDefaultLength = time.Hour
+
+// QuickAdd is an event described in plain english, like "Lunch with Ana tomorrow 13:00-14:00".
+// A dry run only returns the interpretation of the text.
+type QuickAdd struct {
+	Text   string `json:"text" validate:"required,max=255"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// QuickInterpretation is the event read from a quick add text. Confidence goes from 1 for
+// texts read without guessing down to 0, Notes name the guesses that were made. RepeatsOn
+// holds the weekdays of a repeating event, of which Event is the first occurrence.
+type QuickInterpretation struct {
+	Text       string        `json:"text"`
+	Event      EventCreation `json:"interpretation"`
+	RepeatsOn  []string      `json:"repeats_on,omitempty"`
+	Confidence float64       `json:"confidence"`
+	Notes      []string      `json:"notes,omitempty"`
+}
+
+// QuickAddResult is the answer to a quick add, Event is the added event unless it was a dry run.
+type QuickAddResult struct {
+	QuickInterpretation
+	DryRun bool   `json:"dry_run"`
+	Event  *Event `json:"event,omitempty"`
+}
+
+const (
+	quickWeekday = `(sunday|monday|tuesday|wednesday|thursday|friday|saturday)`
+	quickMonth   = `(january|february|march|april|may|june|july|august|september|october|november|december|` +
+		`jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec)\.?`
+	quickClock = `(\d{1,2}(?::\d{2})?\s*(?:am|pm)?)`
+)
+
+var (
+	quickISODate   = regexp.MustCompile(`(?i)\b(?:on\s+)?(\d{4})-(\d{2})-(\d{2})\b`)
+	quickMonthDay  = regexp.MustCompile(`(?i)\b(?:on\s+)?` + quickMonth + `\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?\b`)
+	quickDayMonth  = regexp.MustCompile(`(?i)\b(?:on\s+)?(?:the\s+)?(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?` + quickMonth + `(?:\s+(\d{4}))?\b`)
+	quickRepeat    = regexp.MustCompile(`(?i)\b(?:every\s+(day|weekday|weekend\s+day|` + quickWeekday + `((?:\s*(?:,|and)\s*` + quickWeekday + `)*))|(daily)|on\s+(weekdays))\b`)
+	quickRange     = regexp.MustCompile(`(?i)\b(from\s+)?` + quickClock + `\s*(?:-|–|to|until|till)\s*` + quickClock + `(?:\b|$)`)
+	quickAt        = regexp.MustCompile(`(?i)\b(at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?(?:\b|$)`)
+	quickNamedTime = regexp.MustCompile(`(?i)\b(?:at\s+)?(noon|midnight)\b`)
+	quickDuration  = regexp.MustCompile(`(?i)\bfor\s+(?:(\d+(?:\.\d+)?)\s*(minutes?|mins?|m|hours?|hrs?|h)|(an?\s+hour)|(half\s+an\s+hour))\b`)
+	quickRelative  = regexp.MustCompile(`(?i)\b(?:(?:the\s+)?(day\s+after\s+tomorrow)|(tomorrow)|(today|tonight)|in\s+(\d+)\s+(days?|weeks?))\b`)
+	quickOnWeekday = regexp.MustCompile(`(?i)\b(?:on\s+)?(?:(next|this)\s+)?` + quickWeekday + `\b`)
+	quickWeekdays  = regexp.MustCompile(`(?i)` + quickWeekday)
+	quickConnector = regexp.MustCompile(`(?i)(^|\s)(at|on|from|to|for|in|every|the)\s*$`)
+	quickSpaces    = regexp.MustCompile(`\s+`)
+)
+
+// quickParser takes the parts it understood out of a quick add text, the rest is the name of the event.
+type quickParser struct {
+	text       string
+	loc        *time.Location
+	now        time.Time
+	confidence float64
+	notes      []string
+}
+
+// take finds re in the text left, cuts the match out and returns its groups.
+func (q *quickParser) take(re *regexp.Regexp) []string {
+	match := re.FindStringSubmatchIndex(q.text)
+	if match == nil {
+		return nil
+	}
+	return q.cut(match)
+}
+
+// cut takes the match of a regexp with the submatch indexes match out of the text and returns its groups.
+func (q *quickParser) cut(match []int) []string {
+	groups := make([]string, len(match)/2)
+	for i := range groups {
+		if match[2*i] >= 0 {
+			groups[i] = q.text[match[2*i]:match[2*i+1]]
+		}
+	}
+	q.text = q.text[:match[0]] + " " + q.text[match[1]:]
+	return groups
+}
+
+// guess records an assumption the interpretation relies on.
+func (q *quickParser) guess(penalty float64, note string) {
+	q.confidence -= penalty
+	q.notes = append(q.notes, note)
+}
+
+// ParseQuickEvent reads an event from text relative to now in loc. The text names the
+// event and when it takes place: a date, a weekday or a relative day, a time or a range
+// of times and a duration. Texts with a date and no time describe all-day events.
+func ParseQuickEvent(text string, loc *time.Location, now time.Time) (QuickInterpretation, error) {
+	if err := Validate(QuickAdd{Text: text}, "validator : invalid data format"); err != nil {
+		return QuickInterpretation{}, err
+	}
+	q := &quickParser{text: text, loc: loc, now: now.In(loc), confidence: 1}
+	today := time.Date(q.now.Year(), q.now.Month(), q.now.Day(), 0, 0, 0, 0, loc)
+
+	// repeats and durations go first, so that their weekdays and numbers are not read as days and times
+	repeats := q.repeats()
+	length, hasLength := q.duration()
+	day, hasDate, err := q.date(today)
+	if err != nil {
+		return QuickInterpretation{}, err
+	}
+	start, end, hasStart, hasEnd, err := q.times()
+	if err != nil {
+		return QuickInterpretation{}, err
+	}
+	if !hasDate && len(repeats) == 0 && !hasStart {
+		return QuickInterpretation{}, NewValidationError("could not find when the event takes place",
+			FieldError{Field: "text", Message: "must name a day or a time, like tomorrow 13:00"})
+	}
+
+	name := quickSpaces.ReplaceAllString(q.text, " ")
+	for {
+		trimmed := strings.Trim(quickConnector.ReplaceAllString(name, ""), " ,.;:-–")
+		if trimmed == name {
+			break
+		}
+		name = trimmed
+	}
+	if name == "" {
+		return QuickInterpretation{}, NewValidationError("could not find the name of the event",
+			FieldError{Field: "text", Message: "must name the event, like Lunch with Ana"})
+	}
+
+	interpretation := QuickInterpretation{Text: text}
+	if len(repeats) > 0 {
+		for _, weekday := range repeats {
+			interpretation.RepeatsOn = append(interpretation.RepeatsOn, Weekdays[weekday])
+		}
+		q.guess(0.2, "repeating events are not supported, only the first occurrence is read")
+		if !hasDate {
+			day = firstOccurrence(today, repeats, start, hasStart, q.now)
+			hasDate = true
+		}
+	}
+
+	if !hasStart {
+		if hasLength {
+			q.guess(0.2, "a duration without a start time is ignored")
+		}
+		interpretation.Event = EventCreation{Name: name, Start: Date(day), End: Date(day).AddDate(0, 0, 1), AllDay: true}
+		interpretation.Confidence, interpretation.Notes = q.result()
+		return interpretation, nil
+	}
+
+	if !hasDate {
+		day = today
+		if onDay(today, start, loc).Before(q.now) {
+			day = today.AddDate(0, 0, 1)
+			q.guess(0.1, "no day given, the time has passed today so tomorrow is assumed")
+		}
+	}
+	startsAt := onDay(day, start, loc)
+	endsAt := onDay(day, end, loc)
+	switch {
+	case hasEnd && hasLength:
+		q.guess(0.1, "both an end and a duration are given, the end is used")
+	case hasLength:
+		endsAt = startsAt.Add(length)
+	case !hasEnd:
+		endsAt = startsAt.Add(quickDefaultLength)
+		q.guess(0.15, "no end given, the event is assumed to last an hour")
+	}
+	if hasEnd && !endsAt.After(startsAt) {
+		// ranges like 23:00-01:00 end on the next day
+		endsAt = onDay(day.AddDate(0, 0, 1), end, loc)
+	}
+	interpretation.Event = EventCreation{Name: name, Start: startsAt, End: endsAt}
+	interpretation.Confidence, interpretation.Notes = q.result()
+	return interpretation, nil
+}
+
+func (q *quickParser) result() (float64, []string) {
+	return math.Round(math.Max(q.confidence, 0)*100) / 100, q.notes
+}
+
+// date takes the day of the event out of the text, days without a year are the next ones to come.
+func (q *quickParser) date(today time.Time) (time.Time, bool, error) {
+	if groups := q.take(quickISODate); groups != nil {
+		year, _ := strconv.Atoi(groups[1])
+		month, _ := strconv.Atoi(groups[2])
+		day, _ := strconv.Atoi(groups[3])
+		return q.calendarDate(year, time.Month(month), day, true)
+	}
+	if groups := q.take(quickMonthDay); groups != nil {
+		day, _ := strconv.Atoi(groups[2])
+		year, hasYear := q.year(groups[3], today)
+		return q.calendarDate(year, monthOf(groups[1]), day, hasYear)
+	}
+	if groups := q.take(quickDayMonth); groups != nil {
+		day, _ := strconv.Atoi(groups[1])
+		year, hasYear := q.year(groups[3], today)
+		return q.calendarDate(year, monthOf(groups[2]), day, hasYear)
+	}
+	if groups := q.take(quickRelative); groups != nil {
+		switch {
+		case groups[1] != "":
+			return today.AddDate(0, 0, 2), true, nil
+		case groups[2] != "":
+			return today.AddDate(0, 0, 1), true, nil
+		case groups[3] != "":
+			return today, true, nil
+		}
+		n, _ := strconv.Atoi(groups[4])
+		if strings.HasPrefix(strings.ToLower(groups[5]), "week") {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), true, nil
+	}
+	if groups := q.take(quickOnWeekday); groups != nil {
+		weekday, _ := ParseWeekday(groups[2])
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 && !strings.EqualFold(groups[1], "this") {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), true, nil
+	}
+	return today, false, nil
+}
+
+func (q *quickParser) year(value string, today time.Time) (int, bool) {
+	if value == "" {
+		return today.Year(), false
+	}
+	year, _ := strconv.Atoi(value)
+	return year, true
+}
+
+// calendarDate checks a date of the text, dates without a year that have passed are taken in the next year.
+func (q *quickParser) calendarDate(year int, month time.Month, day int, hasYear bool) (time.Time, bool, error) {
+	date := time.Date(year, month, day, 0, 0, 0, 0, q.loc)
+	if date.Day() != day || date.Month() != month {
+		return time.Time{}, false, NewValidationError("invalid date",
+			FieldError{Field: "text", Message: "names a day the month does not have"})
+	}
+	if !hasYear && date.Before(time.Date(q.now.Year(), q.now.Month(), q.now.Day(), 0, 0, 0, 0, q.loc)) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, true, nil
+}
+
+func monthOf(name string) time.Month {
+	prefix := strings.ToLower(name[:3])
+	for month := time.January; month <= time.December; month++ {
+		if strings.HasPrefix(strings.ToLower(month.String()), prefix) {
+			return month
+		}
+	}
+	return time.January
+}
+
+// repeats takes the weekdays an event repeats on out of the text.
+func (q *quickParser) repeats() []time.Weekday {
+	groups := q.take(quickRepeat)
+	if groups == nil {
+		return nil
+	}
+	every := strings.ToLower(groups[1])
+	switch {
+	case every == "day" || groups[5] != "":
+		return []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	case every == "weekday" || groups[6] != "":
+		return []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	case strings.HasPrefix(every, "weekend"):
+		return []time.Weekday{time.Sunday, time.Saturday}
+	}
+	var weekdays []time.Weekday
+	seen := make(map[time.Weekday]bool)
+	for _, name := range quickWeekdays.FindAllString(groups[0], -1) {
+		weekday, _ := ParseWeekday(name)
+		if !seen[weekday] {
+			seen[weekday] = true
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	return weekdays
+}
+
+// times takes the start and the end of the event out of the text as minutes since midnight.
+func (q *quickParser) times() (start int, end int, hasStart bool, hasEnd bool, err error) {
+	if groups := q.take(quickRange); groups != nil {
+		first, second := strings.ToLower(groups[2]), strings.ToLower(groups[3])
+		if groups[1] == "" && !strings.ContainsAny(first+second, ":apm") {
+			q.guess(0.1, "the range "+groups[2]+"-"+groups[3]+" is read as times of day")
+		}
+		// 9-11am is read as 9am-11am, 11-1pm as 11am-1pm
+		suffix := strings.TrimSpace(second[len(strings.TrimRight(second, "apm")):])
+		if suffix != "" && !strings.HasSuffix(first, "am") && !strings.HasSuffix(first, "pm") {
+			from, fromOk := clockOf(first + suffix)
+			to, _ := clockOf(second)
+			if fromOk && from < to {
+				first += suffix
+			}
+		}
+		start, ok := clockOf(first)
+		if !ok {
+			return 0, 0, false, false, invalidQuickTime(groups[2])
+		}
+		end, ok := clockOf(second)
+		if !ok {
+			return 0, 0, false, false, invalidQuickTime(groups[3])
+		}
+		return start, end, true, true, nil
+	}
+	if groups := q.take(quickNamedTime); groups != nil {
+		if strings.EqualFold(groups[1], "noon") {
+			return 12 * 60, 0, true, false, nil
+		}
+		return 0, 0, true, false, nil
+	}
+	// bare numbers are only times after at, like at 9
+	for _, index := range quickAt.FindAllStringSubmatchIndex(q.text, -1) {
+		if index[2] < 0 && index[6] < 0 && index[8] < 0 {
+			continue
+		}
+		match := q.cut(index)
+		clock := match[2]
+		if match[3] != "" {
+			clock += ":" + match[3]
+		}
+		start, ok := clockOf(clock + strings.ToLower(match[4]))
+		if !ok {
+			return 0, 0, false, false, invalidQuickTime(match[0])
+		}
+		if match[3] == "" && match[4] == "" && start >= 60 && start < 8*60 {
+			start += 12 * 60
+			q.guess(0.1, "at "+match[2]+" is read as "+FormatClock(start))
+		}
+		return start, 0, true, false, nil
+	}
+	return 0, 0, false, false, nil
+}
+
+func invalidQuickTime(value string) error {
+	return NewValidationError("invalid time ["+strings.TrimSpace(value)+"]",
+		FieldError{Field: "text", Message: "must name times like 9:30, 13:00 or 2pm"})
+}
+
+// clockOf reads a time of day like 9, 9:30, 9am or 21:30 as minutes since midnight.
+func clockOf(value string) (int, bool) {
+	value = strings.ReplaceAll(strings.ToLower(value), " ", "")
+	suffix := ""
+	if strings.HasSuffix(value, "am") || strings.HasSuffix(value, "pm") {
+		value, suffix = value[:len(value)-2], value[len(value)-2:]
+	}
+	hour, minute := value, "0"
+	if i := strings.Index(value, ":"); i >= 0 {
+		hour, minute = value[:i], value[i+1:]
+	}
+	h, err := strconv.Atoi(hour)
+	if err != nil {
+		return 0, false
+	}
+	m, err := strconv.Atoi(minute)
+	if err != nil || m > 59 {
+		return 0, false
+	}
+	switch suffix {
+	case "am", "pm":
+		if h < 1 || h > 12 {
+			return 0, false
+		}
+		h %= 12
+		if suffix == "pm" {
+			h += 12
+		}
+	default:
+		if h > 24 || h == 24 && m != 0 {
+			return 0, false
+		}
+	}
+	return h*60 + m, true
+}
+
+// duration takes the length of the event out of the text.
+func (q *quickParser) duration() (time.Duration, bool) {
+	groups := q.take(quickDuration)
+	switch {
+	case groups == nil:
+		return 0, false
+	case groups[3] != "":
+		return time.Hour, true
+	case groups[4] != "":
+		return 30 * time.Minute, true
+	}
+	amount, _ := strconv.ParseFloat(groups[1], 64)
+	unit := time.Minute
+	if strings.HasPrefix(strings.ToLower(groups[2]), "h") {
+		unit = time.Hour
+	}
+	return time.Duration(amount * float64(unit)).Round(time.Minute), true
+}
+
+// firstOccurrence returns the first day from today on that is one of weekdays, today
+// only when the event has not started yet.
+func firstOccurrence(today time.Time, weekdays []time.Weekday, start int, hasStart bool, now time.Time) time.Time {
+	for i := 0; i < 8; i++ {
+		day := today.AddDate(0, 0, i)
+		for _, weekday := range weekdays {
+			if day.Weekday() != weekday {
+				continue
+			}
+			if i == 0 && hasStart && onDay(day, start, today.Location()).Before(now) {
+				continue
+			}
+			return day
+		}
+	}
+	return today
+}
+
+// onDay returns the time minutes after the midnight of day.
+func onDay(day time.Time, minutes int, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, loc)
+}
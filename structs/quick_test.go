@@ -0,0 +1,110 @@
+package structs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuickEvent(t *testing.T) {
+	kyiv, _ := time.LoadLocation("Europe/Kiev")
+	// a monday morning
+	now := time.Date(2021, time.December, 13, 10, 0, 0, 0, kyiv)
+	at := func(month time.Month, day int, hour int, min int) time.Time {
+		return time.Date(2021, month, day, hour, min, 0, 0, kyiv)
+	}
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		text       string
+		name       string
+		start      time.Time
+		end        time.Time
+		allDay     bool
+		repeats    int
+		confidence float64
+		kind       ErrorKind
+	}{
+		"Relative day and range": {
+			text: "Lunch with Ana tomorrow 13:00-14:00", name: "Lunch with Ana",
+			start: at(12, 14, 13, 0), end: at(12, 14, 14, 0), confidence: 1,
+		},
+		"Repeating with a duration": {
+			text: "Standup every weekday at 9:30 for 15 minutes", name: "Standup",
+			start: at(12, 14, 9, 30), end: at(12, 14, 9, 45), repeats: 5, confidence: 0.8,
+		},
+		"Weekday and an hour of the afternoon": {
+			text: "Dentist on friday at 3pm", name: "Dentist",
+			start: at(12, 17, 15, 0), end: at(12, 17, 16, 0), confidence: 0.85,
+		},
+		"Date without a time": {
+			text: "Mom's birthday dec 24", name: "Mom's birthday",
+			start: date(2021, 12, 24), end: date(2021, 12, 25), allDay: true, confidence: 1,
+		},
+		"Passed date goes to the next year": {
+			text: "Party on the 5th of January", name: "Party",
+			start: date(2022, 1, 5), end: date(2022, 1, 6), allDay: true, confidence: 1,
+		},
+		"Range sharing the suffix": {
+			text: "Review 2021-12-20 9-11am", name: "Review",
+			start: at(12, 20, 9, 0), end: at(12, 20, 11, 0), confidence: 1,
+		},
+		"Bare hour is read in the afternoon": {
+			text: "Call Bob at 3", name: "Call Bob",
+			start: at(12, 13, 15, 0), end: at(12, 13, 16, 0), confidence: 0.75,
+		},
+		"Range past midnight": {
+			text: "Deploy 23:00-01:00 on friday", name: "Deploy",
+			start: at(12, 17, 23, 0), end: at(12, 18, 1, 0), confidence: 1,
+		},
+		"Passed time goes to tomorrow": {
+			text: "Sync at 9:00 for an hour", name: "Sync",
+			start: at(12, 14, 9, 0), end: at(12, 14, 10, 0), confidence: 0.9,
+		},
+		"Numbers in the name": {
+			text: "Decide 3 options tomorrow", name: "Decide 3 options",
+			start: date(2021, 12, 14), end: date(2021, 12, 15), allDay: true, confidence: 1,
+		},
+		"No time": {
+			text: "Lunch with Ana",
+			kind: KindValidation,
+		},
+		"No name": {
+			text: "tomorrow at 13:00",
+			kind: KindValidation,
+		},
+		"Invalid time": {
+			text: "Call 25:00-26:00",
+			kind: KindValidation,
+		},
+		"Invalid date": {
+			text: "Trip feb 30",
+			kind: KindValidation,
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			interpretation, err := ParseQuickEvent(test.text, kyiv, now)
+			if test.kind != "" {
+				if KindOf(err) != test.kind {
+					t.Errorf("expected an error of kind %v, got %v", test.kind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := interpretation.Event
+			if e.Name != test.name || e.AllDay != test.allDay || !e.Start.Equal(test.start) || !e.End.Equal(test.end) {
+				t.Errorf("expected %q from %v to %v, got %q from %v to %v", test.name, test.start, test.end, e.Name, e.Start, e.End)
+			}
+			if len(interpretation.RepeatsOn) != test.repeats {
+				t.Errorf("expected %d weekdays to repeat on, got %v", test.repeats, interpretation.RepeatsOn)
+			}
+			if interpretation.Confidence != test.confidence {
+				t.Errorf("expected confidence %v, got %v %v", test.confidence, interpretation.Confidence, interpretation.Notes)
+			}
+		})
+	}
+}
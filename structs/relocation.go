@@ -0,0 +1,51 @@
+package structs
+
+import "time"
+
+// RebaseScope tells which events keep their wall-clock times when their owner changes location.
+type RebaseScope string
+
+const (
+	RebaseNone     RebaseScope = "none"
+	RebaseFuture   RebaseScope = "future"
+	RebaseAll      RebaseScope = "all"
+	RebaseSelected RebaseScope = "selected"
+)
+
+// RebaseOptions describe a change of location. EventIDs are only used with RebaseSelected.
+// A dry run reports the events that would be rebased without changing anything.
+type RebaseOptions struct {
+	Scope    RebaseScope
+	EventIDs []int
+	DryRun   bool
+}
+
+// RebasedEvent shows the times of an event before and after its owner moved,
+// each in the location of the owner at that time, so the wall clocks match.
+type RebasedEvent struct {
+	Id           int       `json:"id"`
+	Name         string    `json:"name"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	RebasedStart time.Time `json:"rebased_start"`
+	RebasedEnd   time.Time `json:"rebased_end"`
+}
+
+type Relocation struct {
+	User     HashedInfo     `json:"-"`
+	Location string         `json:"location"`
+	Scope    RebaseScope    `json:"rebase"`
+	DryRun   bool           `json:"dry_run"`
+	Events   []RebasedEvent `json:"events"`
+}
+
+// RebaseEvent moves the times of e so that they read in to as they used to read in from.
+// Reminders follow the moved times, a reminder that can not be scheduled is an error.
+func RebaseEvent(e Event, from *time.Location, to *time.Location) (Event, error) {
+	e.Start = wallClock(e.Start.In(from), to)
+	e.End = wallClock(e.End.In(from), to)
+	if !e.Alert.IsZero() {
+		e.Alert = wallClock(e.Alert.In(from), to)
+	}
+	return ScheduleReminders(e, to)
+}
@@ -0,0 +1,145 @@
+package structs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	ReminderFromStart = "start"
+	ReminderFromEnd   = "end"
+
+	ChannelPush    = "push"
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
+// Reminder fires at an offset from the start or the end of its event, so it
+// follows the event when it is moved. Offsets are Go durations like -15m or
+// -1h30m, or whole days and weeks like -1d or -2w. Day offsets count calendar
+// days in the zone of the event and may name the time of day in At, e.g. 09:00.
+// RemindAt is computed, it is ignored when reminders are received.
+type Reminder struct {
+	RelativeTo string    `json:"relative_to" validate:"omitempty,oneof=start end"`
+	Offset     string    `json:"offset" validate:"required"`
+	At         string    `json:"at,omitempty"`
+	Channel    string    `json:"channel" validate:"omitempty,oneof=push email sms webhook"`
+	RemindAt   time.Time `json:"remind_at"`
+}
+
+// reminderOffset is a parsed Reminder.Offset, only one of its fields is used.
+type reminderOffset struct {
+	days     int
+	duration time.Duration
+}
+
+func parseReminderOffset(offset string) (reminderOffset, bool) {
+	if n := len(offset); n > 1 && (offset[n-1] == 'd' || offset[n-1] == 'w') {
+		count, err := strconv.Atoi(offset[:n-1])
+		if err != nil {
+			return reminderOffset{}, false
+		}
+		if offset[n-1] == 'w' {
+			count *= 7
+		}
+		return reminderOffset{days: count}, true
+	}
+	duration, err := time.ParseDuration(offset)
+	if err != nil {
+		return reminderOffset{}, false
+	}
+	return reminderOffset{duration: duration}, true
+}
+
+func reminderError(i int, field string, message string) error {
+	name := fmt.Sprintf("reminders[%d].%v", i, field)
+	return NewValidationError("invalid reminder", FieldError{Field: name, Message: message})
+}
+
+// ScheduleReminders fills in the defaults and the RemindAt time of every reminder
+// of e. Events without time zones of their own are in loc, like their owner.
+func ScheduleReminders(e Event, loc *time.Location) (Event, error) {
+	if len(e.Reminders) == 0 {
+		return e, nil
+	}
+	scheduled := make([]Reminder, len(e.Reminders))
+	for i, r := range e.Reminders {
+		if r.RelativeTo == "" {
+			r.RelativeTo = ReminderFromStart
+		}
+		if r.Channel == "" {
+			r.Channel = ChannelPush
+		}
+		offset, ok := parseReminderOffset(r.Offset)
+		if !ok {
+			return Event{}, reminderError(i, "offset", "must be a duration like -15m or a number of days or weeks like -1d")
+		}
+
+		anchor, zone, err := reminderAnchor(e, r.RelativeTo, loc)
+		if err != nil {
+			return Event{}, err
+		}
+		if offset.duration != 0 || (offset.days == 0 && r.At == "") {
+			if r.At != "" {
+				return Event{}, reminderError(i, "at", "can only be used with an offset in days or weeks")
+			}
+			r.RemindAt = anchor.Add(offset.duration).UTC()
+		} else {
+			local := anchor.In(zone)
+			hour, minute, second := local.Clock()
+			if r.At != "" {
+				at, err := time.Parse("15:04", r.At)
+				if err != nil {
+					return Event{}, reminderError(i, "at", "must be a time of day like 09:00")
+				}
+				hour, minute, second = at.Hour(), at.Minute(), 0
+			}
+			wall := time.Date(local.Year(), local.Month(), local.Day()+offset.days, hour, minute, second, 0, time.UTC)
+			r.RemindAt = wallClock(wall, zone)
+		}
+		scheduled[i] = r
+	}
+	e.Reminders = scheduled
+	return e, nil
+}
+
+// reminderAnchor returns the time a reminder is relative to and the zone its days are counted in.
+func reminderAnchor(e Event, relativeTo string, loc *time.Location) (time.Time, *time.Location, error) {
+	if e.AllDay {
+		date := e.Start
+		if relativeTo == ReminderFromEnd {
+			date = e.End
+		}
+		return wallClock(date, loc), loc, nil
+	}
+	anchor, name, field := e.Start, e.StartTZ, "start_tz"
+	if relativeTo == ReminderFromEnd {
+		anchor, name, field = e.End, e.EndTZ, "end_tz"
+	}
+	zone, err := eventZone(field, name, loc)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return anchor, zone, nil
+}
+
+// FirstReminder returns the earliest time a reminder of e fires at.
+func FirstReminder(e Event) (time.Time, bool) {
+	var first time.Time
+	for _, r := range e.Reminders {
+		if first.IsZero() || r.RemindAt.Before(first) {
+			first = r.RemindAt
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// CopyReminders returns a copy of reminders, so that stored events do not share them with callers.
+func CopyReminders(reminders []Reminder) []Reminder {
+	if reminders == nil {
+		return nil
+	}
+	return append([]Reminder(nil), reminders...)
+}
@@ -0,0 +1,311 @@
+package structs
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Groupings of time reports.
+const (
+	ReportByDay  = "day"
+	ReportByWeek = "week"
+	ReportByName = "name"
+	ReportByTag  = "tag"
+)
+
+// Kinds of totals repositories compute for a time report.
+const (
+	TotalOfGroup = "group"
+	TotalOfDay   = "day"
+	TotalOfAll   = "all"
+)
+
+const (
+	// MaxReportDays is the longest window a time report covers.
+	MaxReportDays = 92
+	// Untagged is the group of events without tags in reports grouped by tag.
+	Untagged = "(untagged)"
+	// busiestDays is how many days a report names as the busiest ones.
+	busiestDays = 3
+	// TagPattern finds the hashtags events are tagged with in their names and descriptions.
+	// It is both a Go and a Postgres regular expression, and keeps to ASCII letters and
+	// digits, which Postgres matches and lowers the same way whatever the locale.
+	TagPattern = `#([A-Za-z0-9_-]+)`
+)
+
+var tagPattern = regexp.MustCompile(TagPattern)
+
+// ReportParams selects the timed events of Owner a time report is made of. From
+// and To are local midnights in Location, To is excluded. Name is a case-insensitive
+// pattern names have to match, * stands for any text.
+type ReportParams struct {
+	Owner     string
+	From      time.Time
+	To        time.Time
+	GroupBy   string
+	Name      string
+	WeekStart time.Weekday
+	Location  *time.Location
+}
+
+// TimeReport tells how much time of a window is scheduled. Time taken by several
+// events at once is counted once, in the totals as well as within every group.
+type TimeReport struct {
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	GroupBy        string        `json:"group_by"`
+	Timezone       string        `json:"timezone"`
+	Hours          float64       `json:"hours"`
+	Events         int           `json:"events"`
+	AverageMinutes float64       `json:"average_minutes"`
+	Groups         []ReportGroup `json:"groups"`
+	Heatmap        []HeatmapDay  `json:"heatmap"`
+	BusiestDays    []string      `json:"busiest_days"`
+}
+
+type ReportGroup struct {
+	Key    string  `json:"key"`
+	Hours  float64 `json:"hours"`
+	Events int     `json:"events"`
+}
+
+// HeatmapDay is the scheduled time of a day of the window. Level grades it from
+// 0 for a free day to 4 for the busiest days.
+type HeatmapDay struct {
+	Date    string  `json:"date"`
+	Weekday string  `json:"weekday"`
+	Hours   float64 `json:"hours"`
+	Level   int     `json:"level"`
+}
+
+// ReportTotal is the time events take under one key of a report, overlaps counted once.
+// Kind is TotalOfGroup, TotalOfDay with the date as the key, or TotalOfAll.
+type ReportTotal struct {
+	Kind    string
+	Key     string
+	Seconds float64
+	Events  int
+}
+
+// NewReportParams reads the window of a report, the dates are read in loc and to is
+// included. Without dates the report covers the week of now, weeks begin on weekStart.
+func NewReportParams(from string, to string, groupBy string, name string, weekStart time.Weekday,
+	loc *time.Location, now time.Time) (ReportParams, error) {
+	local := now.In(loc)
+	p := ReportParams{GroupBy: groupBy, Name: name, WeekStart: weekStart, Location: loc}
+	p.From = weekStartOf(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc), weekStart)
+	if p.GroupBy == "" {
+		p.GroupBy = ReportByDay
+	}
+	switch p.GroupBy {
+	case ReportByDay, ReportByWeek, ReportByName, ReportByTag:
+	default:
+		return ReportParams{}, NewValidationError("unknown grouping ["+groupBy+"]",
+			FieldError{Field: "group_by", Message: "must be one of day, week, name, tag"})
+	}
+	if from != "" {
+		date, err := time.ParseInLocation(DateLayout, from, loc)
+		if err != nil {
+			return ReportParams{}, NewValidationError("invalid date", FieldError{Field: "from", Message: "must be a date like 2021-12-24"})
+		}
+		p.From = date
+	}
+	p.To = p.From.AddDate(0, 0, 7)
+	if to != "" {
+		date, err := time.ParseInLocation(DateLayout, to, loc)
+		if err != nil {
+			return ReportParams{}, NewValidationError("invalid date", FieldError{Field: "to", Message: "must be a date like 2021-12-24"})
+		}
+		p.To = date.AddDate(0, 0, 1)
+	}
+	if !p.To.After(p.From) {
+		return ReportParams{}, NewValidationError("invalid dates", FieldError{Field: "to", Message: "must not be before from"})
+	}
+	if daysBetween(p.From, p.To) > MaxReportDays {
+		return ReportParams{}, NewValidationError("invalid dates", FieldError{Field: "to", Message: "must be at most 92 days after from"})
+	}
+	return p, nil
+}
+
+// NamePattern turns the name filter of p into a LIKE pattern with \ as the escape character.
+func (p ReportParams) NamePattern() string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(p.Name)
+	return strings.ReplaceAll(escaped, "*", "%")
+}
+
+// suitsName reports whether name matches the name filter of p.
+func (p ReportParams) suitsName(name string) bool {
+	if p.Name == "" {
+		return true
+	}
+	parts := strings.Split(p.Name, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	matched, _ := regexp.MatchString("(?is)^"+strings.Join(parts, ".*")+"$", name)
+	return matched
+}
+
+// EventTags returns the hashtags in the name and the description of e, in lower case.
+func EventTags(e Event) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, match := range tagPattern.FindAllStringSubmatch(e.Name+" "+e.Description, -1) {
+		tag := strings.ToLower(match[1])
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// SummarizeTime computes the totals of the events selected by p the way the database
+// does it, together with the average length of the events.
+func SummarizeTime(p ReportParams, events []Event) ([]ReportTotal, float64) {
+	type key struct{ kind, key string }
+	intervals := make(map[key][]Interval)
+	counted := make(map[key]map[int]bool)
+	var lengths float64
+	selected := 0
+
+	for _, e := range events {
+		if !e.BelongsTo(p.Owner) || e.AllDay || !e.Start.Before(p.To) || !e.End.After(p.From) || !p.suitsName(e.Name) {
+			continue
+		}
+		selected++
+		lengths += e.End.Sub(e.Start).Seconds()
+
+		clipped := e
+		if clipped.Start.Before(p.From) {
+			clipped.Start = p.From
+		}
+		if clipped.End.After(p.To) {
+			clipped.End = p.To
+		}
+		for _, piece := range splitByDay(clipped, p.Location) {
+			keys := []key{{TotalOfAll, ""}, {TotalOfDay, piece.Start.Format(DateLayout)}}
+			for _, group := range reportGroups(p, e, piece.Start) {
+				keys = append(keys, key{TotalOfGroup, group})
+			}
+			for _, k := range keys {
+				intervals[k] = append(intervals[k], Interval{Start: piece.Start, End: piece.End})
+				if counted[k] == nil {
+					counted[k] = make(map[int]bool)
+				}
+				counted[k][e.Id] = true
+			}
+		}
+	}
+
+	totals := make([]ReportTotal, 0, len(intervals))
+	for k, list := range intervals {
+		totals = append(totals, ReportTotal{Kind: k.kind, Key: k.key, Seconds: unionSeconds(list), Events: len(counted[k])})
+	}
+	if selected == 0 {
+		return totals, 0
+	}
+	return totals, lengths / float64(selected)
+}
+
+// reportGroups returns the groups of p the part of e on day belongs to.
+func reportGroups(p ReportParams, e Event, day time.Time) []string {
+	switch p.GroupBy {
+	case ReportByWeek:
+		return []string{weekStartOf(day, p.WeekStart).Format(DateLayout)}
+	case ReportByName:
+		return []string{e.Name}
+	case ReportByTag:
+		if tags := EventTags(e); len(tags) > 0 {
+			return tags
+		}
+		return []string{Untagged}
+	default:
+		return []string{day.Format(DateLayout)}
+	}
+}
+
+// unionSeconds returns the seconds covered by at least one of intervals.
+func unionSeconds(intervals []Interval) float64 {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	var seconds float64
+	var reach time.Time
+	for i, interval := range intervals {
+		start := interval.Start
+		if i > 0 && start.Before(reach) {
+			start = reach
+		}
+		if interval.End.After(start) {
+			seconds += interval.End.Sub(start).Seconds()
+		}
+		if i == 0 || interval.End.After(reach) {
+			reach = interval.End
+		}
+	}
+	return seconds
+}
+
+// NewTimeReport lays out the totals computed by a repository for p.
+func NewTimeReport(p ReportParams, totals []ReportTotal, averageSeconds float64) TimeReport {
+	report := TimeReport{
+		From:           p.From.Format(DateLayout),
+		To:             p.To.AddDate(0, 0, -1).Format(DateLayout),
+		GroupBy:        p.GroupBy,
+		Timezone:       p.Location.String(),
+		AverageMinutes: math.Round(averageSeconds/60*100) / 100,
+		Groups:         make([]ReportGroup, 0),
+		Heatmap:        make([]HeatmapDay, 0, daysBetween(p.From, p.To)),
+		BusiestDays:    make([]string, 0, busiestDays),
+	}
+	days := make(map[string]float64)
+	for _, total := range totals {
+		switch total.Kind {
+		case TotalOfAll:
+			report.Hours, report.Events = hours(total.Seconds), total.Events
+		case TotalOfDay:
+			days[total.Key] = total.Seconds
+		case TotalOfGroup:
+			report.Groups = append(report.Groups, ReportGroup{Key: total.Key, Hours: hours(total.Seconds), Events: total.Events})
+		}
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if p.GroupBy != ReportByDay && p.GroupBy != ReportByWeek && a.Hours != b.Hours {
+			return a.Hours > b.Hours
+		}
+		return a.Key < b.Key
+	})
+
+	var busiest float64
+	for _, seconds := range days {
+		busiest = math.Max(busiest, seconds)
+	}
+	for d := p.From; d.Before(p.To); d = d.AddDate(0, 0, 1) {
+		date := d.Format(DateLayout)
+		day := HeatmapDay{Date: date, Weekday: Weekdays[d.Weekday()], Hours: hours(days[date])}
+		if days[date] > 0 {
+			day.Level = int(math.Ceil(days[date] / busiest * 4))
+		}
+		report.Heatmap = append(report.Heatmap, day)
+	}
+
+	ranked := make([]HeatmapDay, 0, len(report.Heatmap))
+	for _, day := range report.Heatmap {
+		if day.Level > 0 {
+			ranked = append(ranked, day)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Hours > ranked[j].Hours })
+	for i := 0; i < len(ranked) && i < busiestDays; i++ {
+		report.BusiestDays = append(report.BusiestDays, ranked[i].Date)
+	}
+	return report
+}
+
+// hours rounds seconds to hundredths of an hour.
+func hours(seconds float64) float64 {
+	return math.Round(seconds/36) / 100
+}
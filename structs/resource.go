@@ -0,0 +1,76 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ResourceKind tells rooms from the equipment that is booked together with them.
+type ResourceKind string
+
+const (
+	ResourceRoom      ResourceKind = "room"
+	ResourceEquipment ResourceKind = "equipment"
+)
+
+// Resource is something events book, so that two events never use it at the same time.
+// Capacity is the number of people a room seats, it is zero for equipment.
+type Resource struct {
+	Id       int          `json:"id"`
+	Name     string       `json:"name" validate:"required"`
+	Kind     ResourceKind `json:"kind" validate:"required,oneof=room equipment"`
+	Capacity int          `json:"capacity" validate:"min=0"`
+	Location string       `json:"location"`
+}
+
+// NormalizeResources returns the ids of the resources an event books in order,
+// each of them once.
+func NormalizeResources(ids []int) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	normalized := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for i, id := range ids {
+		if id <= 0 {
+			return nil, NewValidationError("invalid resource",
+				FieldError{Field: fmt.Sprintf("resources[%d]", i), Message: "must be the id of a resource"})
+		}
+		if !seen[id] {
+			seen[id] = true
+			normalized = append(normalized, id)
+		}
+	}
+	sort.Ints(normalized)
+	return normalized, nil
+}
+
+// BookingConflict returns the first resource booked by both e and other at
+// overlapping times. All-day events book their resources for whole UTC days.
+func BookingConflict(e Event, other Event) (int, bool) {
+	if e.Id == other.Id || !e.Start.Before(other.End) || !other.Start.Before(e.End) {
+		return 0, false
+	}
+	for _, id := range e.Resources {
+		for _, booked := range other.Resources {
+			if id == booked {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// BookingError reports that resource is already booked by another event.
+func BookingError(resource int) error {
+	message := "resource with id [" + fmt.Sprint(resource) + "] is already booked at that time"
+	return fmt.Errorf("%w : %v ", ErrBooked, message)
+}
+
+// CopyResources returns a copy of ids, so that stored events do not share them with callers.
+func CopyResources(ids []int) []int {
+	if ids == nil {
+		return nil
+	}
+	return append([]int(nil), ids...)
+}
@@ -0,0 +1,275 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// SlotStep is how far apart the starts of suggested slots are.
+	SlotStep = 15 * time.Minute
+	// MaxSearchWindow bounds the time searched for common free slots.
+	MaxSearchWindow = 31 * 24 * time.Hour
+	MaxAttendees    = 20
+
+	defaultSuggestions = 5
+	maxSuggestions     = 50
+)
+
+// SuggestionRequest asks for times a meeting of Duration could take place between
+// From and To. Durations are written like 30m or 1h30m. Buffer is the free time
+// kept around existing events, MinNotice the time from now before the first slot.
+type SuggestionRequest struct {
+	Users            []string  `json:"users" validate:"required,min=1,dive,required"`
+	Duration         string    `json:"duration" validate:"required"`
+	From             time.Time `json:"from" validate:"required"`
+	To               time.Time `json:"to" validate:"required"`
+	WorkingHoursOnly bool      `json:"working_hours_only"`
+	MinNotice        string    `json:"min_notice"`
+	Buffer           string    `json:"buffer"`
+	Limit            int       `json:"limit"`
+}
+
+// SuggestionQuery is a checked SuggestionRequest.
+type SuggestionQuery struct {
+	Users            []string
+	Duration         time.Duration
+	From             time.Time
+	To               time.Time
+	WorkingHoursOnly bool
+	MinNotice        time.Duration
+	Buffer           time.Duration
+	Limit            int
+}
+
+// Suggestion is a slot all attendees are free in, with the times each of them sees.
+type Suggestion struct {
+	Start     time.Time          `json:"start"`
+	End       time.Time          `json:"end"`
+	Attendees []AttendeeSchedule `json:"attendees"`
+}
+
+type AttendeeSchedule struct {
+	Username string    `json:"username"`
+	Timezone string    `json:"timezone"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+// Interval is a span of time, End is exclusive.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (i Interval) Overlaps(other Interval) bool {
+	return i.Start.Before(other.End) && other.Start.Before(i.End)
+}
+
+func (i Interval) Contains(other Interval) bool {
+	return !other.Start.Before(i.Start) && !other.End.After(i.End)
+}
+
+// NewSuggestionQuery checks a request and fills in its defaults.
+func NewSuggestionQuery(r SuggestionRequest) (SuggestionQuery, error) {
+	if err := Validate(r, "validator : invalid data format"); err != nil {
+		return SuggestionQuery{}, err
+	}
+	q := SuggestionQuery{From: r.From.UTC(), To: r.To.UTC(), WorkingHoursOnly: r.WorkingHoursOnly, Limit: r.Limit}
+	var fields []FieldError
+	seen := make(map[string]bool, len(r.Users))
+	for _, user := range r.Users {
+		if !seen[user] {
+			seen[user] = true
+			q.Users = append(q.Users, user)
+		}
+	}
+	if len(q.Users) > MaxAttendees {
+		fields = append(fields, FieldError{Field: "users", Message: fmt.Sprintf("must not name more than %d users", MaxAttendees)})
+	}
+
+	var ok bool
+	if q.Duration, ok = parsePositiveDuration(r.Duration); !ok || q.Duration == 0 || q.Duration > 24*time.Hour {
+		fields = append(fields, FieldError{Field: "duration", Message: "must be a duration like 30m of at most 24h"})
+	}
+	if q.MinNotice, ok = parsePositiveDuration(r.MinNotice); !ok {
+		fields = append(fields, FieldError{Field: "min_notice", Message: "must be a positive duration like 2h"})
+	}
+	if q.Buffer, ok = parsePositiveDuration(r.Buffer); !ok {
+		fields = append(fields, FieldError{Field: "buffer", Message: "must be a positive duration like 10m"})
+	}
+	if !q.To.After(q.From) {
+		fields = append(fields, FieldError{Field: "to", Message: "must be after from"})
+	} else if q.To.Sub(q.From) > MaxSearchWindow {
+		fields = append(fields, FieldError{Field: "to", Message: "must be at most 31 days after from"})
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultSuggestions
+	}
+	if q.Limit < 0 || q.Limit > maxSuggestions {
+		fields = append(fields, FieldError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxSuggestions)})
+	}
+	if len(fields) > 0 {
+		return SuggestionQuery{}, NewValidationError("invalid scheduling request", fields...)
+	}
+	return q, nil
+}
+
+func parsePositiveDuration(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, true
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d >= 0
+}
+
+// WorkingIntervals returns the working hours of a between from and to as times,
+// the hours of every date are read in loc.
+func (a Availability) WorkingIntervals(from time.Time, to time.Time, loc *time.Location) []Interval {
+	var intervals []Interval
+	first := from.In(loc)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	for ; wallClock(day, loc).Before(to); day = day.AddDate(0, 0, 1) {
+		for _, r := range a.RangesOn(day) {
+			start, end := r.Minutes()
+			interval := Interval{
+				Start: wallClock(day.Add(time.Duration(start)*time.Minute), loc),
+				End:   wallClock(day.Add(time.Duration(end)*time.Minute), loc),
+			}
+			if interval.End.After(from) && interval.Start.Before(to) {
+				intervals = append(intervals, interval)
+			}
+		}
+	}
+	return intervals
+}
+
+// Attendee is what the search needs to know about one user: when they are busy
+// and, when the search keeps to working hours, when they work.
+type Attendee struct {
+	Username string
+	Location *time.Location
+	Busy     []Interval
+	// Working is nil for users that did not set working hours, they can meet at any time
+	Working []Interval
+}
+
+// FindSlots returns up to q.Limit slots of q.Duration in which all attendees are
+// free, none of them overlapping another. Slots in the daytime of more attendees
+// come first, then earlier slots.
+func FindSlots(q SuggestionQuery, attendees []Attendee, now time.Time) []Suggestion {
+	start := q.From
+	if earliest := now.Add(q.MinNotice); start.Before(earliest) {
+		start = earliest
+	}
+	// slots start on the quarter hour
+	if rounded := start.Truncate(SlotStep); !rounded.Equal(start) {
+		start = rounded.Add(SlotStep)
+	}
+
+	type candidate struct {
+		slot    Interval
+		penalty time.Duration
+	}
+	var candidates []candidate
+	for t := start; !t.Add(q.Duration).After(q.To); t = t.Add(SlotStep) {
+		slot := Interval{Start: t, End: t.Add(q.Duration)}
+		free := true
+		var penalty time.Duration
+		for _, a := range attendees {
+			if !a.isFree(slot, q) {
+				free = false
+				break
+			}
+			penalty += offDaytime(slot, a.Location)
+		}
+		if free {
+			candidates = append(candidates, candidate{slot: slot, penalty: penalty})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].penalty < candidates[j].penalty })
+
+	suggestions := make([]Suggestion, 0, q.Limit)
+	var picked []Interval
+	for _, c := range candidates {
+		if len(suggestions) == q.Limit {
+			break
+		}
+		overlaps := false
+		for _, p := range picked {
+			if p.Overlaps(c.slot) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		picked = append(picked, c.slot)
+		suggestion := Suggestion{Start: c.slot.Start, End: c.slot.End}
+		for _, a := range attendees {
+			suggestion.Attendees = append(suggestion.Attendees, AttendeeSchedule{
+				Username: a.Username,
+				Timezone: a.Location.String(),
+				Start:    c.slot.Start.In(a.Location),
+				End:      c.slot.End.In(a.Location),
+			})
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+func (a Attendee) isFree(slot Interval, q SuggestionQuery) bool {
+	for _, busy := range a.Busy {
+		if busy.Overlaps(Interval{Start: slot.Start.Add(-q.Buffer), End: slot.End.Add(q.Buffer)}) {
+			return false
+		}
+	}
+	if !q.WorkingHoursOnly || a.Working == nil {
+		return true
+	}
+	for _, working := range a.Working {
+		if working.Contains(slot) {
+			return true
+		}
+	}
+	return false
+}
+
+// daytime is the part of the day meetings are most welcome in, in the location of each attendee.
+const (
+	daytimeStart = 9 * time.Hour
+	daytimeEnd   = 18 * time.Hour
+)
+
+// offDaytime is how much of slot falls outside the daytime of loc.
+func offDaytime(slot Interval, loc *time.Location) time.Duration {
+	local := slot.Start.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	from := local.Sub(midnight)
+	to := from + slot.End.Sub(slot.Start)
+	var off time.Duration
+	if from < daytimeStart {
+		off += minDuration(to, daytimeStart) - from
+	}
+	if to > daytimeEnd {
+		off += to - maxDuration(from, daytimeEnd)
+	}
+	return off
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,158 @@
+package structs
+
+import (
+	"time"
+)
+
+type TaskPriority string
+
+const (
+	TaskLow    TaskPriority = "low"
+	TaskNormal TaskPriority = "normal"
+	TaskHigh   TaskPriority = "high"
+)
+
+// TaskStatus is where a task is: open -> in_progress -> done, or cancelled at any point.
+type TaskStatus string
+
+const (
+	TaskOpen       TaskStatus = "open"
+	TaskInProgress TaskStatus = "in_progress"
+	TaskDone       TaskStatus = "done"
+	TaskCancelled  TaskStatus = "cancelled"
+)
+
+// Task is a to-do of a user that is due at a time, kept next to their events.
+// Due is read in the location of the user like the times of events. RemindBefore
+// sets an alert that long before Due, tasks that are finished are not reminded of.
+// CompletedAt is set by the service when the task is done.
+type Task struct {
+	Id           int          `json:"id"`
+	Title        string       `json:"title" validate:"required,max=255"`
+	Notes        string       `json:"notes" validate:"max=1024"`
+	Due          time.Time    `json:"due" validate:"required"`
+	Priority     TaskPriority `json:"priority" validate:"omitempty,oneof=low normal high"`
+	Status       TaskStatus   `json:"status" validate:"omitempty,oneof=open in_progress done cancelled"`
+	CompletedAt  time.Time    `json:"completed_at"`
+	RemindBefore string       `json:"remind_before,omitempty"`
+	Owner        string       `json:"-"`
+}
+
+// Finished tells whether there is nothing left to do for the task.
+func (t Task) Finished() bool {
+	return t.Status == TaskDone || t.Status == TaskCancelled
+}
+
+// Overdue tells whether the task is not finished though it was due before now.
+func (t Task) Overdue(now time.Time) bool {
+	return !t.Finished() && t.Due.Before(now)
+}
+
+// AlertAt returns the time the task is reminded of, zero for tasks without reminders.
+func (t Task) AlertAt() time.Time {
+	before, ok := parsePositiveDuration(t.RemindBefore)
+	if !ok || t.RemindBefore == "" || t.Finished() {
+		return time.Time{}
+	}
+	return t.Due.Add(-before)
+}
+
+// NormalizeTask checks a task sent by a client and fills in its defaults. Due is read
+// in loc, previous is the stored version of an updated task, nil for a new one.
+func NormalizeTask(t Task, previous *Task, loc *time.Location, now time.Time) (Task, error) {
+	if err := Validate(t, "validator : invalid data format"); err != nil {
+		return Task{}, err
+	}
+	due, err := localTime("due", t.Due, loc, DefaultDSTPolicy)
+	if err != nil {
+		return Task{}, err
+	}
+	t.Due = due
+	if t.RemindBefore != "" {
+		before, ok := parsePositiveDuration(t.RemindBefore)
+		if !ok || before%time.Minute != 0 || before > MaxSnooze {
+			return Task{}, NewValidationError("invalid reminder",
+				FieldError{Field: "remind_before", Message: "must be a duration of whole minutes like 30m of at most 7 days"})
+		}
+		t.RemindBefore = ShortDuration(before)
+		if before == 0 {
+			t.RemindBefore = ""
+		}
+	}
+	if t.Priority == "" {
+		t.Priority = TaskNormal
+	}
+	if t.Status == "" {
+		t.Status = TaskOpen
+	}
+
+	t.CompletedAt = time.Time{}
+	if t.Status == TaskDone {
+		t.CompletedAt = now
+		if previous != nil && previous.Status == TaskDone {
+			t.CompletedAt = previous.CompletedAt
+		}
+	}
+	return t, nil
+}
+
+// TaskParams selects tasks of Owner. DueFrom and DueBefore bound their due times
+// when set, Unfinished leaves out the tasks that are done or cancelled.
+type TaskParams struct {
+	Owner      string
+	Status     TaskStatus
+	DueFrom    time.Time
+	DueBefore  time.Time
+	Unfinished bool
+}
+
+// Task filters clients can list tasks by.
+const (
+	TasksOverdue  = "overdue"
+	TasksDueToday = "today"
+)
+
+// NewTaskParams reads the filters of a list of tasks. Overdue tasks are the
+// unfinished ones due before now, tasks due today are due on the date of now in loc.
+func NewTaskParams(owner string, status string, due string, loc *time.Location, now time.Time) (TaskParams, error) {
+	p := TaskParams{Owner: owner, Status: TaskStatus(status)}
+	switch p.Status {
+	case "", TaskOpen, TaskInProgress, TaskDone, TaskCancelled:
+	default:
+		return TaskParams{}, NewValidationError("unknown task status ["+status+"]",
+			FieldError{Field: "status", Message: "must be one of open, in_progress, done, cancelled"})
+	}
+	switch due {
+	case "":
+	case TasksOverdue:
+		p.DueBefore, p.Unfinished = now, true
+	case TasksDueToday:
+		local := now.In(loc)
+		p.DueFrom = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		p.DueBefore = p.DueFrom.AddDate(0, 0, 1)
+	default:
+		return TaskParams{}, NewValidationError("unknown due filter ["+due+"]",
+			FieldError{Field: "due", Message: "must be overdue or today"})
+	}
+	return p, nil
+}
+
+// SuitsTaskParams reports whether t is selected by p.
+func SuitsTaskParams(p TaskParams, t Task) bool {
+	if p.Owner != "" && t.Owner != p.Owner {
+		return false
+	}
+	if p.Status != "" && t.Status != p.Status {
+		return false
+	}
+	if p.Unfinished && t.Finished() {
+		return false
+	}
+	if !p.DueFrom.IsZero() && t.Due.Before(p.DueFrom) {
+		return false
+	}
+	if !p.DueBefore.IsZero() && !t.Due.Before(p.DueBefore) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package structs
+
+import "time"
+
+// Times of an event are written in the location of its owner, unless the event
+// names its own time zones. start_tz applies to the start and the alert, end_tz
+// to the end, so an event can begin and finish in different zones.
+
+// eventZone loads the zone called name, an empty name stands for fallback.
+func eventZone(field string, name string, fallback *time.Location) (*time.Location, error) {
+	if name == "" {
+		return fallback, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, NewValidationError("unknown time zone ["+name+"]",
+			FieldError{Field: field, Message: "must be an IANA time zone name"})
+	}
+	return loc, nil
+}
+
+// CreationOf returns the form e would be created from by a user in loc,
+// with times written in the zones of the event.
+func CreationOf(e Event, loc time.Location) EventCreation {
+	creation := EventCreation{
+		Name:        e.Name,
+		Start:       e.Start,
+		End:         e.End,
+		Description: e.Description,
+		Alert:       e.Alert,
+		AllDay:      e.AllDay,
+		StartTZ:     e.StartTZ,
+		EndTZ:       e.EndTZ,
+		Reminders:   CopyReminders(e.Reminders),
+		Resources:   CopyResources(e.Resources),
+	}
+	if e.AllDay {
+		if !e.Alert.IsZero() {
+			creation.Alert = e.Alert.In(&loc)
+		}
+		return creation
+	}
+	startLoc, err := eventZone("start_tz", e.StartTZ, &loc)
+	if err != nil {
+		startLoc = &loc
+	}
+	endLoc, err := eventZone("end_tz", e.EndTZ, &loc)
+	if err != nil {
+		endLoc = &loc
+	}
+	creation.Start = e.Start.In(startLoc)
+	creation.End = e.End.In(endLoc)
+	if !e.Alert.IsZero() {
+		creation.Alert = e.Alert.In(startLoc)
+	}
+	return creation
+}
@@ -0,0 +1,47 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// report fields by the names clients send them with
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// Validate checks the validate tags of s. A failure is a validation error
+// with message and one FieldError for every field that did not pass.
+func Validate(s interface{}, message string) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return WrapError(KindInternal, err)
+	}
+	fields := make([]FieldError, 0, len(invalid))
+	for _, f := range invalid {
+		// the namespace starts with the name of the struct, nested fields read like reminders[0].offset
+		field := f.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		fields = append(fields, FieldError{Field: field, Message: "failed on the '" + f.Tag() + "' rule"})
+	}
+	return NewValidationError(message, fields...)
+}
@@ -0,0 +1,233 @@
+package structs
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar views clients can ask for.
+const (
+	AgendaView = "agenda"
+	DayView    = "day"
+	WeekView   = "week"
+	MonthView  = "month"
+)
+
+const (
+	DefaultAgendaDays = 7
+	MaxAgendaDays     = 62
+)
+
+// ViewParams selects the local days of a view. Days are counted from First,
+// the midnight of the first day in the location of the user. Month is set for
+// month views, whose grid also shows the days of the weeks around the month.
+type ViewParams struct {
+	Kind      string
+	First     time.Time
+	Days      int
+	WeekStart time.Weekday
+	Month     time.Month
+	Owner     string
+	// Holidays is the country whose public holidays are shown with the events
+	Holidays string
+}
+
+// CalendarView is the list of events of a user bucketed by their local days.
+// Agenda views only list the days that have events.
+type CalendarView struct {
+	View      string    `json:"view"`
+	Start     string    `json:"start"`
+	End       string    `json:"end"`
+	WeekStart string    `json:"week_start"`
+	Timezone  string    `json:"timezone"`
+	Total     int       `json:"total"`
+	Days      []ViewDay `json:"days"`
+}
+
+// ViewDay holds the events taking place on Date, Outside marks the days of a month
+// grid that belong to the month before or after.
+type ViewDay struct {
+	Date    string      `json:"date"`
+	Weekday string      `json:"weekday"`
+	Outside bool        `json:"outside,omitempty"`
+	Count   int         `json:"count"`
+	Events  []ViewEntry `json:"events"`
+}
+
+// ViewEntry is the part of an event on one day. Continued tells that the event
+// started on a day before, Continues that it goes on the day after.
+type ViewEntry struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Continued bool      `json:"continued"`
+	Continues bool      `json:"continues"`
+	Event     Event     `json:"event"`
+}
+
+// ParseWeekday returns the weekday with name, in any case.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for day, weekday := range Weekdays {
+		if strings.EqualFold(name, weekday) {
+			return time.Weekday(day), true
+		}
+	}
+	return time.Sunday, false
+}
+
+// NewViewParams reads the days of a view around date, today in loc by default.
+// Weeks begin on weekStart, or on defaultWeekStart when it is empty. Agenda views
+// list days from date on.
+func NewViewParams(kind string, date string, days string, weekStart string, defaultWeekStart time.Weekday,
+	loc *time.Location, now time.Time) (ViewParams, error) {
+	p := ViewParams{Kind: kind, Days: 1, WeekStart: defaultWeekStart}
+	if weekStart != "" {
+		var ok bool
+		if p.WeekStart, ok = ParseWeekday(weekStart); !ok {
+			return ViewParams{}, NewValidationError("unknown week start ["+weekStart+"]",
+				FieldError{Field: "week_start", Message: "must be a weekday like monday"})
+		}
+	}
+	local := now.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if date != "" {
+		parsed, err := time.ParseInLocation(DateLayout, date, loc)
+		if err != nil {
+			return ViewParams{}, NewValidationError("invalid date", FieldError{Field: "date", Message: "must be a date like 2021-12-24"})
+		}
+		day = parsed
+	}
+
+	switch kind {
+	case DayView:
+		p.First = day
+	case WeekView:
+		p.First = weekStartOf(day, p.WeekStart)
+		p.Days = 7
+	case MonthView:
+		first := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+		next := first.AddDate(0, 1, 0)
+		p.First, p.Month = weekStartOf(first, p.WeekStart), first.Month()
+		p.Days = daysBetween(p.First, weekStartOf(next.AddDate(0, 0, 6), p.WeekStart))
+	case AgendaView:
+		p.First, p.Days = day, DefaultAgendaDays
+		if days != "" {
+			n, err := strconv.Atoi(days)
+			if err != nil || n < 1 || n > MaxAgendaDays {
+				return ViewParams{}, NewValidationError("invalid number of days",
+					FieldError{Field: "days", Message: "must be a number from 1 to " + strconv.Itoa(MaxAgendaDays)})
+			}
+			p.Days = n
+		}
+	default:
+		return ViewParams{}, NewValidationError("unknown view ["+kind+"]",
+			FieldError{Field: "view", Message: "must be one of agenda, day, week, month"})
+	}
+	return p, nil
+}
+
+// End returns the midnight after the last day of the view.
+func (p ViewParams) End() time.Time {
+	return p.First.AddDate(0, 0, p.Days)
+}
+
+// BuildView puts events on the days of p they take place on in loc. Events lasting
+// several days are split at midnight, all-day events fill their dates.
+func BuildView(p ViewParams, events []Event, loc *time.Location) CalendarView {
+	view := CalendarView{
+		View:      p.Kind,
+		Start:     p.First.Format(DateLayout),
+		End:       p.End().AddDate(0, 0, -1).Format(DateLayout),
+		WeekStart: Weekdays[p.WeekStart],
+		Timezone:  loc.String(),
+		Days:      make([]ViewDay, 0, p.Days),
+	}
+	days := make([]ViewDay, p.Days)
+	for i := range days {
+		d := p.First.AddDate(0, 0, i)
+		days[i] = ViewDay{
+			Date:    d.Format(DateLayout),
+			Weekday: Weekdays[d.Weekday()],
+			Outside: p.Month != 0 && d.Month() != p.Month,
+			Events:  make([]ViewEntry, 0),
+		}
+	}
+
+	for _, e := range events {
+		shown := false
+		for _, entry := range splitByDay(e, loc) {
+			index := daysBetween(p.First, entry.Start)
+			if index < 0 || index >= p.Days {
+				continue
+			}
+			days[index].Events = append(days[index].Events, entry)
+			shown = true
+		}
+		if shown {
+			view.Total++
+		}
+	}
+
+	for _, d := range days {
+		if p.Kind == AgendaView && len(d.Events) == 0 {
+			continue
+		}
+		sort.SliceStable(d.Events, func(i, j int) bool {
+			a, b := d.Events[i], d.Events[j]
+			if a.Event.AllDay != b.Event.AllDay {
+				return a.Event.AllDay
+			}
+			return a.Start.Before(b.Start)
+		})
+		d.Count = len(d.Events)
+		view.Days = append(view.Days, d)
+	}
+	return view
+}
+
+// splitByDay returns the parts of e on each local day it takes place on.
+func splitByDay(e Event, loc *time.Location) []ViewEntry {
+	var entries []ViewEntry
+	if e.AllDay {
+		first, last := CoveredDays(e)
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			start := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+			entries = append(entries, ViewEntry{
+				Start: start, End: start.AddDate(0, 0, 1),
+				Continued: d.After(first), Continues: d.Before(last),
+				Event: e,
+			})
+		}
+		return entries
+	}
+
+	start, end := e.Start.In(loc), e.End.In(loc)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	for {
+		next := day.AddDate(0, 0, 1)
+		entry := ViewEntry{Start: start, End: end, Continued: start.Before(day), Continues: end.After(next), Event: e}
+		if entry.Continued {
+			entry.Start = day
+		}
+		if entry.Continues {
+			entry.End = next
+		}
+		entries = append(entries, entry)
+		if !entry.Continues {
+			return entries
+		}
+		day = next
+	}
+}
+
+// weekStartOf returns the midnight the week of day begins at.
+func weekStartOf(day time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// daysBetween counts the calendar days from the date of from to the date of to.
+func daysBetween(from time.Time, to time.Time) int {
+	return int(Date(to).Sub(Date(from)).Hours() / 24)
+}